@@ -0,0 +1,13 @@
+package annotation
+
+// AnnotatedEntity is result of annotation query on Storage.
+type AnnotatedEntity struct {
+	Namespace *Namespace
+	File      *File
+	// Allowed types: *Type, *Func, *Const, *Var, *Field.
+	Entity interface{}
+	// Declaration of type, which contains *Field, nil for other entities.
+	Type *Type
+	// Annotations of entity with requested type.
+	Annotations []interface{}
+}
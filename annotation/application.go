@@ -1,29 +1,77 @@
 package annotation
 
+import (
+	"encoding/json"
+	"fmt"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
 type Application struct {
-	storage *Storage
+	config        *Config
+	storage       *Storage
+	typesInfo     *TypesInfo
+	typesImporter types.Importer
+	entityContext *EntityContext
+	diagnostics   *Diagnostics
+	regions       *Regions
+	changeSet     *ChangeSet
+	logger        Logger
 
-	annotationParser AnnotationParser
-	cloner           Cloner
-	storageCleaner   StorageCleaner
-	storageWriter    StorageWriter
-	importFetcher    ImportFetcher
-	importRenamer    ImportRenamer
-	importUniquer    ImportUniquer
-	equaler          Equaler
-	containsChecker  ContainsChecker
-	renderer         Renderer
-	scanner          Scanner
-	sourceParser     SourceParser
-	validator        Validator
+	annotationFinder     AnnotationFinder
+	annotationParser     AnnotationParser
+	astConverter         ASTConverter
+	cloner               Cloner
+	configLoader         ConfigLoader
+	storageCleaner       StorageCleaner
+	storageWriter        StorageWriter
+	importFetcher        ImportFetcher
+	importRenamer        ImportRenamer
+	importUniquer        ImportUniquer
+	implementationFinder ImplementationFinder
+	methodSetFetcher     MethodSetFetcher
+	namespaceResolver    NamespaceResolver
+	equaler              Equaler
+	fileConflictResolver FileConflictResolver
+	fileEditor           FileEditor
+	containsChecker      ContainsChecker
+	contextIndexer       ContextIndexer
+	renderer             Renderer
+	scanner              Scanner
+	selector             Selector
+	typesScanner         Scanner
+	sourceParser         SourceParser
+	transformer          Transformer
+	validator            Validator
+	walker               Walker
 
-	generators []Generator
+	generators        []Generator
+	generatorOptions  map[string]map[string]interface{}
+	enabledGenerators map[string]bool
+	isConcurrent      bool
+	isEditing         bool
+	isTypesChecking   bool
+	isLayoutKept      bool
+	maxPasses         int
 }
 
 func NewApplication() *Application {
 	return &Application{}
 }
 
+// Returns applied config or nil.
+func (a *Application) Config() *Config {
+	return a.config
+}
+
 func (a *Application) Storage() *Storage {
 	if a.storage == nil {
 		a.storage = &Storage{}
@@ -32,6 +80,87 @@ func (a *Application) Storage() *Storage {
 	return a.storage
 }
 
+func (a *Application) TypesInfo() *TypesInfo {
+	if a.typesInfo == nil {
+		a.typesInfo = NewTypesInfo()
+	}
+
+	return a.typesInfo
+}
+
+// Returns "gc" importer of compiled export data, it's shared by types scanner and namespace resolver, so they use
+// the same package instances.
+func (a *Application) TypesImporter() types.Importer {
+	if a.typesImporter == nil {
+		a.typesImporter = importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup(""))
+	}
+
+	return a.typesImporter
+}
+
+// Returns parents of storage entities, it's rebuilt after scan and before each generator.
+func (a *Application) EntityContext() *EntityContext {
+	if a.entityContext == nil {
+		a.entityContext = NewEntityContext()
+	}
+
+	return a.entityContext
+}
+
+// Returns diagnostics, which were reported by generators during last generation.
+func (a *Application) Diagnostics() *Diagnostics {
+	if a.diagnostics == nil {
+		a.diagnostics = NewDiagnostics()
+	}
+
+	return a.diagnostics
+}
+
+// Returns regions of hand-written files, which were filled by generators during last generation.
+func (a *Application) Regions() *Regions {
+	if a.regions == nil {
+		a.regions = NewRegions()
+	}
+
+	return a.regions
+}
+
+// Returns files, which were written during last generation.
+func (a *Application) ChangeSet() *ChangeSet {
+	if a.changeSet == nil {
+		a.changeSet = NewChangeSet()
+	}
+
+	return a.changeSet
+}
+
+func (a *Application) Logger() Logger {
+	if a.logger == nil {
+		a.logger = NewTextLogger(os.Stderr)
+	}
+
+	return a.logger
+}
+
+func (a *Application) SetLogger(logger Logger) {
+	if logger == nil {
+		panic(errors.New("Variable 'logger' must be not nil"))
+	}
+
+	a.logger = logger
+}
+
+func (a *Application) AnnotationFinder() AnnotationFinder {
+	if a.annotationFinder == nil {
+		annotationFinder := NewEntityAnnotationFinder()
+		annotationFinder.SetContext(a.EntityContext())
+
+		a.annotationFinder = annotationFinder
+	}
+
+	return a.annotationFinder
+}
+
 func (a *Application) AnnotationParser() AnnotationParser {
 	if a.annotationParser == nil {
 		a.annotationParser = NewJSONAnnotationParser()
@@ -40,6 +169,14 @@ func (a *Application) AnnotationParser() AnnotationParser {
 	return a.annotationParser
 }
 
+func (a *Application) ASTConverter() ASTConverter {
+	if a.astConverter == nil {
+		a.astConverter = NewEntityASTConverter(a.Renderer(), NewGoSourceParser(a.AnnotationParser()))
+	}
+
+	return a.astConverter
+}
+
 func (a *Application) Cloner() Cloner {
 	if a.cloner == nil {
 		a.cloner = NewEntityCloner()
@@ -50,7 +187,11 @@ func (a *Application) Cloner() Cloner {
 
 func (a *Application) StorageCleaner() StorageCleaner {
 	if a.storageCleaner == nil {
-		a.storageCleaner = NewGeneratedFileCleaner()
+		storageCleaner := NewGeneratedFileCleaner()
+		storageCleaner.SetLogger(a.Logger())
+		storageCleaner.SetSourceParser(a.SourceParser())
+
+		a.storageCleaner = storageCleaner
 	}
 
 	return a.storageCleaner
@@ -58,15 +199,37 @@ func (a *Application) StorageCleaner() StorageCleaner {
 
 func (a *Application) StorageWriter() StorageWriter {
 	if a.storageWriter == nil {
-		a.storageWriter = NewGeneratedFileWriter(
+		storageWriter := NewGeneratedFileWriter(
 			a.Validator(),
 			a.Renderer(),
 		)
+		storageWriter.SetBeforeWrite(a.beforeWrite)
+		storageWriter.SetLogger(a.Logger())
+		storageWriter.SetRegions(a.Regions())
+		storageWriter.SetChangeSet(a.ChangeSet())
+
+		a.storageWriter = storageWriter
 	}
 
 	return a.storageWriter
 }
 
+func (a *Application) FileConflictResolver() FileConflictResolver {
+	if a.fileConflictResolver == nil {
+		a.fileConflictResolver = NewGeneratedFileConflictResolver(a.ImportRenamer())
+	}
+
+	return a.fileConflictResolver
+}
+
+func (a *Application) FileEditor() FileEditor {
+	if a.fileEditor == nil {
+		a.fileEditor = NewSourceFileEditor(a.SourceParser(), a.Renderer())
+	}
+
+	return a.fileEditor
+}
+
 func (a *Application) ImportFetcher() ImportFetcher {
 	if a.importFetcher == nil {
 		a.importFetcher = NewEntityImportFetcher(a.ImportUniquer())
@@ -91,6 +254,17 @@ func (a *Application) ImportUniquer() ImportUniquer {
 	return a.importUniquer
 }
 
+func (a *Application) NamespaceResolver() NamespaceResolver {
+	if a.namespaceResolver == nil {
+		namespaceResolver := NewExternalNamespaceResolver(a.TypesImporter(), NewTypesSpecBuilder(), a.ImportRenamer())
+		namespaceResolver.SetLogger(a.Logger())
+
+		a.namespaceResolver = namespaceResolver
+	}
+
+	return a.namespaceResolver
+}
+
 func (a *Application) Equaler() Equaler {
 	if a.equaler == nil {
 		a.equaler = NewEntityEqualer()
@@ -107,6 +281,46 @@ func (a *Application) ContainsChecker() ContainsChecker {
 	return a.containsChecker
 }
 
+func (a *Application) ConfigLoader() ConfigLoader {
+	if a.configLoader == nil {
+		a.configLoader = NewJSONConfigLoader()
+	}
+
+	return a.configLoader
+}
+
+func (a *Application) ContextIndexer() ContextIndexer {
+	if a.contextIndexer == nil {
+		a.contextIndexer = NewEntityContextIndexer(a.Walker())
+	}
+
+	return a.contextIndexer
+}
+
+func (a *Application) MethodSetFetcher() MethodSetFetcher {
+	if a.methodSetFetcher == nil {
+		a.methodSetFetcher = NewEntityMethodSetFetcher()
+	}
+
+	return a.methodSetFetcher
+}
+
+func (a *Application) ImplementationFinder() ImplementationFinder {
+	if a.implementationFinder == nil {
+		a.implementationFinder = NewEntityImplementationFinder(a.MethodSetFetcher())
+	}
+
+	return a.implementationFinder
+}
+
+func (a *Application) Selector() Selector {
+	if a.selector == nil {
+		a.selector = NewEntitySelector()
+	}
+
+	return a.selector
+}
+
 func (a *Application) Scanner() Scanner {
 	if a.scanner == nil {
 		a.scanner = NewGoScanner(a.SourceParser(), a.AnnotationParser())
@@ -115,6 +329,20 @@ func (a *Application) Scanner() Scanner {
 	return a.scanner
 }
 
+// Returns scanner, which fills TypesInfo by type checking of scanned namespaces.
+func (a *Application) TypesScanner() Scanner {
+	if a.typesScanner == nil {
+		a.typesScanner = NewGoTypesScanner(
+			a.Scanner(),
+			a.TypesInfo(),
+			a.TypesImporter(),
+			NewTypesSpecBuilder(),
+		)
+	}
+
+	return a.typesScanner
+}
+
 func (a *Application) Renderer() Renderer {
 	if a.renderer == nil {
 		a.renderer = NewEntityRenderer()
@@ -125,12 +353,23 @@ func (a *Application) Renderer() Renderer {
 
 func (a *Application) SourceParser() SourceParser {
 	if a.sourceParser == nil {
-		a.sourceParser = NewGoSourceParser(a.AnnotationParser())
+		sourceParser := NewGoSourceParser(a.AnnotationParser())
+		sourceParser.SetLayoutKept(a.isLayoutKept)
+
+		a.sourceParser = sourceParser
 	}
 
 	return a.sourceParser
 }
 
+func (a *Application) Transformer() Transformer {
+	if a.transformer == nil {
+		a.transformer = NewEntityTransformer(a.Validator())
+	}
+
+	return a.transformer
+}
+
 func (a *Application) Validator() Validator {
 	if a.validator == nil {
 		a.validator = NewEntityValidator()
@@ -139,8 +378,151 @@ func (a *Application) Validator() Validator {
 	return a.validator
 }
 
+func (a *Application) Walker() Walker {
+	if a.walker == nil {
+		a.walker = NewEntityWalker()
+	}
+
+	return a.walker
+}
+
 func (a *Application) Scan(rootNamespace string, rootPath string, ignores ...string) {
-	a.Scanner().Scan(a.storage, rootNamespace, rootPath, ignores...)
+	a.activeScanner().Scan(a.storage, rootNamespace, rootPath, ignores...)
+	a.afterScan()
+}
+
+// Searches config file in folder and its parents and applies it. Returns false if config file is not found.
+func (a *Application) LoadConfig(folder string) bool {
+	path := a.ConfigLoader().Find(folder)
+
+	if path == "" {
+		return false
+	}
+
+	a.ApplyConfig(a.ConfigLoader().Load(path))
+
+	return true
+}
+
+// Applies config: enables generators, sets their options and build tags of scanner.
+// Generators must be registered before. Options of ConfigurableGenerator are decoded into its ConfigOptions too.
+func (a *Application) ApplyConfig(config *Config) {
+	if config == nil {
+		panic(errors.New("Variable 'config' must be not nil"))
+	}
+
+	registered := map[string]Generator{}
+
+	for _, generator := range a.generators {
+		registered[a.generatorName(generator)] = generator
+	}
+
+	var enabledGenerators map[string]bool
+
+	if len(config.Generators) > 0 {
+		enabledGenerators = map[string]bool{}
+
+		for _, name := range config.Generators {
+			if registered[name] == nil {
+				panic(errors.Errorf("Generator '%s' from config '%s' is not registered", name, config.Path))
+			}
+
+			enabledGenerators[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(config.Options))
+
+	for name := range config.Options {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		generator := registered[name]
+
+		if generator == nil {
+			panic(errors.Errorf("Generator '%s' from config '%s' is not registered", name, config.Path))
+		}
+
+		options := map[string]interface{}{}
+
+		if err := json.Unmarshal(config.Options[name], &options); err != nil {
+			panic(errors.Wrapf(err, "Can't parse options of generator '%s'", name))
+		}
+
+		if configurableGenerator, ok := generator.(ConfigurableGenerator); ok {
+			if err := json.Unmarshal(config.Options[name], configurableGenerator.ConfigOptions()); err != nil {
+				panic(errors.Wrapf(err, "Can't parse options of generator '%s'", name))
+			}
+		}
+
+		a.SetGeneratorOptions(name, options)
+	}
+
+	if storageCleaner, ok := a.StorageCleaner().(*GeneratedFileCleaner); ok && config.ModifiedFiles != "" {
+		storageCleaner.SetModifiedFilePolicy(config.ModifiedFiles)
+	}
+
+	if storageWriter, ok := a.StorageWriter().(*GeneratedFileWriter); ok && config.ModifiedFiles != "" {
+		storageWriter.SetModifiedFilePolicy(config.ModifiedFiles)
+	}
+
+	if resolver, ok := a.FileConflictResolver().(*GeneratedFileConflictResolver); ok && config.FileConflicts != "" {
+		resolver.SetPolicy(config.FileConflicts)
+	}
+
+	if config.EditHandWritten {
+		a.SetEditing(true)
+	}
+
+	if config.TypesChecking {
+		a.SetTypesChecking(true)
+	}
+
+	if config.KeepLayout {
+		a.SetLayoutKept(true)
+	}
+
+	if scanner, ok := a.Scanner().(*GoScanner); ok && config.BuildTags != nil {
+		scanner.SetBuildTags(config.BuildTags)
+	}
+
+	a.enabledGenerators = enabledGenerators
+	a.config = config
+}
+
+// Scans all roots of applied config.
+func (a *Application) ScanConfig() {
+	if a.config == nil {
+		panic(errors.New("Config is not applied"))
+	}
+
+	for _, root := range a.config.Roots {
+		a.activeScanner().Scan(a.Storage(), root.Namespace, root.Path, a.config.Ignores...)
+	}
+
+	a.afterScan()
+}
+
+// Returns TypesScanner if types checking is enabled, otherwise Scanner.
+func (a *Application) activeScanner() Scanner {
+	if a.isTypesChecking {
+		return a.TypesScanner()
+	}
+
+	return a.Scanner()
+}
+
+func (a *Application) afterScan() {
+	a.ContextIndexer().Index(a.Storage(), a.EntityContext())
+
+	for _, generator := range a.sortGenerators() {
+		if hook, ok := generator.(AfterScanGenerator); ok {
+			hook.AfterScan(a)
+		}
+	}
 }
 
 func (a *Application) RegisterGenerator(generator Generator) {
@@ -154,15 +536,759 @@ func (a *Application) RegisterGenerator(generator Generator) {
 		a.generators = []Generator{}
 	}
 
+	if namedGenerator, ok := generator.(NamedGenerator); ok {
+		for _, registered := range a.generators {
+			if registered, ok := registered.(NamedGenerator); ok && registered.Name() == namedGenerator.Name() {
+				panic(errors.Errorf("Generator with name '%s' is already registered", namedGenerator.Name()))
+			}
+		}
+	}
+
 	a.generators = append(a.generators, generator)
 }
 
+// Sets options of generator, they are available by GeneratorContext.
+func (a *Application) SetGeneratorOptions(name string, options map[string]interface{}) {
+	if name == "" {
+		panic(errors.New("Variable 'name' must be not empty"))
+	}
+
+	if options == nil {
+		panic(errors.New("Variable 'options' must be not nil"))
+	}
+
+	if a.generatorOptions == nil {
+		a.generatorOptions = map[string]map[string]interface{}{}
+	}
+
+	a.generatorOptions[name] = options
+}
+
+// Enables concurrent run of consecutive registered generators, which implement ConcurrentGenerator.
+// Only generators, which implement ContextGenerator, are run in parallel, files created by them are added to storage
+// after all of them are finished. Other concurrent generators are run one by one after that.
+func (a *Application) SetConcurrent(isConcurrent bool) {
+	a.isConcurrent = isConcurrent
+}
+
+// Enables editing of hand-written files: declarations, which were changed by generators, are rewritten in their
+// content and other content is kept. Edited files are reported in ChangeSet.
+func (a *Application) SetEditing(isEditing bool) {
+	a.isEditing = isEditing
+}
+
+// Enables keeping of layout of parsed files, so changed files are rendered with original comments, grouping and blank
+// lines of their declarations, see GoSourceParser.SetLayoutKept.
+func (a *Application) SetLayoutKept(isLayoutKept bool) {
+	a.isLayoutKept = isLayoutKept
+
+	if sourceParser, ok := a.sourceParser.(*GoSourceParser); ok {
+		sourceParser.SetLayoutKept(isLayoutKept)
+	}
+}
+
+// Enables type checking of namespaces, which are scanned by Scan and ScanConfig, type information of scanned
+// entities is stored in TypesInfo before AfterScan hooks are called.
+func (a *Application) SetTypesChecking(isTypesChecking bool) {
+	a.isTypesChecking = isTypesChecking
+}
+
+// Sets max count of generation passes. If it's greater than 1, generators are run again while previous pass adds
+// new entities with annotations of registered generators. Before next run generator's files and namespaces of
+// previous pass are removed, so generator sees output of other generators only. In multi-pass generation generators
+// could only create files, modification of existing files causes panic, because it would be applied on each pass.
+func (a *Application) SetMaxPasses(maxPasses int) {
+	if maxPasses < 1 {
+		panic(errors.Errorf("Variable 'maxPasses' must be greater than 0, actual value: '%d'", maxPasses))
+	}
+
+	a.maxPasses = maxPasses
+}
+
 func (a *Application) Generate() {
+	generators := a.sortGenerators()
+	owners := make([]string, len(generators))
+	headers := map[string]string{}
+
+	for i, generator := range generators {
+		owners[i] = a.generatorName(generator)
+
+		if headerGenerator, ok := generator.(HeaderGenerator); ok {
+			headers[owners[i]] = headerGenerator.Header()
+		}
+	}
+
+	if storageCleaner, ok := a.StorageCleaner().(*GeneratedFileCleaner); ok {
+		storageCleaner.SetOwners(owners)
+	}
+
+	if storageWriter, ok := a.StorageWriter().(*GeneratedFileWriter); ok {
+		storageWriter.SetOwners(owners)
+		storageWriter.SetHeaders(headers)
+
+		if a.isEditing {
+			storageWriter.SetEditor(a.FileEditor())
+		} else {
+			storageWriter.SetEditor(nil)
+		}
+	}
+
 	a.StorageCleaner().Clean(a.storage)
 
-	for _, generator := range a.generators {
-		generator.Generate(a)
+	batches := a.generatorBatches(generators)
+
+	for _, generator := range generators {
+		if hook, ok := generator.(BeforeGenerateGenerator); ok {
+			hook.BeforeGenerate(a)
+		}
+	}
+
+	generatedFiles := make([][]*File, len(batches))
+	generatedNamespaces := make([][]*Namespace, len(batches))
+	isMultiPass := a.maxPasses > 1
+
+	var annotated map[string]bool
+	var declarations map[*File]string
+
+	if isMultiPass {
+		annotated = a.annotatedEntities()
+		declarations = a.renderedDeclarations(generatedFiles)
+	}
+
+	for pass := 1; ; pass++ {
+		// Generators report the same diagnostics and fill the same regions on each pass
+		a.Diagnostics().Reset()
+		a.Regions().Reset()
+
+		for i, batch := range batches {
+			// Previous generators could modify storage directly
+			a.Storage().Reindex()
+
+			if isMultiPass {
+				for _, file := range generatedFiles[i] {
+					a.Storage().RemoveFile(file)
+				}
+
+				for _, namespace := range generatedNamespaces[i] {
+					a.Storage().RemoveNamespace(namespace)
+				}
+			}
+
+			files := a.storageFiles()
+			namespaces := a.storageNamespaces()
+
+			a.ContextIndexer().Index(a.Storage(), a.EntityContext())
+
+			if len(batch) == 1 {
+				a.runGenerator(batch[0])
+				generatedFiles[i] = a.addedFiles(files)
+				a.ownFiles(batch[0], generatedFiles[i])
+			} else {
+				for j, batchFiles := range a.generateConcurrently(batch) {
+					a.ownFiles(batch[j], batchFiles)
+				}
+
+				generatedFiles[i] = a.addedFiles(files)
+			}
+
+			generatedNamespaces[i] = a.addedNamespaces(namespaces)
+
+			if isMultiPass {
+				a.requireNotModifiedFiles(batch, declarations, generatedFiles)
+			}
+		}
+
+		if !isMultiPass {
+			break
+		}
+
+		current := a.annotatedEntities()
+
+		if !a.hasNewEntities(annotated, current) {
+			break
+		}
+
+		if pass == a.maxPasses {
+			panic(errors.Errorf("Generation is not stable after %d passes", pass))
+		}
+
+		annotated = current
 	}
 
+	a.resolveFileConflicts()
+	a.reportDiagnostics()
+	a.ChangeSet().Reset()
+
 	a.StorageWriter().Write(a.storage)
+
+	for _, generator := range generators {
+		if hook, ok := generator.(AfterWriteGenerator); ok {
+			hook.AfterWrite(a)
+		}
+	}
+}
+
+func (a *Application) runGenerator(generator Generator) {
+	if contextGenerator, ok := generator.(ContextGenerator); ok {
+		contextGenerator.GenerateWithContext(a.generatorContext(generator))
+	} else {
+		generator.Generate(a)
+	}
+}
+
+func (a *Application) generatorContext(generator Generator) *GeneratorContext {
+	name := a.generatorName(generator)
+	options := a.generatorOptions[name]
+
+	if options == nil {
+		options = map[string]interface{}{}
+	}
+
+	context := NewGeneratorContext(a, name, options, a.Logger().With("generator", name))
+	context.version = a.generatorVersion(generator)
+
+	return context
+}
+
+// Adds FileGeneratedByAnnotation to files, which were created by generator without GeneratorContext.
+func (a *Application) ownFiles(generator Generator, files []*File) {
+	for _, file := range files {
+		isOwned := false
+
+		for _, rawAnnotation := range file.Annotations {
+			if _, ok := rawAnnotation.(FileGeneratedByAnnotation); ok {
+				isOwned = true
+
+				break
+			}
+		}
+
+		if !isOwned && file.Content == "" {
+			file.Annotations = append(
+				file.Annotations,
+				FileGeneratedByAnnotation{Name: a.generatorName(generator), Version: a.generatorVersion(generator)},
+			)
+		}
+	}
+}
+
+// Reports unresolved file conflicts as errors of generators, which created conflicting files.
+func (a *Application) resolveFileConflicts() {
+	for _, conflict := range a.FileConflictResolver().Resolve(a.Storage()) {
+		a.Diagnostics().Add(
+			&Diagnostic{
+				Severity:  DiagnosticSeverityError,
+				Generator: fileOwnerName(conflict.Conflicting),
+				Message:   conflict.String(),
+				Namespace: conflict.Namespace,
+				File:      conflict.Conflicting,
+				Position:  token.Position{Filename: conflict.Path()},
+			},
+		)
+	}
+}
+
+// Logs all diagnostics and fails if any of them is error.
+func (a *Application) reportDiagnostics() {
+	for _, diagnostic := range a.Diagnostics().List() {
+		fields := []interface{}{"generator", diagnostic.Generator}
+
+		if diagnostic.Position.Filename != "" {
+			fields = append(fields, "position", diagnostic.Position.String())
+		}
+
+		if diagnostic.Severity == DiagnosticSeverityError {
+			a.Logger().Error(diagnostic.Message, fields...)
+		} else {
+			a.Logger().Warning(diagnostic.Message, fields...)
+		}
+	}
+
+	if err := a.Diagnostics().Err(); err != nil {
+		panic(err)
+	}
+}
+
+// Calls BeforeWrite hooks of generators in order of generation.
+func (a *Application) beforeWrite(namespace *Namespace, file *File, content string) string {
+	for _, generator := range a.sortGenerators() {
+		if hook, ok := generator.(BeforeWriteGenerator); ok {
+			content = hook.BeforeWrite(a, namespace, file, content)
+		}
+	}
+
+	return content
+}
+
+// Orders enabled generators by dependencies, otherwise order of registration is kept.
+func (a *Application) sortGenerators() []Generator {
+	generators := a.activeGenerators()
+	result := make([]Generator, 0, len(generators))
+	byName := map[string]int{}
+
+	for i, generator := range generators {
+		if namedGenerator, ok := generator.(NamedGenerator); ok {
+			byName[namedGenerator.Name()] = i
+		}
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	states := make([]int, len(generators))
+
+	var visit func(i int, path []int)
+
+	visit = func(i int, path []int) {
+		switch states[i] {
+		case visited:
+			return
+		case visiting:
+			names := []string{}
+
+			for j := len(path) - 1; j >= 0; j-- {
+				names = append([]string{a.generatorName(generators[path[j]])}, names...)
+
+				if path[j] == i {
+					break
+				}
+			}
+
+			names = append(names, a.generatorName(generators[i]))
+
+			panic(errors.Errorf("Generators have dependency cycle: '%s'", strings.Join(names, "' -> '")))
+		}
+
+		states[i] = visiting
+		path = append(path[:len(path):len(path)], i)
+
+		if dependentGenerator, ok := generators[i].(DependentGenerator); ok {
+			for _, name := range dependentGenerator.Dependencies() {
+				dependency, ok := byName[name]
+
+				if !ok {
+					panic(
+						errors.Errorf(
+							"Generator '%s' depends on unknown generator '%s'",
+							a.generatorName(generators[i]),
+							name,
+						),
+					)
+				}
+
+				visit(dependency, path)
+			}
+		}
+
+		states[i] = visited
+		result = append(result, generators[i])
+	}
+
+	for i := range generators {
+		visit(i, []int{})
+	}
+
+	return result
+}
+
+// Returns generators, which are enabled by config, in order of registration.
+func (a *Application) activeGenerators() []Generator {
+	if a.enabledGenerators == nil {
+		return a.generators
+	}
+
+	result := []Generator{}
+
+	for _, generator := range a.generators {
+		if a.enabledGenerators[a.generatorName(generator)] {
+			result = append(result, generator)
+		}
+	}
+
+	return result
+}
+
+func (a *Application) generatorName(generator Generator) string {
+	if namedGenerator, ok := generator.(NamedGenerator); ok {
+		return namedGenerator.Name()
+	}
+
+	return fmt.Sprintf("%T", generator)
+}
+
+func (a *Application) generatorVersion(generator Generator) string {
+	if versionedGenerator, ok := generator.(VersionedGenerator); ok {
+		return versionedGenerator.Version()
+	}
+
+	return ""
+}
+
+func (a *Application) storageNamespaces() map[*Namespace]bool {
+	result := map[*Namespace]bool{}
+
+	a.Storage().Read(func() {
+		for _, namespace := range a.storage.Namespaces {
+			result[namespace] = true
+		}
+	})
+
+	return result
+}
+
+func (a *Application) addedNamespaces(namespaces map[*Namespace]bool) []*Namespace {
+	result := []*Namespace{}
+
+	a.Storage().Read(func() {
+		for _, namespace := range a.storage.Namespaces {
+			if !namespaces[namespace] {
+				result = append(result, namespace)
+			}
+		}
+	})
+
+	return result
+}
+
+// Returns rendered declarations of files, which were not generated during current run.
+// Content of hand-written files is not rendered, so their declarations are compared instead.
+func (a *Application) renderedDeclarations(generatedFiles [][]*File) map[*File]string {
+	generated := map[*File]bool{}
+
+	for _, files := range generatedFiles {
+		for _, file := range files {
+			generated[file] = true
+		}
+	}
+
+	result := map[*File]string{}
+
+	a.Storage().Read(func() {
+		for _, namespace := range a.storage.Namespaces {
+			if namespace.IsIgnored || namespace.IsReadOnly {
+				continue
+			}
+
+			for _, file := range namespace.Files {
+				if generated[file] {
+					continue
+				}
+
+				declarations := []string{file.PackageName, file.Comment}
+
+				for _, element := range file.ImportGroups {
+					declarations = append(declarations, a.Renderer().Render(element))
+				}
+
+				for _, element := range file.ConstGroups {
+					declarations = append(declarations, a.Renderer().Render(element))
+				}
+
+				for _, element := range file.VarGroups {
+					declarations = append(declarations, a.Renderer().Render(element))
+				}
+
+				for _, element := range file.TypeGroups {
+					declarations = append(declarations, a.Renderer().Render(element))
+				}
+
+				for _, element := range file.Funcs {
+					declarations = append(declarations, a.Renderer().Render(element))
+				}
+
+				result[file] = strings.Join(declarations, "\n")
+			}
+		}
+	})
+
+	return result
+}
+
+// Panics if generators of batch modified, added or removed files, which existed before generation.
+func (a *Application) requireNotModifiedFiles(
+	batch []Generator,
+	declarations map[*File]string,
+	generatedFiles [][]*File,
+) {
+	if reflect.DeepEqual(declarations, a.renderedDeclarations(generatedFiles)) {
+		return
+	}
+
+	names := make([]string, len(batch))
+
+	for i, generator := range batch {
+		names[i] = a.generatorName(generator)
+	}
+
+	panic(
+		errors.Errorf(
+			"Generators '%s' modified existing files, only new files could be created in multi-pass generation",
+			strings.Join(names, "', '"),
+		),
+	)
+}
+
+func (a *Application) storageFiles() map[*File]bool {
+	result := map[*File]bool{}
+
+	a.Storage().Read(func() {
+		for _, namespace := range a.storage.Namespaces {
+			for _, file := range namespace.Files {
+				result[file] = true
+			}
+		}
+	})
+
+	return result
+}
+
+func (a *Application) addedFiles(files map[*File]bool) []*File {
+	result := []*File{}
+
+	a.Storage().Read(func() {
+		for _, namespace := range a.storage.Namespaces {
+			for _, file := range namespace.Files {
+				if !files[file] {
+					result = append(result, file)
+				}
+			}
+		}
+	})
+
+	return result
+}
+
+// Returns keys of entities with annotations of registered generators.
+func (a *Application) annotatedEntities() map[string]bool {
+	result := map[string]bool{}
+	storage := a.Storage()
+
+	for _, generator := range a.activeGenerators() {
+		for _, annotation := range generator.Annotations() {
+			entities := append(storage.FindTypesByAnnotation(annotation), storage.FindFuncsByAnnotation(annotation)...)
+			entities = append(entities, storage.FindConstsByAnnotation(annotation)...)
+			entities = append(entities, storage.FindVarsByAnnotation(annotation)...)
+			entities = append(entities, storage.FindFieldsByAnnotation(annotation)...)
+
+			for _, entity := range entities {
+				result[a.annotatedEntityKey(entity)] = true
+			}
+		}
+	}
+
+	return result
+}
+
+func (a *Application) annotatedEntityKey(entity *AnnotatedEntity) string {
+	key := entity.Namespace.Name + "/" + entity.File.Name + ":"
+
+	if entity.Type != nil {
+		key += entity.Type.Name + "."
+	}
+
+	switch element := entity.Entity.(type) {
+	case *Type:
+		return key + "Type " + element.Name
+	case *Func:
+		if element.Related != nil {
+			if spec, ok := element.Related.Spec.(*SimpleSpec); ok {
+				return key + "Method " + spec.TypeName + "." + element.Name
+			}
+		}
+
+		return key + "Func " + element.Name
+	case *Const:
+		return key + "Const " + element.Name
+	case *Var:
+		return key + "Var " + element.Name
+	case *Field:
+		return key + "Field " + element.Name
+	default:
+		panic(errors.Errorf("Can't build key of entity with type: '%T'", entity.Entity))
+	}
+}
+
+func (a *Application) hasNewEntities(previous map[string]bool, current map[string]bool) bool {
+	for key := range current {
+		if !previous[key] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Splits generators by batches keeping their order, each batch is one not concurrent generator or consecutive
+// concurrent generators.
+func (a *Application) generatorBatches(generators []Generator) [][]Generator {
+	result := [][]Generator{}
+	isPreviousConcurrent := false
+
+	for _, generator := range generators {
+		concurrentGenerator, isConcurrent := generator.(ConcurrentGenerator)
+		isConcurrent = a.isConcurrent && isConcurrent && concurrentGenerator.IsConcurrent()
+
+		if isConcurrent && isPreviousConcurrent {
+			result[len(result)-1] = append(result[len(result)-1], generator)
+		} else {
+			result = append(result, []Generator{generator})
+		}
+
+		isPreviousConcurrent = isConcurrent
+	}
+
+	return result
+}
+
+// Runs generators concurrently and returns files, which were created by each of them. Generators with
+// GeneratorContext are run concurrently, their files are added to storage after all of them are finished, so they
+// don't modify storage, while other generators read it. Other generators could modify storage directly, so they are
+// run one by one after that. Namespaces and files, which were added by generators, are sorted by name, so result
+// doesn't depend on scheduling. Panic of the first generator in order of registration is repeated.
+func (a *Application) generateConcurrently(generators []Generator) [][]*File {
+	a.initServices()
+
+	storage := a.Storage()
+	namespacesCount := 0
+	filesCounts := map[*Namespace]int{}
+
+	storage.Read(func() {
+		namespacesCount = len(storage.Namespaces)
+
+		for _, namespace := range storage.Namespaces {
+			filesCounts[namespace] = len(namespace.Files)
+		}
+	})
+
+	result := make([][]*File, len(generators))
+	contexts := make([]*GeneratorContext, len(generators))
+	panics := make([]interface{}, len(generators))
+	group := sync.WaitGroup{}
+	run := func(i int, callback func()) {
+		defer func() {
+			panics[i] = recover()
+		}()
+
+		callback()
+	}
+
+	for i, generator := range generators {
+		contextGenerator, ok := generator.(ContextGenerator)
+
+		if !ok {
+			continue
+		}
+
+		contexts[i] = a.generatorContext(generator)
+		contexts[i].isDeferred = true
+
+		group.Add(1)
+
+		go func(i int, generator ContextGenerator) {
+			defer group.Done()
+
+			run(i, func() {
+				generator.GenerateWithContext(contexts[i])
+			})
+		}(i, contextGenerator)
+	}
+
+	group.Wait()
+
+	// Files with the same name are added too, they are merged or reported by FileConflictResolver
+	storage.Update(func() {
+		for i, context := range contexts {
+			if context == nil {
+				continue
+			}
+
+			result[i] = []*File{}
+
+			for _, created := range context.files {
+				created.namespace.Files = append(created.namespace.Files, created.file)
+				result[i] = append(result[i], created.file)
+			}
+		}
+	})
+
+	for i, generator := range generators {
+		if contexts[i] != nil {
+			continue
+		}
+
+		files := a.storageFiles()
+
+		run(i, func() {
+			generator.Generate(a)
+		})
+
+		result[i] = a.addedFiles(files)
+	}
+
+	storage.Update(func() {
+		if len(storage.Namespaces) > namespacesCount {
+			added := storage.Namespaces[namespacesCount:]
+
+			sort.SliceStable(added, func(i, j int) bool {
+				return added[i].Name < added[j].Name
+			})
+		}
+
+		for _, namespace := range storage.Namespaces {
+			if len(namespace.Files) > filesCounts[namespace] {
+				added := namespace.Files[filesCounts[namespace]:]
+
+				sort.SliceStable(added, func(i, j int) bool {
+					return added[i].Name < added[j].Name
+				})
+			}
+		}
+	})
+
+	for _, err := range panics {
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	return result
+}
+
+// Creates all services before concurrent run, because lazy creation is not synchronized.
+func (a *Application) initServices() {
+	a.Storage()
+	a.TypesInfo()
+	a.EntityContext()
+	a.Diagnostics()
+	a.Regions()
+	a.ChangeSet()
+	a.Logger()
+	a.AnnotationFinder()
+	a.AnnotationParser()
+	a.ASTConverter()
+	a.Cloner()
+	a.ConfigLoader()
+	a.StorageCleaner()
+	a.StorageWriter()
+	a.ImportFetcher()
+	a.ImportRenamer()
+	a.ImportUniquer()
+	a.NamespaceResolver()
+	a.Equaler()
+	a.FileConflictResolver()
+	a.FileEditor()
+	a.ContainsChecker()
+	a.ContextIndexer()
+	a.MethodSetFetcher()
+	a.ImplementationFinder()
+	a.Selector()
+	a.Scanner()
+	a.TypesScanner()
+	a.Renderer()
+	a.SourceParser()
+	a.Transformer()
+	a.Validator()
+	a.Walker()
 }
@@ -1,6 +1,11 @@
 package annotation
 
 import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 
 	"github.com/index0h/go-unit/unit"
@@ -13,21 +18,45 @@ func TestNewApplication(t *testing.T) {
 	actual := NewApplication()
 
 	ctrl.AssertNotNil(actual)
+	ctrl.AssertNil(actual.config)
 	ctrl.AssertNil(actual.storage)
+	ctrl.AssertNil(actual.typesInfo)
+	ctrl.AssertNil(actual.entityContext)
+	ctrl.AssertNil(actual.diagnostics)
+	ctrl.AssertNil(actual.regions)
+	ctrl.AssertNil(actual.changeSet)
+	ctrl.AssertNil(actual.logger)
+	ctrl.AssertNil(actual.annotationFinder)
 	ctrl.AssertNil(actual.annotationParser)
+	ctrl.AssertNil(actual.astConverter)
 	ctrl.AssertNil(actual.cloner)
+	ctrl.AssertNil(actual.configLoader)
 	ctrl.AssertNil(actual.storageCleaner)
 	ctrl.AssertNil(actual.storageWriter)
 	ctrl.AssertNil(actual.importFetcher)
 	ctrl.AssertNil(actual.importRenamer)
 	ctrl.AssertNil(actual.importUniquer)
+	ctrl.AssertNil(actual.implementationFinder)
+	ctrl.AssertNil(actual.methodSetFetcher)
+	ctrl.AssertNil(actual.namespaceResolver)
 	ctrl.AssertNil(actual.equaler)
+	ctrl.AssertNil(actual.fileConflictResolver)
+	ctrl.AssertNil(actual.fileEditor)
 	ctrl.AssertNil(actual.containsChecker)
+	ctrl.AssertNil(actual.contextIndexer)
 	ctrl.AssertNil(actual.renderer)
 	ctrl.AssertNil(actual.scanner)
+	ctrl.AssertNil(actual.selector)
+	ctrl.AssertNil(actual.typesScanner)
 	ctrl.AssertNil(actual.sourceParser)
+	ctrl.AssertNil(actual.transformer)
 	ctrl.AssertNil(actual.validator)
+	ctrl.AssertNil(actual.walker)
 	ctrl.AssertNil(actual.generators)
+	ctrl.AssertNil(actual.generatorOptions)
+	ctrl.AssertNil(actual.enabledGenerators)
+	ctrl.AssertFalse(actual.isConcurrent)
+	ctrl.AssertSame(0, actual.maxPasses)
 }
 
 func TestApplication_Storage(t *testing.T) {
@@ -42,6 +71,145 @@ func TestApplication_Storage(t *testing.T) {
 	ctrl.AssertSame(application.storage, actual)
 }
 
+func TestApplication_TypesInfo(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.TypesInfo()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.typesInfo, actual)
+}
+
+func TestApplication_TypesImporter(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.TypesImporter()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.typesImporter, actual)
+}
+
+func TestApplication_EntityContext(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.EntityContext()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.entityContext, actual)
+}
+
+func TestApplication_ConfigLoader(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.ConfigLoader()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.configLoader, actual)
+}
+
+func TestApplication_ContextIndexer(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.ContextIndexer()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.contextIndexer, actual)
+	ctrl.AssertSame(application.walker, actual.(*EntityContextIndexer).walker)
+}
+
+func TestApplication_Diagnostics(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.Diagnostics()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.diagnostics, actual)
+}
+
+func TestApplication_Regions(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.Regions()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.regions, actual)
+}
+
+func TestApplication_ChangeSet(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.ChangeSet()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.changeSet, actual)
+}
+
+func TestApplication_Logger(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.Logger()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.logger, actual)
+	ctrl.AssertSame(os.Stderr, actual.(*TextLogger).writer)
+}
+
+func TestApplication_SetLogger(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+	logger := NewTextLogger(&bytes.Buffer{})
+
+	application.SetLogger(logger)
+
+	ctrl.AssertSame(logger, application.logger)
+
+	ctrl.Subtest("").
+		Call(application.SetLogger, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'logger' must be not nil"))
+}
+
+func TestApplication_AnnotationFinder(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.AnnotationFinder()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.annotationFinder, actual)
+	ctrl.AssertSame(application.EntityContext(), actual.(*EntityAnnotationFinder).context)
+}
+
 func TestApplication_AnnotationParser(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
@@ -54,6 +222,23 @@ func TestApplication_AnnotationParser(t *testing.T) {
 	ctrl.AssertSame(application.annotationParser, actual)
 }
 
+func TestApplication_ASTConverter(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.ASTConverter()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.astConverter, actual)
+	ctrl.AssertSame(application.renderer, actual.(*EntityASTConverter).renderer)
+	ctrl.AssertSame(
+		application.annotationParser,
+		actual.(*EntityASTConverter).sourceParser.annotationParser,
+	)
+}
+
 func TestApplication_Cloner(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
@@ -76,6 +261,8 @@ func TestApplication_StorageCleaner(t *testing.T) {
 
 	ctrl.AssertNotNil(actual)
 	ctrl.AssertSame(application.storageCleaner, actual)
+	ctrl.AssertSame(application.Logger(), actual.(*GeneratedFileCleaner).logger)
+	ctrl.AssertSame(application.sourceParser, actual.(*GeneratedFileCleaner).sourceParser)
 }
 
 func TestApplication_StorageWriter(t *testing.T) {
@@ -90,6 +277,10 @@ func TestApplication_StorageWriter(t *testing.T) {
 	ctrl.AssertSame(application.storageWriter, actual)
 	ctrl.AssertSame(application.storageWriter.(*GeneratedFileWriter).validator, actual.(*GeneratedFileWriter).validator)
 	ctrl.AssertSame(application.storageWriter.(*GeneratedFileWriter).renderer, actual.(*GeneratedFileWriter).renderer)
+	ctrl.AssertSame(application.logger, actual.(*GeneratedFileWriter).logger)
+	ctrl.AssertNotNil(actual.(*GeneratedFileWriter).beforeWrite)
+	ctrl.AssertSame(application.regions, actual.(*GeneratedFileWriter).regions)
+	ctrl.AssertSame(application.changeSet, actual.(*GeneratedFileWriter).changeSet)
 }
 
 func TestApplication_ImportFetcher(t *testing.T) {
@@ -132,6 +323,21 @@ func TestApplication_ImportUniquer(t *testing.T) {
 	ctrl.AssertSame(application.importUniquer, actual)
 }
 
+func TestApplication_NamespaceResolver(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.NamespaceResolver()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.namespaceResolver, actual)
+	ctrl.AssertSame(application.typesImporter, actual.(*ExternalNamespaceResolver).importer)
+	ctrl.AssertNotNil(actual.(*ExternalNamespaceResolver).specBuilder)
+	ctrl.AssertSame(application.logger, actual.(*ExternalNamespaceResolver).logger)
+}
+
 func TestApplication_Equaler(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
@@ -144,6 +350,34 @@ func TestApplication_Equaler(t *testing.T) {
 	ctrl.AssertSame(application.equaler, actual)
 }
 
+func TestApplication_FileConflictResolver(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.FileConflictResolver()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.fileConflictResolver, actual)
+	ctrl.AssertSame(FileConflictPolicyError, actual.Policy())
+	ctrl.AssertSame(application.importRenamer, actual.(*GeneratedFileConflictResolver).importRenamer)
+}
+
+func TestApplication_FileEditor(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.FileEditor()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.fileEditor, actual)
+	ctrl.AssertSame(application.sourceParser, actual.(*SourceFileEditor).sourceParser)
+	ctrl.AssertSame(application.renderer, actual.(*SourceFileEditor).renderer)
+}
+
 func TestApplication_ContainsChecker(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
@@ -157,6 +391,43 @@ func TestApplication_ContainsChecker(t *testing.T) {
 	ctrl.AssertSame(application.equaler, actual.(*EntityContainsChecker).equaler)
 }
 
+func TestApplication_MethodSetFetcher(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.MethodSetFetcher()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.methodSetFetcher, actual)
+}
+
+func TestApplication_ImplementationFinder(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.ImplementationFinder()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.implementationFinder, actual)
+	ctrl.AssertSame(application.methodSetFetcher, actual.(*EntityImplementationFinder).methodSetFetcher)
+}
+
+func TestApplication_Selector(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.Selector()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.selector, actual)
+}
+
 func TestApplication_Scanner(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
@@ -171,6 +442,21 @@ func TestApplication_Scanner(t *testing.T) {
 	ctrl.AssertSame(application.scanner.(*GoScanner).sourceParser, actual.(*GoScanner).sourceParser)
 }
 
+func TestApplication_TypesScanner(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.TypesScanner()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.typesScanner, actual)
+	ctrl.AssertSame(application.scanner, actual.(*GoTypesScanner).scanner)
+	ctrl.AssertSame(application.typesInfo, actual.(*GoTypesScanner).typesInfo)
+	ctrl.AssertSame(application.typesImporter, actual.(*GoTypesScanner).importer)
+}
+
 func TestApplication_Renderer(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
@@ -197,6 +483,35 @@ func TestApplication_SourceParser(t *testing.T) {
 		application.sourceParser.(*GoSourceParser).annotationParser,
 		actual.(*GoSourceParser).annotationParser,
 	)
+	ctrl.AssertFalse(actual.(*GoSourceParser).isLayoutKept)
+}
+
+func TestApplication_SetLayoutKept(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+	application.SetLayoutKept(true)
+
+	ctrl.AssertTrue(application.SourceParser().(*GoSourceParser).isLayoutKept)
+
+	application.SetLayoutKept(false)
+
+	ctrl.AssertFalse(application.isLayoutKept)
+	ctrl.AssertFalse(application.SourceParser().(*GoSourceParser).isLayoutKept)
+}
+
+func TestApplication_Transformer(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.Transformer()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.transformer, actual)
+	ctrl.AssertSame(application.validator, actual.(*EntityTransformer).validator)
 }
 
 func TestApplication_Validator(t *testing.T) {
@@ -211,6 +526,18 @@ func TestApplication_Validator(t *testing.T) {
 	ctrl.AssertSame(application.validator, actual)
 }
 
+func TestApplication_Walker(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	actual := application.Walker()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application.walker, actual)
+}
+
 func TestApplication_Scan(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
@@ -233,6 +560,29 @@ func TestApplication_Scan(t *testing.T) {
 		Return()
 
 	application.Scan(rootNamespace, rootPath, ignores...)
+
+	ctrl.AssertEqual([]interface{}{}, application.EntityContext().Parents(storage))
+}
+
+func TestApplication_Scan_WithTypesChecking(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fs := NewTmpFS(ctrl).
+		CreateDir("model", 0777).
+		CreateFile("model/model.go", 0666, "package model\n\ntype Model struct{}\n")
+
+	generator := &TestHookGenerator{}
+	application := &Application{storage: &Storage{}, generators: []Generator{generator}}
+
+	application.SetTypesChecking(true)
+	application.Scan("example.com", fs.RootPath())
+
+	modelType := application.Storage().FindNamespaceByName("example.com/model").Files[0].TypeGroups[0].Types[0]
+
+	ctrl.AssertNotNil(application.TypesInfo().Find(modelType))
+	ctrl.AssertSame("Model", application.TypesInfo().Find(modelType).Object.Name())
+	ctrl.AssertEqual([]string{"AfterScan"}, generator.Calls)
 }
 
 func TestApplication_RegisterGenerator(t *testing.T) {
@@ -324,4 +674,878 @@ func TestApplication_Generate(t *testing.T) {
 		Return()
 
 	application.Generate()
+
+	ctrl.AssertEqual([]interface{}{}, application.EntityContext().Parents(storage))
+}
+
+func TestApplication_SetConcurrent(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	application.SetConcurrent(true)
+
+	ctrl.AssertTrue(application.isConcurrent)
+}
+
+func TestApplication_Generate_WithConcurrentGenerators(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	namespace := &Namespace{Name: "model", Path: "/model", Files: []*File{{Name: "z.go"}}}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+	storageCleaner := NewStorageCleanerMock(ctrl)
+	storageWriter := NewStorageWriterMock(ctrl)
+	calls := []string{}
+	fileNames := func(namespace *Namespace) []string {
+		result := []string{}
+
+		for _, file := range namespace.Files {
+			result = append(result, file.Name)
+		}
+
+		return result
+	}
+	addFile := func(name string) *TestConcurrentGenerator {
+		return &TestConcurrentGenerator{
+			IsConcurrentValue: true,
+			Callback: func(application *Application) {
+				application.Storage().AddFile(namespace, &File{Name: name})
+			},
+		}
+	}
+
+	application := &Application{
+		storage:        storage,
+		storageCleaner: storageCleaner,
+		storageWriter:  storageWriter,
+		isConcurrent:   true,
+		generators: []Generator{
+			addFile("c.go"),
+			addFile("a.go"),
+			&TestConcurrentGenerator{
+				Callback: func(application *Application) {
+					calls = append(calls, "barrier")
+
+					ctrl.AssertEqual([]string{"z.go", "a.go", "c.go"}, fileNames(namespace))
+				},
+			},
+			addFile("e.go"),
+			addFile("b.go"),
+			addFile("d.go"),
+		},
+	}
+
+	storageCleaner.
+		EXPECT().
+		Clean(ctrl.Same(storage)).
+		Return()
+
+	storageWriter.
+		EXPECT().
+		Write(ctrl.Same(storage)).
+		Return()
+
+	application.Generate()
+
+	ctrl.AssertEqual([]string{"barrier"}, calls)
+	ctrl.AssertEqual([]string{"z.go", "a.go", "c.go", "b.go", "d.go", "e.go"}, fileNames(namespace))
+	ctrl.AssertNotNil(application.walker)
+}
+
+func TestApplication_Generate_WithConcurrentContextGenerators(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	namespace := &Namespace{
+		Name: "model",
+		Path: "/model",
+		Files: []*File{
+			{Name: "model.go", TypeGroups: []*TypeGroup{{Types: []*Type{{Name: "Model", Spec: &StructSpec{}}}}}},
+		},
+	}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+	storageCleaner := NewStorageCleanerMock(ctrl)
+	storageWriter := NewStorageWriterMock(ctrl)
+	legacyFile := &File{Name: "legacy.go"}
+	newGenerator := func(name string) Generator {
+		return &TestConcurrentContextGenerator{
+			TestContextGenerator: TestContextGenerator{
+				NameValue: name,
+				Callback: func(context *GeneratorContext) {
+					for i := 0; i < 10; i++ {
+						file := context.CreateFile(namespace, name+".go")
+						file.TypeGroups = append(
+							file.TypeGroups,
+							&TypeGroup{Types: []*Type{{Name: name + strconv.Itoa(i), Spec: &StructSpec{}}}},
+						)
+
+						_, model := namespace.FindTypeByName("Model")
+						ctrl.AssertNotNil(model)
+					}
+				},
+			},
+		}
+	}
+
+	application := &Application{
+		storage:        storage,
+		storageCleaner: storageCleaner,
+		storageWriter:  storageWriter,
+		isConcurrent:   true,
+		generators: []Generator{
+			newGenerator("second"),
+			&TestConcurrentGenerator{
+				IsConcurrentValue: true,
+				Callback: func(application *Application) {
+					application.Storage().AddFile(namespace, legacyFile)
+				},
+			},
+			newGenerator("first"),
+		},
+	}
+
+	application.FileConflictResolver().(*GeneratedFileConflictResolver).SetPolicy(FileConflictPolicyMerge)
+
+	storageCleaner.
+		EXPECT().
+		Clean(ctrl.Same(storage)).
+		Return()
+
+	storageWriter.
+		EXPECT().
+		Write(ctrl.Same(storage)).
+		Return()
+
+	application.Generate()
+
+	ctrl.AssertSame(4, len(namespace.Files))
+	ctrl.AssertSame("model.go", namespace.Files[0].Name)
+	ctrl.AssertSame("first.go", namespace.Files[1].Name)
+	ctrl.AssertSame("legacy.go", namespace.Files[2].Name)
+	ctrl.AssertSame("second.go", namespace.Files[3].Name)
+	ctrl.AssertSame(10, len(namespace.Files[1].TypeGroups))
+	ctrl.AssertEqual([]interface{}{FileGeneratedByAnnotation{Name: "first"}}, namespace.Files[1].Annotations)
+	ctrl.AssertEqual(
+		[]interface{}{FileGeneratedByAnnotation{Name: "*annotation.TestConcurrentGenerator"}},
+		legacyFile.Annotations,
+	)
+	ctrl.AssertNotNil(storage.FindDeclaration("model.first9"))
+	ctrl.AssertNotNil(storage.FindDeclaration("model.second9"))
+}
+
+func TestApplication_Generate_WithConcurrentGeneratorPanic(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	storageCleaner := NewStorageCleanerMock(ctrl)
+
+	application := &Application{
+		storage:        &Storage{},
+		storageCleaner: storageCleaner,
+		isConcurrent:   true,
+		generators: []Generator{
+			&TestConcurrentGenerator{IsConcurrentValue: true, Callback: func(*Application) {}},
+			&TestConcurrentGenerator{IsConcurrentValue: true, Callback: func(*Application) { panic("first") }},
+			&TestConcurrentGenerator{IsConcurrentValue: true, Callback: func(*Application) { panic("second") }},
+		},
+	}
+
+	storageCleaner.
+		EXPECT().
+		Clean(ctrl.Same(application.storage)).
+		Return()
+
+	ctrl.Subtest("").
+		Call(application.Generate).
+		ExpectPanic("first")
+}
+
+func TestApplication_generatorBatches(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	generator1 := NewGeneratorMock(ctrl)
+	generator2 := &TestConcurrentGenerator{IsConcurrentValue: true}
+	generator3 := &TestConcurrentGenerator{IsConcurrentValue: true}
+	generator4 := &TestConcurrentGenerator{IsConcurrentValue: false}
+	generator5 := &TestConcurrentGenerator{IsConcurrentValue: true}
+
+	application := &Application{
+		generators: []Generator{generator1, generator2, generator3, generator4, generator5},
+	}
+
+	actual := application.generatorBatches(application.generators)
+
+	ctrl.AssertSame(5, len(actual))
+
+	application.SetConcurrent(true)
+
+	actual = application.generatorBatches(application.generators)
+
+	ctrl.AssertSame(4, len(actual))
+	ctrl.AssertSame(generator1, actual[0][0])
+	ctrl.AssertSame(2, len(actual[1]))
+	ctrl.AssertSame(generator2, actual[1][0])
+	ctrl.AssertSame(generator3, actual[1][1])
+	ctrl.AssertSame(generator4, actual[2][0])
+	ctrl.AssertSame(generator5, actual[3][0])
+}
+
+func TestApplication_RegisterGenerator_WithDuplicateName(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	application.RegisterGenerator(&TestDependentGenerator{NameValue: "mock"})
+
+	ctrl.Subtest("").
+		Call(application.RegisterGenerator, &TestDependentGenerator{NameValue: "mock"}).
+		ExpectPanic(NewErrorMessageConstraint("Generator with name 'mock' is already registered"))
+}
+
+func TestApplication_SetMaxPasses(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+
+	application.SetMaxPasses(3)
+
+	ctrl.AssertSame(3, application.maxPasses)
+
+	ctrl.Subtest("").
+		Call(application.SetMaxPasses, 0).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'maxPasses' must be greater than 0, actual value: '0'"))
+}
+
+func TestApplication_sortGenerators(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	generator1 := NewGeneratorMock(ctrl)
+	mock := &TestDependentGenerator{NameValue: "mock", DependenciesValue: []string{"extract", "interface"}}
+	extract := &TestDependentGenerator{NameValue: "extract", DependenciesValue: []string{"interface"}}
+	iface := &TestDependentGenerator{NameValue: "interface"}
+
+	application := &Application{generators: []Generator{generator1, mock, extract, iface}}
+
+	actual := application.sortGenerators()
+
+	ctrl.AssertSame(4, len(actual))
+	ctrl.AssertSame(generator1, actual[0])
+	ctrl.AssertSame(iface, actual[1])
+	ctrl.AssertSame(extract, actual[2])
+	ctrl.AssertSame(mock, actual[3])
+}
+
+func TestApplication_sortGenerators_WithCycle(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{
+		generators: []Generator{
+			&TestDependentGenerator{NameValue: "first", DependenciesValue: []string{"second"}},
+			&TestDependentGenerator{NameValue: "second", DependenciesValue: []string{"third"}},
+			&TestDependentGenerator{NameValue: "third", DependenciesValue: []string{"second"}},
+		},
+	}
+
+	ctrl.Subtest("").
+		Call(application.sortGenerators).
+		ExpectPanic(NewErrorMessageConstraint("Generators have dependency cycle: 'second' -> 'third' -> 'second'"))
+}
+
+func TestApplication_sortGenerators_WithUnknownDependency(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{
+		generators: []Generator{&TestDependentGenerator{NameValue: "mock", DependenciesValue: []string{"unknown"}}},
+	}
+
+	ctrl.Subtest("").
+		Call(application.sortGenerators).
+		ExpectPanic(NewErrorMessageConstraint("Generator 'mock' depends on unknown generator 'unknown'"))
+}
+
+func TestApplication_Generate_WithMultiPass(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	namespace := &Namespace{Name: "model", Path: "/model", Files: []*File{}}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+	storageCleaner := NewStorageCleanerMock(ctrl)
+	storageWriter := NewStorageWriterMock(ctrl)
+	annotations := map[string]interface{}{"Test": TestAnnotation{}}
+	mockCalls := []int{}
+
+	application := &Application{
+		storage:        storage,
+		storageCleaner: storageCleaner,
+		storageWriter:  storageWriter,
+		generators: []Generator{
+			&TestDependentGenerator{
+				NameValue:        "mock",
+				AnnotationsValue: annotations,
+				Callback: func(application *Application) {
+					mockCalls = append(mockCalls, len(application.Storage().FindTypesByAnnotation(TestAnnotation{})))
+					application.Storage().AddFile(namespace, &File{Name: "mock.go"})
+				},
+			},
+			&TestDependentGenerator{
+				NameValue:        "extract",
+				AnnotationsValue: annotations,
+				Callback: func(application *Application) {
+					application.Storage().AddFile(
+						namespace,
+						&File{
+							Name: "interface.go",
+							TypeGroups: []*TypeGroup{
+								{
+									Types: []*Type{
+										{
+											Name:        "Saver",
+											Annotations: []interface{}{TestAnnotation{}},
+											Spec:        &InterfaceSpec{},
+										},
+									},
+								},
+							},
+						},
+					)
+				},
+			},
+		},
+	}
+
+	application.SetMaxPasses(3)
+
+	storageCleaner.
+		EXPECT().
+		Clean(ctrl.Same(storage)).
+		Return()
+
+	storageWriter.
+		EXPECT().
+		Write(ctrl.Same(storage)).
+		Return()
+
+	application.Generate()
+
+	ctrl.AssertEqual([]int{0, 1}, mockCalls)
+	ctrl.AssertSame(2, len(namespace.Files))
+}
+
+func TestApplication_Generate_WithFileOwners(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	namespace := &Namespace{Name: "model", Path: "/model", Files: []*File{}}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+	storageCleaner := NewGeneratedFileCleaner()
+	storageWriter := NewStorageWriterMock(ctrl)
+	mockFile := &File{Name: "mock.go"}
+	contextFile := (*File)(nil)
+
+	application := &Application{
+		storage:        storage,
+		storageCleaner: storageCleaner,
+		storageWriter:  storageWriter,
+		generators: []Generator{
+			&TestVersionedGenerator{
+				NameValue:    "mock",
+				VersionValue: "1.0",
+				Callback: func(application *Application) {
+					application.Storage().AddFile(namespace, mockFile)
+				},
+			},
+			&TestContextGenerator{
+				NameValue: "context",
+				Callback: func(context *GeneratorContext) {
+					contextFile = context.CreateFile(namespace, "context.go")
+				},
+			},
+		},
+	}
+
+	storageWriter.
+		EXPECT().
+		Write(ctrl.Same(storage)).
+		Return()
+
+	application.Generate()
+
+	ctrl.AssertEqual(map[string]bool{"mock": true, "context": true}, storageCleaner.owners)
+	ctrl.AssertEqual([]interface{}{FileGeneratedByAnnotation{Name: "mock", Version: "1.0"}}, mockFile.Annotations)
+	ctrl.AssertEqual([]interface{}{FileGeneratedByAnnotation{Name: "context"}}, contextFile.Annotations)
+}
+
+func TestApplication_Generate_WithFileConflict(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	namespace := &Namespace{Name: "model", Path: "/model", Files: []*File{}}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+	storageCleaner := NewStorageCleanerMock(ctrl)
+	writer := &bytes.Buffer{}
+
+	newGenerator := func(name string) Generator {
+		return &TestDependentGenerator{
+			NameValue: name,
+			Callback: func(application *Application) {
+				namespace.Files = append(namespace.Files, &File{Name: "model.go"})
+			},
+		}
+	}
+
+	application := &Application{
+		storage:        storage,
+		storageCleaner: storageCleaner,
+		logger:         NewTextLogger(writer),
+		generators:     []Generator{newGenerator("first"), newGenerator("second")},
+	}
+
+	storageCleaner.
+		EXPECT().
+		Clean(ctrl.Same(storage)).
+		Return()
+
+	ctrl.Subtest("").
+		Call(application.Generate).
+		ExpectPanic(
+			NewErrorMessageConstraint(
+				"Generation failed with 1 error(s):\n" +
+					"/model/model.go: error: File '/model/model.go' is created by generators 'first' and 'second' " +
+					"(generator: second)",
+			),
+		)
+}
+
+func TestApplication_Generate_WithEditing(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	storage := &Storage{}
+	storageCleaner := NewStorageCleanerMock(ctrl)
+	validator := NewValidatorMock(ctrl)
+	storageWriter := NewGeneratedFileWriter(validator, NewRendererMock(ctrl))
+
+	application := &Application{storage: storage, storageCleaner: storageCleaner, storageWriter: storageWriter}
+	application.SetEditing(true)
+
+	storageCleaner.
+		EXPECT().
+		Clean(ctrl.Same(storage)).
+		Return()
+
+	validator.
+		EXPECT().
+		Validate(ctrl.Same(storage)).
+		Return(nil)
+
+	application.Generate()
+
+	ctrl.AssertSame(application.fileEditor, storageWriter.editor)
+}
+
+func TestApplication_Generate_WithMultiPassAndNamespace(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	namespace := &Namespace{Name: "model", Path: "/model", Files: []*File{}}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+	storageCleaner := NewStorageCleanerMock(ctrl)
+	storageWriter := NewStorageWriterMock(ctrl)
+	annotations := map[string]interface{}{"Test": TestAnnotation{}}
+	mockCalls := []int{}
+
+	application := &Application{
+		storage:        storage,
+		storageCleaner: storageCleaner,
+		storageWriter:  storageWriter,
+		generators: []Generator{
+			&TestDependentGenerator{
+				NameValue:        "mock",
+				AnnotationsValue: annotations,
+				Callback: func(application *Application) {
+					mockCalls = append(mockCalls, len(application.Storage().FindTypesByAnnotation(TestAnnotation{})))
+				},
+			},
+			&TestDependentGenerator{
+				NameValue:        "extract",
+				AnnotationsValue: annotations,
+				Callback: func(application *Application) {
+					application.Storage().AddNamespace(
+						&Namespace{
+							Name: "model/extracted",
+							Path: "/model/extracted",
+							Files: []*File{
+								{
+									Name:        "interface.go",
+									PackageName: "extracted",
+									TypeGroups: []*TypeGroup{
+										{
+											Types: []*Type{
+												{
+													Name:        "Saver",
+													Annotations: []interface{}{TestAnnotation{}},
+													Spec:        &InterfaceSpec{},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					)
+				},
+			},
+		},
+	}
+
+	application.SetMaxPasses(3)
+
+	storageCleaner.
+		EXPECT().
+		Clean(ctrl.Same(storage)).
+		Return()
+
+	storageWriter.
+		EXPECT().
+		Write(ctrl.Same(storage)).
+		Return()
+
+	application.Generate()
+
+	ctrl.AssertEqual([]int{0, 1}, mockCalls)
+	ctrl.AssertSame(2, len(storage.Namespaces))
+	ctrl.AssertSame(namespace, storage.Namespaces[0])
+	ctrl.AssertSame("model/extracted", storage.Namespaces[1].Name)
+}
+
+func TestApplication_Generate_WithMultiPassAndModifiedFile(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	file := &File{Name: "model.go", PackageName: "model", Content: "package model\n"}
+	namespace := &Namespace{Name: "model", Path: "/model", Files: []*File{file}}
+	storageCleaner := NewStorageCleanerMock(ctrl)
+
+	application := &Application{
+		storage:        &Storage{Namespaces: []*Namespace{namespace}},
+		storageCleaner: storageCleaner,
+		generators: []Generator{
+			&TestDependentGenerator{
+				NameValue: "create",
+				Callback: func(application *Application) {
+					application.Storage().AddFile(namespace, &File{Name: "created.go", PackageName: "model"})
+				},
+			},
+			&TestDependentGenerator{
+				NameValue: "modify",
+				Callback: func(application *Application) {
+					file.Funcs = append(file.Funcs, &Func{Name: "Modified", Spec: &FuncSpec{}})
+				},
+			},
+		},
+	}
+
+	application.SetMaxPasses(2)
+
+	storageCleaner.
+		EXPECT().
+		Clean(ctrl.Same(application.storage)).
+		Return()
+
+	ctrl.Subtest("").
+		Call(application.Generate).
+		ExpectPanic(
+			NewErrorMessageConstraint(
+				"Generators 'modify' modified existing files, only new files could be created in multi-pass generation",
+			),
+		)
+}
+
+func TestApplication_Generate_WithNotStableMultiPass(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	namespace := &Namespace{Name: "model", Path: "/model", Files: []*File{}}
+	storageCleaner := NewStorageCleanerMock(ctrl)
+	count := 0
+
+	application := &Application{
+		storage:        &Storage{Namespaces: []*Namespace{namespace}},
+		storageCleaner: storageCleaner,
+		generators: []Generator{
+			&TestDependentGenerator{
+				NameValue:        "growing",
+				AnnotationsValue: map[string]interface{}{"Test": TestAnnotation{}},
+				Callback: func(application *Application) {
+					count++
+
+					application.Storage().AddFile(
+						namespace,
+						&File{
+							Name: "file" + strconv.Itoa(count) + ".go",
+							Funcs: []*Func{
+								{Name: "F", Annotations: []interface{}{TestAnnotation{}}, Spec: &FuncSpec{}},
+							},
+						},
+					)
+				},
+			},
+		},
+	}
+
+	application.SetMaxPasses(2)
+
+	storageCleaner.
+		EXPECT().
+		Clean(ctrl.Same(application.storage)).
+		Return()
+
+	ctrl.Subtest("").
+		Call(application.Generate).
+		ExpectPanic(NewErrorMessageConstraint("Generation is not stable after 2 passes"))
+}
+
+func TestApplication_WithHooks(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	storage := &Storage{}
+	scanner := NewScannerMock(ctrl)
+	storageCleaner := NewStorageCleanerMock(ctrl)
+	storageWriter := NewStorageWriterMock(ctrl)
+	generator := &TestHookGenerator{}
+	file := &File{Name: "file.go"}
+
+	application := &Application{
+		storage:        storage,
+		scanner:        scanner,
+		storageCleaner: storageCleaner,
+		storageWriter:  storageWriter,
+		generators:     []Generator{NewGeneratorMock(ctrl), generator},
+	}
+
+	application.generators[0].(*GeneratorMock).
+		EXPECT().
+		Generate(ctrl.Same(application)).
+		Return()
+
+	scanner.
+		EXPECT().
+		Scan(ctrl.Same(storage), "namespace", "/path").
+		Return()
+
+	storageCleaner.
+		EXPECT().
+		Clean(ctrl.Same(storage)).
+		Return()
+
+	storageWriter.
+		EXPECT().
+		Write(ctrl.Same(storage)).
+		Callback(func(storage *Storage) {
+			ctrl.AssertSame(
+				"content// namespace\n",
+				application.beforeWrite(&Namespace{Name: "namespace"}, file, "content"),
+			)
+		})
+
+	application.Scan("namespace", "/path")
+	application.Generate()
+
+	ctrl.AssertEqual(
+		[]string{"AfterScan", "BeforeGenerate", "Generate", "BeforeWrite file.go", "AfterWrite"},
+		generator.Calls,
+	)
+}
+
+func TestApplication_SetGeneratorOptions(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+	options := map[string]interface{}{"key": "value"}
+
+	application.SetGeneratorOptions("mock", options)
+
+	ctrl.AssertEqual(map[string]map[string]interface{}{"mock": options}, application.generatorOptions)
+
+	ctrl.Subtest("Name").
+		Call(application.SetGeneratorOptions, "", options).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'name' must be not empty"))
+	ctrl.Subtest("Options").
+		Call(application.SetGeneratorOptions, "mock", nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'options' must be not nil"))
+}
+
+func TestApplication_Generate_WithContextGenerators(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	storageCleaner := NewStorageCleanerMock(ctrl)
+	writer := &bytes.Buffer{}
+	calls := []string{}
+
+	application := &Application{
+		storage:        &Storage{},
+		storageCleaner: storageCleaner,
+		logger:         NewTextLogger(writer),
+		generators: []Generator{
+			&TestContextGenerator{
+				NameValue: "first",
+				Callback: func(context *GeneratorContext) {
+					calls = append(calls, context.Name()+" "+context.Option("key", "default").(string))
+
+					context.Error(nil, "first error")
+				},
+			},
+			&TestContextGenerator{
+				NameValue: "second",
+				Callback: func(context *GeneratorContext) {
+					calls = append(calls, context.Name()+" "+context.Option("key", "default").(string))
+
+					context.Warning(nil, "warning")
+					context.Error(nil, "second error")
+				},
+			},
+		},
+	}
+
+	application.SetGeneratorOptions("second", map[string]interface{}{"key": "value"})
+
+	storageCleaner.
+		EXPECT().
+		Clean(ctrl.Same(application.storage)).
+		Return()
+
+	ctrl.Subtest("").
+		Call(application.Generate).
+		ExpectPanic(
+			NewErrorMessageConstraint(
+				"Generation failed with 2 error(s):\nerror: first error (generator: first)\n" +
+					"error: second error (generator: second)",
+			),
+		)
+
+	ctrl.AssertEqual([]string{"first default", "second value"}, calls)
+	ctrl.AssertSame(
+		"level=error message=\"first error\" generator=first\n"+
+			"level=warning message=warning generator=second\n"+
+			"level=error message=\"second error\" generator=second\n",
+		writer.String(),
+	)
+}
+
+func TestApplication_ApplyConfig(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	mock := &TestConfigurableGenerator{NameValue: "mock"}
+	disabled := &TestDependentGenerator{NameValue: "disabled"}
+	application := &Application{generators: []Generator{mock, disabled}}
+	config := &Config{
+		Roots:           []*ConfigRoot{{Path: "/path"}},
+		BuildTags:       []string{"integration"},
+		Generators:      []string{"mock"},
+		Options:         map[string]json.RawMessage{"mock": json.RawMessage(`{"suffix": "Mock", "level": 2}`)},
+		ModifiedFiles:   ModifiedFilePolicyWarning,
+		FileConflicts:   FileConflictPolicyMerge,
+		EditHandWritten: true,
+		TypesChecking:   true,
+		KeepLayout:      true,
+	}
+
+	application.ApplyConfig(config)
+
+	ctrl.AssertSame(config, application.Config())
+	ctrl.AssertSame("Mock", mock.Options.Suffix)
+	ctrl.AssertEqual(
+		map[string]interface{}{"suffix": "Mock", "level": float64(2)},
+		application.generatorOptions["mock"],
+	)
+	ctrl.AssertEqual([]string{"integration"}, application.scanner.(*GoScanner).buildTags)
+	ctrl.AssertSame(ModifiedFilePolicyWarning, application.storageCleaner.(*GeneratedFileCleaner).modifiedFilePolicy)
+	ctrl.AssertSame(ModifiedFilePolicyWarning, application.storageWriter.(*GeneratedFileWriter).modifiedFilePolicy)
+	ctrl.AssertSame(FileConflictPolicyMerge, application.FileConflictResolver().Policy())
+	ctrl.AssertTrue(application.isEditing)
+	ctrl.AssertTrue(application.isTypesChecking)
+	ctrl.AssertTrue(application.isLayoutKept)
+	ctrl.AssertTrue(application.sourceParser.(*GoSourceParser).isLayoutKept)
+	ctrl.AssertSame(1, len(application.sortGenerators()))
+	ctrl.AssertSame(mock, application.sortGenerators()[0])
+}
+
+func TestApplication_ApplyConfig_WithInvalidConfig(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{generators: []Generator{&TestConfigurableGenerator{NameValue: "mock"}}}
+
+	ctrl.Subtest("Nil").
+		Call(application.ApplyConfig, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'config' must be not nil"))
+	ctrl.Subtest("UnknownGenerator").
+		Call(application.ApplyConfig, &Config{Generators: []string{"unknown"}, Path: "/config.json"}).
+		ExpectPanic(NewErrorMessageConstraint("Generator 'unknown' from config '/config.json' is not registered"))
+	ctrl.Subtest("UnknownOptions").
+		Call(
+			application.ApplyConfig,
+			&Config{Options: map[string]json.RawMessage{"unknown": json.RawMessage(`{}`)}, Path: "/config.json"},
+		).
+		ExpectPanic(NewErrorMessageConstraint("Generator 'unknown' from config '/config.json' is not registered"))
+	ctrl.Subtest("InvalidOptions").
+		Call(application.ApplyConfig, &Config{Options: map[string]json.RawMessage{"mock": json.RawMessage(`[]`)}}).
+		ExpectPanic(
+			NewErrorMessageConstraint(
+				"Can't parse options of generator 'mock': " +
+					"json: cannot unmarshal array into Go value of type map[string]interface {}",
+			),
+		)
+}
+
+func TestApplication_LoadConfig(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fs := NewTmpFS(ctrl).
+		CreateDir("project", 0777).
+		CreateDir("project/model", 0777).
+		CreateFile("project/model/model.go", 0666, "package model\n\ntype Model struct{}\n").
+		CreateFile("project/"+ConfigFileName, 0666, `{"roots": [{"namespace": "example.com/project", "path": "."}]}`)
+
+	application := NewApplication()
+
+	ctrl.AssertTrue(application.LoadConfig(filepath.Join(fs.RootPath(), "project", "model")))
+	ctrl.AssertSame(filepath.Join(fs.RootPath(), "project", ConfigFileName), application.Config().Path)
+
+	application.ScanConfig()
+
+	ctrl.AssertNotNil(application.Storage().FindDeclaration("example.com/project/model.Model"))
+}
+
+func TestApplication_LoadConfig_WithNotFound(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{configLoader: NewJSONConfigLoader()}
+	fs := NewTmpFS(ctrl)
+
+	ctrl.AssertFalse(application.LoadConfig(fs.RootPath()))
+	ctrl.AssertNil(application.Config())
+}
+
+func TestApplication_ScanConfig_WithoutConfig(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call((&Application{}).ScanConfig).
+		ExpectPanic(NewErrorMessageConstraint("Config is not applied"))
 }
@@ -0,0 +1,60 @@
+package annotation
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Generated file is written.
+	FileChangeKindCreate = "create"
+	// Regions of hand-written file are changed.
+	FileChangeKindRegions = "regions"
+	// Declarations of hand-written file are changed.
+	FileChangeKindEdit = "edit"
+)
+
+// FileChange describes one written file.
+type FileChange struct {
+	Kind string
+	Path string
+	// Changed declarations of hand-written file, e.g. "type Model" or "func Model.Validate".
+	Declarations []string
+}
+
+// ChangeSet contains files, which were written during last generation.
+type ChangeSet struct {
+	mutex   sync.RWMutex
+	changes []*FileChange
+}
+
+func NewChangeSet() *ChangeSet {
+	return &ChangeSet{changes: []*FileChange{}}
+}
+
+func (s *ChangeSet) Add(change *FileChange) {
+	if change == nil {
+		panic(errors.New("Variable 'change' must be not nil"))
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.changes = append(s.changes, change)
+}
+
+// Returns changes in order of writing.
+func (s *ChangeSet) List() []*FileChange {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return append([]*FileChange{}, s.changes...)
+}
+
+func (s *ChangeSet) Reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.changes = []*FileChange{}
+}
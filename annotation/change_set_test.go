@@ -0,0 +1,29 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestChangeSet(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	first := &FileChange{Kind: FileChangeKindCreate, Path: "/model/first.go"}
+	second := &FileChange{Kind: FileChangeKindEdit, Path: "/model/second.go", Declarations: []string{"type Model"}}
+
+	changeSet := NewChangeSet()
+	changeSet.Add(first)
+	changeSet.Add(second)
+
+	ctrl.AssertEqual([]*FileChange{first, second}, changeSet.List())
+
+	changeSet.Reset()
+
+	ctrl.AssertEqual([]*FileChange{}, changeSet.List())
+
+	ctrl.Subtest("").
+		Call(changeSet.Add, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'change' must be not nil"))
+}
@@ -0,0 +1,52 @@
+package annotation
+
+import (
+	"encoding/json"
+)
+
+// Name of project config file, it's searched in working directory and its parents.
+const ConfigFileName = ".go-annotation.json"
+
+// Config describes generation run of project, e.g.:
+//
+//	{
+//	  "roots": [{"namespace": "example.com/project", "path": "."}],
+//	  "ignores": ["vendor"],
+//	  "buildTags": ["integration"],
+//	  "generators": ["mock"],
+//	  "options": {"mock": {"suffix": "Mock"}},
+//	  "modifiedFiles": "warning",
+//	  "fileConflicts": "merge",
+//	  "editHandWritten": true,
+//	  "typesChecking": true,
+//	  "keepLayout": true
+//	}
+type Config struct {
+	Roots []*ConfigRoot `json:"roots"`
+	// Parts of paths or absolute paths of folders, which must be ignored.
+	Ignores []string `json:"ignores"`
+	// If it's not nil, files are scanned only if they match build tags, GOOS and GOARCH.
+	BuildTags []string `json:"buildTags"`
+	// Names of enabled generators, all registered generators are enabled if it's empty.
+	Generators []string `json:"generators"`
+	// Options by generator name.
+	Options map[string]json.RawMessage `json:"options"`
+	// Policy for generated files, which were modified manually: "error" (default) or "warning".
+	ModifiedFiles string `json:"modifiedFiles"`
+	// Policy for files with the same name, which are created by several generators: "error" (default) or "merge".
+	FileConflicts string `json:"fileConflicts"`
+	// Enables editing of declarations of hand-written files, see Application.SetEditing.
+	EditHandWritten bool `json:"editHandWritten"`
+	// Enables type checking of scanned namespaces, see Application.SetTypesChecking.
+	TypesChecking bool `json:"typesChecking"`
+	// Enables keeping of layout of parsed files, see Application.SetLayoutKept.
+	KeepLayout bool `json:"keepLayout"`
+	// Absolute path of loaded config file.
+	Path string `json:"-"`
+}
+
+// ConfigRoot is root folder for scanning. Relative path is resolved from folder of config file.
+type ConfigRoot struct {
+	Namespace string `json:"namespace"`
+	Path      string `json:"path"`
+}
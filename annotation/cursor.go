@@ -0,0 +1,209 @@
+package annotation
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// Called by EntityTransformer for each entity. See EntityTransformer.Apply.
+type ApplyFunc func(cursor *Cursor) bool
+
+// Cursor describes entity during EntityTransformer.Apply and allows to modify its parent.
+// Each modification is validated with parent by Validator, invalid modification is rolled back and causes panic.
+type Cursor struct {
+	validator Validator
+	entity    interface{}
+	parents   []interface{}
+	name      string
+	// Slice field of parent, which contains entity, nil if entity is not element of slice.
+	list *cursorList
+	// Single field of parent, which contains entity, invalid value for root.
+	field reflect.Value
+	// Entity was deleted from parent, its children and post ApplyFunc are skipped.
+	deleted bool
+}
+
+// State of iteration over slice field of parent.
+type cursorList struct {
+	value reflect.Value
+	index int
+	step  int
+}
+
+// Returns current entity.
+func (c *Cursor) Entity() interface{} {
+	return c.entity
+}
+
+// Returns parent of current entity, or nil for root.
+func (c *Cursor) Parent() interface{} {
+	if len(c.parents) == 0 {
+		return nil
+	}
+
+	return c.parents[len(c.parents)-1]
+}
+
+// Returns parents ordered from root to direct parent, they must not be modified.
+func (c *Cursor) Parents() []interface{} {
+	return c.parents
+}
+
+// Returns name of parent field, which contains current entity, or empty string for root.
+func (c *Cursor) Name() string {
+	return c.name
+}
+
+// Returns index of current entity in slice field of parent, or -1 if entity is not element of slice.
+func (c *Cursor) Index() int {
+	if c.list == nil {
+		return -1
+	}
+
+	return c.list.index
+}
+
+// Replaces current entity by another one. If it's called in pre ApplyFunc, children of new entity are visited.
+func (c *Cursor) Replace(entity interface{}) {
+	c.requireNotDeleted()
+	c.requireEntity(entity)
+
+	var target reflect.Value
+
+	switch {
+	case c.list != nil:
+		target = c.list.value.Index(c.list.index)
+	case c.field.IsValid():
+		target = c.field
+	default:
+		c.entity = entity
+
+		return
+	}
+
+	previous := reflect.ValueOf(c.entity)
+	value := c.value(target.Type(), entity)
+
+	c.modify(
+		func() {
+			target.Set(value)
+		},
+		func() {
+			target.Set(previous)
+		},
+	)
+
+	c.entity = entity
+}
+
+// Deletes current entity from slice field of parent. If it's called in pre ApplyFunc, children of entity and post
+// ApplyFunc are skipped.
+func (c *Cursor) Delete() {
+	list := c.requireList()
+
+	value := list.value
+	previous := c.copyList(value)
+
+	c.modify(
+		func() {
+			reflect.Copy(value.Slice(list.index, value.Len()), value.Slice(list.index+1, value.Len()))
+			value.Set(value.Slice(0, value.Len()-1))
+		},
+		func() {
+			value.Set(previous)
+		},
+	)
+
+	list.step--
+	c.deleted = true
+}
+
+// Inserts entity before current one into slice field of parent, inserted entity is not visited.
+func (c *Cursor) InsertBefore(entity interface{}) {
+	list := c.requireList()
+
+	c.insert(list, list.index, entity)
+	list.index++
+}
+
+// Inserts entity after current one into slice field of parent, inserted entity is not visited.
+func (c *Cursor) InsertAfter(entity interface{}) {
+	list := c.requireList()
+
+	c.insert(list, list.index+1, entity)
+	list.step++
+}
+
+func (c *Cursor) insert(list *cursorList, index int, entity interface{}) {
+	c.requireEntity(entity)
+
+	value := list.value
+	element := c.value(value.Type().Elem(), entity)
+	previous := c.copyList(value)
+
+	c.modify(
+		func() {
+			value.Set(reflect.Append(value, reflect.Zero(value.Type().Elem())))
+			reflect.Copy(value.Slice(index+1, value.Len()), value.Slice(index, value.Len()-1))
+			value.Index(index).Set(element)
+		},
+		func() {
+			value.Set(previous)
+		},
+	)
+}
+
+// Applies modification and validates parent, modification is rolled back, if parent is not valid.
+func (c *Cursor) modify(modification func(), rollback func()) {
+	modification()
+
+	if err := c.validator.Validate(c.Parent()); err != nil {
+		rollback()
+		panic(err)
+	}
+}
+
+func (c *Cursor) copyList(value reflect.Value) reflect.Value {
+	result := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+	reflect.Copy(result, value)
+
+	return result
+}
+
+func (c *Cursor) requireEntity(entity interface{}) {
+	if entity == nil {
+		panic(errors.New("Variable 'entity' must be not nil"))
+	}
+
+	if err := c.validator.Validate(entity); err != nil {
+		panic(err)
+	}
+}
+
+func (c *Cursor) requireList() *cursorList {
+	c.requireNotDeleted()
+
+	if c.list == nil {
+		panic(errors.Errorf("Entity with type '%T' is not element of slice", c.entity))
+	}
+
+	return c.list
+}
+
+func (c *Cursor) requireNotDeleted() {
+	if c.deleted {
+		panic(errors.Errorf("Entity with type '%T' is deleted", c.entity))
+	}
+}
+
+// Converts entity to value, which could be set to field with type.
+func (c *Cursor) value(typ reflect.Type, entity interface{}) reflect.Value {
+	value := reflect.ValueOf(entity)
+
+	if !value.Type().AssignableTo(typ) {
+		panic(errors.Errorf("Can't set entity with type '%T' to field with type '%s'", entity, typ))
+	}
+
+	return value
+}
@@ -0,0 +1,9 @@
+package annotation
+
+// Declaration is result of lookup by qualified name in Storage.
+type Declaration struct {
+	Namespace *Namespace
+	File      *File
+	// Allowed types: *Type, *Func, *Const, *Var.
+	Entity interface{}
+}
@@ -0,0 +1,39 @@
+package annotation
+
+import (
+	"go/token"
+)
+
+// Severities of diagnostic.
+const (
+	DiagnosticSeverityError   = "error"
+	DiagnosticSeverityWarning = "warning"
+)
+
+// Diagnostic is problem, which was reported by generator.
+type Diagnostic struct {
+	Severity  string
+	Generator string
+	Message   string
+	// Entity, which caused problem, and its namespace and file, each of them could be nil.
+	Entity    interface{}
+	Namespace *Namespace
+	File      *File
+	// Path of file or folder of namespace and line of entity in file, they are empty if they are unknown.
+	Position token.Position
+}
+
+// Returns text representation, e.g.: "/path/model.go:12:6: error: Field is invalid (generator: mock)".
+func (d *Diagnostic) String() string {
+	result := d.Severity + ": " + d.Message
+
+	if d.Position.Filename != "" {
+		result = d.Position.String() + ": " + result
+	}
+
+	if d.Generator != "" {
+		result += " (generator: " + d.Generator + ")"
+	}
+
+	return result
+}
@@ -0,0 +1,77 @@
+package annotation
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Collects diagnostics of generators, it's safe for concurrent use.
+type Diagnostics struct {
+	mutex sync.Mutex
+	list  []*Diagnostic
+}
+
+// Creates new instance of Diagnostics.
+func NewDiagnostics() *Diagnostics {
+	return &Diagnostics{
+		list: []*Diagnostic{},
+	}
+}
+
+// Adds diagnostic.
+func (d *Diagnostics) Add(diagnostic *Diagnostic) {
+	if diagnostic == nil {
+		panic(errors.New("Variable 'diagnostic' must be not nil"))
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.list = append(d.list, diagnostic)
+}
+
+// Returns all diagnostics in order of adding.
+func (d *Diagnostics) List() []*Diagnostic {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return append([]*Diagnostic{}, d.list...)
+}
+
+// Checks, that any diagnostic has error severity.
+func (d *Diagnostics) HasErrors() bool {
+	for _, diagnostic := range d.List() {
+		if diagnostic.Severity == DiagnosticSeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns error, which contains all diagnostics with error severity, or nil.
+func (d *Diagnostics) Err() error {
+	messages := []string{}
+
+	for _, diagnostic := range d.List() {
+		if diagnostic.Severity == DiagnosticSeverityError {
+			messages = append(messages, diagnostic.String())
+		}
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return errors.Errorf("Generation failed with %d error(s):\n%s", len(messages), strings.Join(messages, "\n"))
+}
+
+// Removes all diagnostics.
+func (d *Diagnostics) Reset() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.list = []*Diagnostic{}
+}
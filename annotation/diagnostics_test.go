@@ -0,0 +1,71 @@
+package annotation
+
+import (
+	"go/token"
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestDiagnostic_String(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.AssertSame(
+		"/path/model.go:12:6: error: Field is invalid (generator: mock)",
+		(&Diagnostic{
+			Severity:  DiagnosticSeverityError,
+			Generator: "mock",
+			Message:   "Field is invalid",
+			Position:  token.Position{Filename: "/path/model.go", Line: 12, Column: 6},
+		}).String(),
+	)
+	ctrl.AssertSame("warning: message", (&Diagnostic{Severity: DiagnosticSeverityWarning, Message: "message"}).String())
+}
+
+func TestDiagnostics(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	warning := &Diagnostic{Severity: DiagnosticSeverityWarning, Message: "warning"}
+	first := &Diagnostic{Severity: DiagnosticSeverityError, Message: "first", Generator: "mock"}
+	second := &Diagnostic{
+		Severity: DiagnosticSeverityError,
+		Message:  "second",
+		Position: token.Position{Filename: "/path"},
+	}
+
+	model := NewDiagnostics()
+
+	ctrl.AssertEqual([]*Diagnostic{}, model.List())
+	ctrl.AssertFalse(model.HasErrors())
+	ctrl.AssertNil(model.Err())
+
+	model.Add(warning)
+
+	ctrl.AssertFalse(model.HasErrors())
+	ctrl.AssertNil(model.Err())
+
+	model.Add(first)
+	model.Add(second)
+
+	ctrl.AssertEqual([]*Diagnostic{warning, first, second}, model.List())
+	ctrl.AssertTrue(model.HasErrors())
+	ctrl.AssertSame(
+		"Generation failed with 2 error(s):\nerror: first (generator: mock)\n/path: error: second",
+		model.Err().Error(),
+	)
+
+	model.Reset()
+
+	ctrl.AssertEqual([]*Diagnostic{}, model.List())
+}
+
+func TestDiagnostics_Add_WithNilDiagnostic(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewDiagnostics().Add, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'diagnostic' must be not nil"))
+}
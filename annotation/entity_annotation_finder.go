@@ -0,0 +1,107 @@
+package annotation
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// Finds annotations of entities by Go type of annotation. If EntityContext is set, consts, vars and types inherit
+// annotations of their groups, the same way as Storage Find*ByAnnotation methods do.
+type EntityAnnotationFinder struct {
+	context *EntityContext
+}
+
+// Creates new instance of EntityAnnotationFinder.
+func NewEntityAnnotationFinder() *EntityAnnotationFinder {
+	return &EntityAnnotationFinder{}
+}
+
+// Sets EntityContext, which is used to find groups of consts, vars and types.
+func (f *EntityAnnotationFinder) SetContext(context *EntityContext) {
+	if context == nil {
+		panic(errors.New("Variable 'context' must be not nil"))
+	}
+
+	f.context = context
+}
+
+// Returns annotations of entity, which have the same type as annotationType, annotations of group go first.
+func (f *EntityAnnotationFinder) Find(entity interface{}, annotationType interface{}) []interface{} {
+	if annotationType == nil {
+		panic(errors.New("Variable 'annotationType' must be not nil"))
+	}
+
+	result := []interface{}{}
+	expectedType := reflect.TypeOf(annotationType)
+
+	for _, annotation := range f.annotations(entity) {
+		if reflect.TypeOf(annotation) == expectedType {
+			result = append(result, annotation)
+		}
+	}
+
+	return result
+}
+
+// Returns first annotation of entity, which has the same type as annotationType, or nil if it's not found.
+// Result could be asserted to type of annotationType.
+func (f *EntityAnnotationFinder) FindFirst(entity interface{}, annotationType interface{}) interface{} {
+	if result := f.Find(entity, annotationType); len(result) > 0 {
+		return result[0]
+	}
+
+	return nil
+}
+
+func (f *EntityAnnotationFinder) annotations(entity interface{}) []interface{} {
+	switch entity := entity.(type) {
+	case *Field:
+		return entity.Annotations
+	case *Import:
+		return entity.Annotations
+	case *ImportGroup:
+		return entity.Annotations
+	case *Const:
+		if group, ok := f.group(entity).(*ConstGroup); ok {
+			return append(append([]interface{}{}, group.Annotations...), entity.Annotations...)
+		}
+
+		return entity.Annotations
+	case *ConstGroup:
+		return entity.Annotations
+	case *Var:
+		if group, ok := f.group(entity).(*VarGroup); ok {
+			return append(append([]interface{}{}, group.Annotations...), entity.Annotations...)
+		}
+
+		return entity.Annotations
+	case *VarGroup:
+		return entity.Annotations
+	case *Type:
+		if group, ok := f.group(entity).(*TypeGroup); ok {
+			return append(append([]interface{}{}, group.Annotations...), entity.Annotations...)
+		}
+
+		return entity.Annotations
+	case *TypeGroup:
+		return entity.Annotations
+	case *Func:
+		return entity.Annotations
+	case *File:
+		return entity.Annotations
+	case *AnnotatedEntity:
+		return entity.Annotations
+	default:
+		panic(errors.Errorf("Can't find annotations of entity with type: '%T'", entity))
+	}
+}
+
+// Returns direct parent of entity from EntityContext, or nil if context is not set.
+func (f *EntityAnnotationFinder) group(entity interface{}) interface{} {
+	if f.context == nil {
+		return nil
+	}
+
+	return f.context.Parent(entity)
+}
@@ -0,0 +1,137 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestNewEntityAnnotationFinder(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	actual := NewEntityAnnotationFinder()
+
+	ctrl.AssertNotNil(actual)
+}
+
+func TestEntityAnnotationFinder_Find(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	annotations := []interface{}{
+		TestAnnotation{Name: "first"},
+		FileIsGeneratedAnnotation(true),
+		TestAnnotation{Name: "second"},
+	}
+	expected := []interface{}{TestAnnotation{Name: "first"}, TestAnnotation{Name: "second"}}
+	entities := []interface{}{
+		&Field{Annotations: annotations},
+		&Import{Annotations: annotations},
+		&ImportGroup{Annotations: annotations},
+		&Const{Annotations: annotations},
+		&ConstGroup{Annotations: annotations},
+		&Var{Annotations: annotations},
+		&VarGroup{Annotations: annotations},
+		&Type{Annotations: annotations},
+		&TypeGroup{Annotations: annotations},
+		&Func{Annotations: annotations},
+		&File{Annotations: annotations},
+		&AnnotatedEntity{Annotations: annotations},
+	}
+	finder := NewEntityAnnotationFinder()
+
+	for _, entity := range entities {
+		ctrl.AssertEqual(expected, finder.Find(entity, TestAnnotation{}))
+	}
+
+	ctrl.AssertEqual([]interface{}{}, finder.Find(&Type{}, TestAnnotation{}))
+}
+
+func TestEntityAnnotationFinder_Find_WithContext(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	groupAnnotations := []interface{}{TestAnnotation{Name: "group"}}
+	annotations := []interface{}{TestAnnotation{Name: "element"}}
+	expected := []interface{}{TestAnnotation{Name: "group"}, TestAnnotation{Name: "element"}}
+	constElement := &Const{Name: "c", Annotations: annotations}
+	varElement := &Var{Name: "v", Annotations: annotations}
+	typeElement := &Type{Name: "T", Annotations: annotations}
+	storage := &Storage{
+		Namespaces: []*Namespace{
+			{
+				Name: "namespace",
+				Path: "namespace",
+				Files: []*File{
+					{
+						Name:        "file.go",
+						PackageName: "namespace",
+						ConstGroups: []*ConstGroup{{Annotations: groupAnnotations, Consts: []*Const{constElement}}},
+						VarGroups:   []*VarGroup{{Annotations: groupAnnotations, Vars: []*Var{varElement}}},
+						TypeGroups:  []*TypeGroup{{Annotations: groupAnnotations, Types: []*Type{typeElement}}},
+					},
+				},
+			},
+		},
+	}
+	context := NewEntityContext()
+	NewEntityContextIndexer(NewEntityWalker()).Index(storage, context)
+
+	finder := NewEntityAnnotationFinder()
+	finder.SetContext(context)
+
+	ctrl.AssertEqual(expected, finder.Find(constElement, TestAnnotation{}))
+	ctrl.AssertEqual(expected, finder.Find(varElement, TestAnnotation{}))
+	ctrl.AssertEqual(expected, finder.Find(typeElement, TestAnnotation{}))
+	ctrl.AssertEqual(annotations, finder.Find(&Type{Annotations: annotations}, TestAnnotation{}))
+	ctrl.AssertEqual(
+		[]*AnnotatedEntity{
+			{
+				Namespace:   storage.Namespaces[0],
+				File:        storage.Namespaces[0].Files[0],
+				Entity:      typeElement,
+				Annotations: finder.Find(typeElement, TestAnnotation{}),
+			},
+		},
+		storage.FindTypesByAnnotation(TestAnnotation{}),
+	)
+}
+
+func TestEntityAnnotationFinder_SetContext_WithNil(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityAnnotationFinder().SetContext, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'context' must be not nil"))
+}
+
+func TestEntityAnnotationFinder_Find_WithNilAnnotationType(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityAnnotationFinder().Find, &Type{}, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'annotationType' must be not nil"))
+}
+
+func TestEntityAnnotationFinder_Find_WithInvalidEntity(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityAnnotationFinder().Find, &SimpleSpec{}, TestAnnotation{}).
+		ExpectPanic(NewErrorMessageConstraint("Can't find annotations of entity with type: '*annotation.SimpleSpec'"))
+}
+
+func TestEntityAnnotationFinder_FindFirst(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &Type{Annotations: []interface{}{FileIsGeneratedAnnotation(true), TestAnnotation{Name: "first"}}}
+	finder := NewEntityAnnotationFinder()
+
+	ctrl.AssertEqual(TestAnnotation{Name: "first"}, finder.FindFirst(entity, TestAnnotation{}))
+	ctrl.AssertNil(finder.FindFirst(&Type{}, TestAnnotation{}))
+}
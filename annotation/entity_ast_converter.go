@@ -0,0 +1,227 @@
+package annotation
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// Package name used to wrap declarations into parsable source.
+const astConverterPackageName = "annotation"
+
+// Converts entities to go/ast nodes and go/ast nodes to entities.
+type EntityASTConverter struct {
+	renderer     Renderer
+	sourceParser *GoSourceParser
+}
+
+// Creates new instance of EntityASTConverter.
+func NewEntityASTConverter(renderer Renderer, sourceParser *GoSourceParser) *EntityASTConverter {
+	if renderer == nil {
+		panic(errors.New("Variable 'renderer' must be not nil"))
+	}
+
+	if sourceParser == nil {
+		panic(errors.New("Variable 'sourceParser' must be not nil"))
+	}
+
+	return &EntityASTConverter{
+		renderer:     renderer,
+		sourceParser: sourceParser,
+	}
+}
+
+// Converts entity to go/ast node, all positions of result are registered in fileSet.
+// Specs are converted to ast.Expr, *Field to *ast.Field, *Import, *Const, *Var and *Type to ast.Spec,
+// groups to *ast.GenDecl, *Func to *ast.FuncDecl and *File to *ast.File.
+// Comments inside of function body are kept only in *ast.File, because other nodes don't reference comments.
+func (c *EntityASTConverter) ToAST(fileSet *token.FileSet, entity interface{}) ast.Node {
+	if fileSet == nil {
+		panic(errors.New("Variable 'fileSet' must be not nil"))
+	}
+
+	switch entity := entity.(type) {
+	case *SimpleSpec, *PointerSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec:
+		return c.parseExpr(fileSet, c.renderer.Render(entity))
+	case *FuncSpec:
+		return c.parseExpr(fileSet, "func"+c.renderer.Render(entity))
+	case *Field:
+		return c.fieldToAST(fileSet, entity)
+	case *Import, *Const, *Var, *Type:
+		return c.specToAST(c.declToAST(fileSet, entity))
+	case *ImportGroup, *ConstGroup, *VarGroup, *TypeGroup, *Func:
+		return c.declToAST(fileSet, entity)
+	case *File:
+		return c.fileToAST(fileSet, entity)
+	default:
+		panic(errors.Errorf("Can't convert to ast entity with type: '%T'", entity))
+	}
+}
+
+// Converts go/ast node to entity, fileSet must contain positions of node.
+// Supported nodes are type expressions, *ast.Field (with no more than one name), *ast.FieldList, *ast.ImportSpec,
+// *ast.ValueSpec, *ast.TypeSpec, *ast.GenDecl, *ast.FuncDecl and *ast.File. Content of result *File is empty.
+// *ast.ValueSpec is converted to *Const or *Var by object of its name,
+// use ValueSpecFromAST for nodes without resolved objects.
+// Comments inside of function body are kept in Func.Content only for *ast.File, because *ast.FuncDecl doesn't
+// reference them.
+func (c *EntityASTConverter) FromAST(fileSet *token.FileSet, node ast.Node) interface{} {
+	if fileSet == nil {
+		panic(errors.New("Variable 'fileSet' must be not nil"))
+	}
+
+	astFile := &ast.File{}
+
+	switch node := node.(type) {
+	case *ast.File:
+		return c.fileFromAST(fileSet, node)
+	case *ast.Ident, *ast.SelectorExpr, *ast.StarExpr, *ast.ArrayType, *ast.MapType, *ast.FuncType,
+		*ast.StructType, *ast.InterfaceType:
+		return c.sourceParser.parseSpec(node.(ast.Expr), astFile, fileSet)
+	case *ast.Ellipsis:
+		return &c.sourceParser.parseEllipsisSpec(node, astFile, fileSet).ArraySpec
+	case *ast.Field:
+		if len(node.Names) > 1 {
+			panic(errors.Errorf("Variable 'node' must have no more than one name, actual: %d", len(node.Names)))
+		}
+
+		return c.sourceParser.parseFieldsList(&ast.FieldList{List: []*ast.Field{node}}, astFile, fileSet)[0]
+	case *ast.FieldList:
+		return c.sourceParser.parseFieldsList(node, astFile, fileSet)
+	case *ast.ImportSpec:
+		return c.sourceParser.parseImportGroup(&ast.GenDecl{Specs: []ast.Spec{node}}).Imports[0]
+	case *ast.ValueSpec:
+		if len(node.Names) > 0 && node.Names[0].Obj != nil {
+			switch node.Names[0].Obj.Kind {
+			case ast.Con:
+				return c.ValueSpecFromAST(fileSet, node, token.CONST)
+			case ast.Var:
+				return c.ValueSpecFromAST(fileSet, node, token.VAR)
+			}
+		}
+
+		panic(errors.New("Can't detect token of *ast.ValueSpec, use ValueSpecFromAST"))
+	case *ast.TypeSpec:
+		return c.sourceParser.parseTypeGroup(&ast.GenDecl{Specs: []ast.Spec{node}}, astFile, fileSet).Types[0]
+	case *ast.GenDecl:
+		return c.genDeclFromAST(fileSet, node, astFile)
+	case *ast.FuncDecl:
+		return c.sourceParser.parseFunc(node, astFile, fileSet)
+	default:
+		panic(errors.Errorf("Can't convert from ast node with type: '%T'", node))
+	}
+}
+
+// Converts *ast.ValueSpec to *Const if tok is token.CONST or to *Var if tok is token.VAR.
+func (c *EntityASTConverter) ValueSpecFromAST(
+	fileSet *token.FileSet,
+	node *ast.ValueSpec,
+	tok token.Token,
+) interface{} {
+	if fileSet == nil {
+		panic(errors.New("Variable 'fileSet' must be not nil"))
+	}
+
+	if node == nil {
+		panic(errors.New("Variable 'node' must be not nil"))
+	}
+
+	decl := &ast.GenDecl{Tok: tok, Specs: []ast.Spec{node}}
+
+	switch tok {
+	case token.CONST:
+		return c.sourceParser.parseConstGroup(decl, &ast.File{}, fileSet).Consts[0]
+	case token.VAR:
+		return c.sourceParser.parseVarGroup(decl, &ast.File{}, fileSet).Vars[0]
+	default:
+		panic(errors.Errorf("Variable 'tok' has unknown value: '%s'", tok))
+	}
+}
+
+func (c *EntityASTConverter) parseExpr(fileSet *token.FileSet, content string) ast.Expr {
+	result, err := parser.ParseExprFrom(fileSet, "", content, parser.ParseComments)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+func (c *EntityASTConverter) fieldToAST(fileSet *token.FileSet, entity *Field) *ast.Field {
+	structType := c.parseExpr(fileSet, c.renderer.Render(&StructSpec{Fields: []*Field{entity}})).(*ast.StructType)
+
+	return structType.Fields.List[0]
+}
+
+func (c *EntityASTConverter) declToAST(fileSet *token.FileSet, entity interface{}) ast.Decl {
+	content := "package " + astConverterPackageName + "\n\n" + c.renderer.Render(entity)
+	astFile, err := parser.ParseFile(fileSet, "", content, parser.ParseComments)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return astFile.Decls[0]
+}
+
+// Single declaration is rendered without parenthesis, so its comment belongs to declaration.
+func (c *EntityASTConverter) specToAST(decl ast.Decl) ast.Spec {
+	genDecl := decl.(*ast.GenDecl)
+	result := genDecl.Specs[0]
+
+	switch spec := result.(type) {
+	case *ast.ImportSpec:
+		if spec.Doc == nil {
+			spec.Doc = genDecl.Doc
+		}
+	case *ast.ValueSpec:
+		if spec.Doc == nil {
+			spec.Doc = genDecl.Doc
+		}
+	case *ast.TypeSpec:
+		if spec.Doc == nil {
+			spec.Doc = genDecl.Doc
+		}
+	}
+
+	return result
+}
+
+func (c *EntityASTConverter) fileToAST(fileSet *token.FileSet, entity *File) *ast.File {
+	result, err := parser.ParseFile(fileSet, entity.Name, c.renderer.Render(entity), parser.ParseComments)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return result
+}
+
+func (c *EntityASTConverter) fileFromAST(fileSet *token.FileSet, node *ast.File) *File {
+	result := c.sourceParser.parseFile(node, fileSet)
+
+	if tokenFile := fileSet.File(node.Pos()); tokenFile != nil && tokenFile.Name() != "" {
+		result.Name = filepath.Base(tokenFile.Name())
+	}
+
+	return result
+}
+
+func (c *EntityASTConverter) genDeclFromAST(fileSet *token.FileSet, node *ast.GenDecl, astFile *ast.File) interface{} {
+	switch node.Tok {
+	case token.IMPORT:
+		return c.sourceParser.parseImportGroup(node)
+	case token.CONST:
+		return c.sourceParser.parseConstGroup(node, astFile, fileSet)
+	case token.VAR:
+		return c.sourceParser.parseVarGroup(node, astFile, fileSet)
+	case token.TYPE:
+		return c.sourceParser.parseTypeGroup(node, astFile, fileSet)
+	default:
+		panic(errors.Errorf("Can't convert from ast declaration with token: '%s'", node.Tok))
+	}
+}
@@ -0,0 +1,377 @@
+package annotation
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestNewEntityASTConverter(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	renderer := NewRendererMock(ctrl)
+	sourceParser := NewGoSourceParser(NewAnnotationParserMock(ctrl))
+
+	actual := NewEntityASTConverter(renderer, sourceParser)
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(renderer, actual.renderer)
+	ctrl.AssertSame(sourceParser, actual.sourceParser)
+}
+
+func TestNewEntityASTConverter_WithNilRenderer(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	sourceParser := NewGoSourceParser(NewAnnotationParserMock(ctrl))
+
+	ctrl.Subtest("").
+		Call(NewEntityASTConverter, nil, sourceParser).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'renderer' must be not nil"))
+}
+
+func TestNewEntityASTConverter_WithNilSourceParser(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	renderer := NewRendererMock(ctrl)
+
+	ctrl.Subtest("").
+		Call(NewEntityASTConverter, renderer, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'sourceParser' must be not nil"))
+}
+
+func TestEntityASTConverter_ToAST_WithSpecs(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	converter := NewEntityASTConverter(NewEntityRenderer(), NewGoSourceParser(NewJSONAnnotationParser()))
+
+	entities := map[string]interface{}{
+		"*packageName.typeName": &SimpleSpec{PackageName: "packageName", TypeName: "typeName", IsPointer: true},
+		"*[]string":             &PointerSpec{Value: &ArraySpec{Value: &SimpleSpec{TypeName: "string"}}},
+		"[5]string":             &ArraySpec{Value: &SimpleSpec{TypeName: "string"}, Length: "5"},
+		"map[string]int":        &MapSpec{Key: &SimpleSpec{TypeName: "string"}, Value: &SimpleSpec{TypeName: "int"}},
+		"func(a int, b ...string) error": &FuncSpec{
+			Params: []*Field{
+				{Name: "a", Spec: &SimpleSpec{TypeName: "int"}},
+				{Name: "b", Spec: &ArraySpec{Value: &SimpleSpec{TypeName: "string"}}},
+			},
+			Results:    []*Field{{Spec: &SimpleSpec{TypeName: "error"}}},
+			IsVariadic: true,
+		},
+	}
+
+	for expected, entity := range entities {
+		fileSet := token.NewFileSet()
+
+		actual := converter.ToAST(fileSet, entity)
+
+		ctrl.AssertSame(expected, printASTNode(fileSet, actual))
+	}
+}
+
+func TestEntityASTConverter_ToAST_WithConst(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &Const{
+		Name:    "name",
+		Value:   "5",
+		Comment: "comment",
+	}
+	fileSet := token.NewFileSet()
+
+	actual := NewEntityASTConverter(NewEntityRenderer(), NewGoSourceParser(NewJSONAnnotationParser())).
+		ToAST(fileSet, entity)
+
+	ctrl.AssertSame("// comment\nname = 5", printASTNode(fileSet, actual))
+	ctrl.AssertSame("comment\n", actual.(*ast.ValueSpec).Doc.Text())
+}
+
+func TestEntityASTConverter_ToAST_WithFunc(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &Func{
+		Name:    "name",
+		Content: "return nil",
+		Spec: &FuncSpec{
+			Results: []*Field{{Spec: &SimpleSpec{TypeName: "error"}}},
+		},
+		Related: &Field{
+			Name: "m",
+			Spec: &SimpleSpec{TypeName: "model", IsPointer: true},
+		},
+	}
+	fileSet := token.NewFileSet()
+
+	actual := NewEntityASTConverter(NewEntityRenderer(), NewGoSourceParser(NewJSONAnnotationParser())).
+		ToAST(fileSet, entity)
+
+	ctrl.AssertSame("func (m *model) name() error {\n\treturn nil\n}", printASTNode(fileSet, actual))
+}
+
+func TestEntityASTConverter_ToAST_WithFile(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &File{
+		Name:        "file.go",
+		PackageName: "packageName",
+		TypeGroups: []*TypeGroup{
+			{
+				Types: []*Type{
+					{
+						Name: "model",
+						Spec: &StructSpec{
+							Fields: []*Field{{Name: "ID", Spec: &SimpleSpec{TypeName: "int"}, Tag: `json:"id"`}},
+						},
+					},
+				},
+			},
+		},
+	}
+	fileSet := token.NewFileSet()
+
+	actual := NewEntityASTConverter(NewEntityRenderer(), NewGoSourceParser(NewJSONAnnotationParser())).
+		ToAST(fileSet, entity)
+
+	ctrl.AssertSame("packageName", actual.(*ast.File).Name.Name)
+	ctrl.AssertSame(
+		"package packageName\n\ntype model struct {\n\tID int \"json:\\\"id\\\"\"\n}\n",
+		printASTNode(fileSet, actual),
+	)
+}
+
+func TestEntityASTConverter_ToAST_WithNilFileSet(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	converter := NewEntityASTConverter(NewRendererMock(ctrl), NewGoSourceParser(NewAnnotationParserMock(ctrl)))
+
+	ctrl.Subtest("").
+		Call(converter.ToAST, nil, &SimpleSpec{TypeName: "string"}).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'fileSet' must be not nil"))
+}
+
+func TestEntityASTConverter_ToAST_WithUnknownEntity(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	converter := NewEntityASTConverter(NewRendererMock(ctrl), NewGoSourceParser(NewAnnotationParserMock(ctrl)))
+
+	ctrl.Subtest("").
+		Call(converter.ToAST, token.NewFileSet(), &Namespace{}).
+		ExpectPanic(NewErrorMessageConstraint("Can't convert to ast entity with type: '*annotation.Namespace'"))
+}
+
+func TestEntityASTConverter_FromAST_WithExpr(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fileSet := token.NewFileSet()
+	node, _ := parser.ParseExprFrom(fileSet, "", "map[string][]*packageName.typeName", 0)
+	expected := &MapSpec{
+		Key: &SimpleSpec{TypeName: "string"},
+		Value: &ArraySpec{
+			Value: &SimpleSpec{PackageName: "packageName", TypeName: "typeName", IsPointer: true},
+		},
+	}
+
+	actual := NewEntityASTConverter(NewEntityRenderer(), NewGoSourceParser(NewJSONAnnotationParser())).
+		FromAST(fileSet, node)
+
+	ctrl.AssertEqual(expected, actual)
+}
+
+func TestEntityASTConverter_FromAST_WithGenDecl(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fileSet := token.NewFileSet()
+	content := "package p\n\n// comment\nvar (\n\tx int\n\ty = \"y\"\n)\n"
+	astFile, _ := parser.ParseFile(fileSet, "", content, parser.ParseComments)
+	expected := &VarGroup{
+		Comment:     "comment",
+		Annotations: []interface{}{},
+		Vars: []*Var{
+			{Name: "x", Spec: &SimpleSpec{TypeName: "int"}},
+			{Name: "y", Value: "\"y\"", Spec: &SimpleSpec{TypeName: "string"}},
+		},
+	}
+
+	actual := NewEntityASTConverter(NewEntityRenderer(), NewGoSourceParser(NewJSONAnnotationParser())).
+		FromAST(fileSet, astFile.Decls[0])
+
+	ctrl.AssertEqual(expected, actual)
+}
+
+func TestEntityASTConverter_FromAST_WithField(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fileSet := token.NewFileSet()
+	node, _ := parser.ParseExprFrom(fileSet, "", "struct{ID int `json:\"id\"`}", 0)
+	expected := &Field{
+		Name: "ID",
+		Tag:  `json:"id"`,
+		Spec: &SimpleSpec{TypeName: "int"},
+	}
+
+	actual := NewEntityASTConverter(NewEntityRenderer(), NewGoSourceParser(NewJSONAnnotationParser())).
+		FromAST(fileSet, node.(*ast.StructType).Fields.List[0])
+
+	ctrl.AssertEqual(expected, actual)
+}
+
+func TestEntityASTConverter_FromAST_WithFile(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fileSet := token.NewFileSet()
+	astFile, _ := parser.ParseFile(fileSet, "/path/file.go", "package p\n\nfunc f() {}\n", parser.ParseComments)
+	expected := &File{
+		Name:         "file.go",
+		PackageName:  "p",
+		ImportGroups: []*ImportGroup{},
+		ConstGroups:  []*ConstGroup{},
+		VarGroups:    []*VarGroup{},
+		TypeGroups:   []*TypeGroup{},
+		Funcs: []*Func{
+			{
+				Name: "f",
+				Spec: &FuncSpec{Params: []*Field{}, Results: []*Field{}},
+			},
+		},
+	}
+
+	actual := NewEntityASTConverter(NewEntityRenderer(), NewGoSourceParser(NewJSONAnnotationParser())).
+		FromAST(fileSet, astFile)
+
+	ctrl.AssertEqual(expected, actual)
+}
+
+func TestEntityASTConverter_FromAST_WithFuncComments(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := "package p\n\n" +
+		"func f() {\n" +
+		"\t// c\n" +
+		"\tif true {\n" +
+		"\t\treturn // r\n" +
+		"\t}\n" +
+		"}\n"
+	converter := NewEntityASTConverter(NewEntityRenderer(), NewGoSourceParser(NewJSONAnnotationParser()))
+	fileSet := token.NewFileSet()
+	astFile, _ := parser.ParseFile(fileSet, "/path/file.go", content, parser.ParseComments)
+
+	actual := converter.FromAST(fileSet, astFile).(*File)
+
+	ctrl.AssertSame("// c\nif true {\n\treturn // r\n}", actual.Funcs[0].Content)
+
+	// Round trip through *ast.File keeps comments
+	fileSet = token.NewFileSet()
+
+	buffer := bytes.Buffer{}
+	_ = format.Node(&buffer, fileSet, converter.ToAST(fileSet, actual))
+
+	ctrl.AssertSame(content, buffer.String())
+
+	// Standalone *ast.FuncDecl doesn't reference comments of file, so they are lost
+	fileSet = token.NewFileSet()
+	astFile, _ = parser.ParseFile(fileSet, "/path/file.go", content, parser.ParseComments)
+
+	ctrl.AssertSame("if true {\n\treturn\n}", converter.FromAST(fileSet, astFile.Decls[0]).(*Func).Content)
+}
+
+func TestEntityASTConverter_FromAST_WithConst(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &Const{
+		Name:        "name",
+		Spec:        &SimpleSpec{TypeName: "int"},
+		Value:       "5",
+		Comment:     "comment",
+		Annotations: []interface{}{},
+	}
+	fileSet := token.NewFileSet()
+	converter := NewEntityASTConverter(NewEntityRenderer(), NewGoSourceParser(NewJSONAnnotationParser()))
+
+	actual := converter.FromAST(fileSet, converter.ToAST(fileSet, entity))
+
+	ctrl.AssertEqual(entity, actual)
+}
+
+func TestEntityASTConverter_FromAST_WithVar(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &Var{
+		Name:        "name",
+		Spec:        &SimpleSpec{TypeName: "string"},
+		Comment:     "comment",
+		Annotations: []interface{}{},
+	}
+	fileSet := token.NewFileSet()
+	converter := NewEntityASTConverter(NewEntityRenderer(), NewGoSourceParser(NewJSONAnnotationParser()))
+
+	actual := converter.FromAST(fileSet, converter.ToAST(fileSet, entity))
+
+	ctrl.AssertEqual(entity, actual)
+}
+
+func TestEntityASTConverter_FromAST_WithUnresolvedValueSpec(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	node := &ast.ValueSpec{Names: []*ast.Ident{ast.NewIdent("name")}, Type: ast.NewIdent("int")}
+	converter := NewEntityASTConverter(NewEntityRenderer(), NewGoSourceParser(NewJSONAnnotationParser()))
+
+	ctrl.Subtest("").
+		Call(converter.FromAST, token.NewFileSet(), node).
+		ExpectPanic(NewErrorMessageConstraint("Can't detect token of *ast.ValueSpec, use ValueSpecFromAST"))
+
+	actual := converter.ValueSpecFromAST(token.NewFileSet(), node, token.VAR)
+
+	ctrl.AssertEqual(&Var{Name: "name", Spec: &SimpleSpec{TypeName: "int"}}, actual)
+}
+
+func TestEntityASTConverter_ValueSpecFromAST_WithUnknownToken(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	node := &ast.ValueSpec{Names: []*ast.Ident{ast.NewIdent("name")}, Type: ast.NewIdent("int")}
+	converter := NewEntityASTConverter(NewEntityRenderer(), NewGoSourceParser(NewJSONAnnotationParser()))
+
+	ctrl.Subtest("").
+		Call(converter.ValueSpecFromAST, token.NewFileSet(), node, token.TYPE).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'tok' has unknown value: 'type'"))
+}
+
+func TestEntityASTConverter_FromAST_WithUnknownNode(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	converter := NewEntityASTConverter(NewRendererMock(ctrl), NewGoSourceParser(NewAnnotationParserMock(ctrl)))
+
+	ctrl.Subtest("").
+		Call(converter.FromAST, token.NewFileSet(), &ast.BasicLit{}).
+		ExpectPanic(NewErrorMessageConstraint("Can't convert from ast node with type: '*ast.BasicLit'"))
+}
+
+func printASTNode(fileSet *token.FileSet, node ast.Node) string {
+	buffer := bytes.Buffer{}
+	_ = printer.Fprint(&buffer, fileSet, node)
+
+	return buffer.String()
+}
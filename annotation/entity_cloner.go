@@ -19,6 +19,8 @@ func (c *EntityCloner) Clone(entity interface{}) interface{} {
 	switch entity := entity.(type) {
 	case *SimpleSpec:
 		return c.cloneSimpleSpec(entity)
+	case *PointerSpec:
+		return c.clonePointerSpec(entity)
 	case *ArraySpec:
 		return c.cloneArraySpec(entity)
 	case *MapSpec:
@@ -68,6 +70,12 @@ func (c *EntityCloner) cloneSimpleSpec(entity *SimpleSpec) interface{} {
 	}
 }
 
+func (c *EntityCloner) clonePointerSpec(entity *PointerSpec) interface{} {
+	return &PointerSpec{
+		Value: c.Clone(entity.Value),
+	}
+}
+
 func (c *EntityCloner) cloneArraySpec(entity *ArraySpec) interface{} {
 	return &ArraySpec{
 		Value:  c.Clone(entity.Value),
@@ -89,6 +97,7 @@ func (c *EntityCloner) cloneField(entity *Field) interface{} {
 		Comment:     entity.Comment,
 		Annotations: c.cloneAnnotations(entity.Annotations),
 		Spec:        c.Clone(entity.Spec),
+		IsEmbedded:  entity.IsEmbedded,
 	}
 }
 
@@ -298,6 +307,8 @@ func (c *EntityCloner) cloneFile(entity *File) interface{} {
 		PackageName: entity.PackageName,
 		Comment:     entity.Comment,
 		Annotations: c.cloneAnnotations(entity.Annotations),
+		// Layout is not changed after parse, so it's shared
+		Layout: entity.Layout,
 	}
 
 	if entity.ImportGroups != nil {
@@ -345,9 +356,10 @@ func (c *EntityCloner) cloneFile(entity *File) interface{} {
 
 func (c *EntityCloner) cloneNamespace(entity *Namespace) interface{} {
 	result := &Namespace{
-		Name:      entity.Name,
-		Path:      entity.Path,
-		IsIgnored: entity.IsIgnored,
+		Name:       entity.Name,
+		Path:       entity.Path,
+		IsIgnored:  entity.IsIgnored,
+		IsReadOnly: entity.IsReadOnly,
 	}
 
 	if entity.Files != nil {
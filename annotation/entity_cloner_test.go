@@ -35,6 +35,25 @@ func TestEntityCloner_Clone_WithSimpleSpec(t *testing.T) {
 	ctrl.AssertNotSame(entity, actual)
 }
 
+func TestEntityCloner_Clone_WithPointerSpec(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &PointerSpec{
+		Value: &ArraySpec{
+			Value: &SimpleSpec{
+				TypeName: "valueTypeName",
+			},
+		},
+	}
+
+	actual := (&EntityCloner{}).Clone(entity)
+
+	ctrl.AssertEqual(entity, actual)
+	ctrl.AssertNotSame(entity, actual)
+	ctrl.AssertNotSame(entity.Value, actual.(*PointerSpec).Value)
+}
+
 func TestEntityCloner_Clone_WithArraySpec(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
@@ -90,6 +109,7 @@ func TestEntityCloner_Clone_WithField(t *testing.T) {
 		Spec: &SimpleSpec{
 			TypeName: "fieldSpecTypeName",
 		},
+		IsEmbedded: true,
 	}
 
 	actual := (&EntityCloner{}).Clone(entity)
@@ -661,6 +681,9 @@ func TestEntityCloner_Clone_WithFile(t *testing.T) {
 				Name: "funcName2",
 			},
 		},
+		Layout: &FileLayout{
+			Header: "package filePackageName",
+		},
 	}
 
 	actual := (&EntityCloner{}).Clone(entity)
@@ -678,6 +701,7 @@ func TestEntityCloner_Clone_WithFile(t *testing.T) {
 	ctrl.AssertNotSame(entity.TypeGroups[1], actual.(*File).TypeGroups[1])
 	ctrl.AssertNotSame(entity.Funcs[0], actual.(*File).Funcs[0])
 	ctrl.AssertNotSame(entity.Funcs[1], actual.(*File).Funcs[1])
+	ctrl.AssertSame(entity.Layout, actual.(*File).Layout)
 }
 
 func TestEntityCloner_Clone_WithFileAndEmptyFields(t *testing.T) {
@@ -700,9 +724,10 @@ func TestEntityCloner_Clone_WithNamespace(t *testing.T) {
 	defer ctrl.Finish()
 
 	entity := &Namespace{
-		Name:      "namespace/alias",
-		Path:      "/namespace/path",
-		IsIgnored: true,
+		Name:       "namespace/alias",
+		Path:       "/namespace/path",
+		IsIgnored:  true,
+		IsReadOnly: true,
 		Files: []*File{
 			{
 				Name:        "file1Name.go",
@@ -0,0 +1,145 @@
+package annotation
+
+import (
+	"github.com/pkg/errors"
+)
+
+// EntityContext stores parents of entities of Storage, model of entities is not changed.
+// It's filled by ContextIndexer and answers which namespace, file or declaration owns any entity, including specs
+// nested in maps, arrays and funcs. If the same entity is used in several places, the last indexed place is stored.
+type EntityContext struct {
+	entries map[interface{}][]interface{}
+}
+
+// Creates new instance of EntityContext.
+func NewEntityContext() *EntityContext {
+	return &EntityContext{
+		entries: map[interface{}][]interface{}{},
+	}
+}
+
+// Returns parents of entity ordered from root to direct parent, or nil if entity is not indexed.
+func (c *EntityContext) Parents(entity interface{}) []interface{} {
+	if entity == nil {
+		panic(errors.New("Variable 'entity' must be not nil"))
+	}
+
+	return c.entries[entity]
+}
+
+// Returns direct parent of entity, or nil if entity is not indexed or it's root.
+func (c *EntityContext) Parent(entity interface{}) interface{} {
+	parents := c.Parents(entity)
+
+	if len(parents) == 0 {
+		return nil
+	}
+
+	return parents[len(parents)-1]
+}
+
+// Returns namespace, which contains entity, or nil.
+func (c *EntityContext) Namespace(entity interface{}) *Namespace {
+	result, _ := c.nearest(entity, func(parent interface{}) bool {
+		_, ok := parent.(*Namespace)
+
+		return ok
+	}).(*Namespace)
+
+	return result
+}
+
+// Returns file, which contains entity, or nil.
+func (c *EntityContext) File(entity interface{}) *File {
+	result, _ := c.nearest(entity, func(parent interface{}) bool {
+		_, ok := parent.(*File)
+
+		return ok
+	}).(*File)
+
+	return result
+}
+
+// Returns nearest type declaration, which contains entity, or nil.
+func (c *EntityContext) Type(entity interface{}) *Type {
+	result, _ := c.nearest(entity, func(parent interface{}) bool {
+		_, ok := parent.(*Type)
+
+		return ok
+	}).(*Type)
+
+	return result
+}
+
+// Returns nearest func declaration, which contains entity, or nil.
+func (c *EntityContext) Func(entity interface{}) *Func {
+	result, _ := c.nearest(entity, func(parent interface{}) bool {
+		_, ok := parent.(*Func)
+
+		return ok
+	}).(*Func)
+
+	return result
+}
+
+// Returns nearest declaration (*Import, *Const, *Var, *Type or *Func), which contains entity, or nil.
+func (c *EntityContext) Owner(entity interface{}) interface{} {
+	return c.nearest(entity, func(parent interface{}) bool {
+		switch parent.(type) {
+		case *Import, *Const, *Var, *Type, *Func:
+			return true
+		default:
+			return false
+		}
+	})
+}
+
+// Stores parents of entity ordered from root to direct parent.
+func (c *EntityContext) Set(entity interface{}, parents []interface{}) {
+	if entity == nil {
+		panic(errors.New("Variable 'entity' must be not nil"))
+	}
+
+	if parents == nil {
+		panic(errors.New("Variable 'parents' must be not nil"))
+	}
+
+	c.entries[entity] = parents
+}
+
+// Removes entity and all entities, which are contained by it.
+func (c *EntityContext) Remove(entity interface{}) {
+	if entity == nil {
+		panic(errors.New("Variable 'entity' must be not nil"))
+	}
+
+	delete(c.entries, entity)
+
+	for child, parents := range c.entries {
+		for _, parent := range parents {
+			if parent == entity {
+				delete(c.entries, child)
+
+				break
+			}
+		}
+	}
+}
+
+// Returns nearest parent of entity, which matches callback, or nil.
+func (c *EntityContext) nearest(entity interface{}, match func(parent interface{}) bool) interface{} {
+	parents := c.Parents(entity)
+
+	for i := len(parents) - 1; i >= 0; i-- {
+		if match(parents[i]) {
+			return parents[i]
+		}
+	}
+
+	return nil
+}
+
+// Removes all entities.
+func (c *EntityContext) Reset() {
+	c.entries = map[interface{}][]interface{}{}
+}
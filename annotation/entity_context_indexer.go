@@ -0,0 +1,64 @@
+package annotation
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Fills EntityContext by parents of Storage entities.
+type EntityContextIndexer struct {
+	walker Walker
+}
+
+// Creates new instance of EntityContextIndexer.
+func NewEntityContextIndexer(walker Walker) *EntityContextIndexer {
+	if walker == nil {
+		panic(errors.New("Variable 'walker' must be not nil"))
+	}
+
+	return &EntityContextIndexer{
+		walker: walker,
+	}
+}
+
+// Rebuilds context by all entities of storage, previous entries are removed.
+func (i *EntityContextIndexer) Index(storage *Storage, context *EntityContext) {
+	if storage == nil {
+		panic(errors.New("Variable 'storage' must be not nil"))
+	}
+
+	if context == nil {
+		panic(errors.New("Variable 'context' must be not nil"))
+	}
+
+	context.Reset()
+	i.index(storage, []interface{}{}, context)
+}
+
+// Updates context after entity or its children were changed. Entity must be already indexed, so to index new entity
+// its parent must be updated. Entries of removed children are removed too.
+func (i *EntityContextIndexer) Update(entity interface{}, context *EntityContext) {
+	if entity == nil {
+		panic(errors.New("Variable 'entity' must be not nil"))
+	}
+
+	if context == nil {
+		panic(errors.New("Variable 'context' must be not nil"))
+	}
+
+	parents := context.Parents(entity)
+
+	if parents == nil {
+		panic(errors.Errorf("Entity with type '%T' is not indexed", entity))
+	}
+
+	context.Remove(entity)
+	i.index(entity, parents, context)
+}
+
+func (i *EntityContextIndexer) index(entity interface{}, parents []interface{}, context *EntityContext) {
+	i.walker.Inspect(entity, func(child interface{}, childParents []interface{}) bool {
+		context.Set(child, append(parents[:len(parents):len(parents)], childParents...))
+
+		return true
+	})
+}
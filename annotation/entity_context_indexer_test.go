@@ -0,0 +1,139 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestNewEntityContextIndexer(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	walker := NewEntityWalker()
+
+	actual := NewEntityContextIndexer(walker)
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(walker, actual.walker)
+}
+
+func TestNewEntityContextIndexer_WithNilWalker(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityContextIndexer, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'walker' must be not nil"))
+}
+
+func TestEntityContextIndexer_Index(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	mapValue := &SimpleSpec{TypeName: "string"}
+	mapSpec := &MapSpec{Key: &SimpleSpec{TypeName: "string"}, Value: &ArraySpec{Value: mapValue}}
+	param := &Field{Name: "value", Spec: mapSpec}
+	funcSpec := &FuncSpec{Params: []*Field{param}}
+	funcEntity := &Func{Name: "Set", Spec: funcSpec}
+	field := &Field{Name: "Callback", Spec: &FuncSpec{Results: []*Field{{Spec: &SimpleSpec{TypeName: "error"}}}}}
+	typeEntity := &Type{Name: "Model", Spec: &StructSpec{Fields: []*Field{field}}}
+	file := &File{
+		Name:        "file.go",
+		PackageName: "model",
+		TypeGroups:  []*TypeGroup{{Types: []*Type{typeEntity}}},
+		Funcs:       []*Func{funcEntity},
+	}
+	namespace := &Namespace{Name: "model", Path: "/model", Files: []*File{file}}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+	context := NewEntityContext()
+	stale := &Field{Name: "Stale"}
+
+	context.Set(stale, []interface{}{storage})
+
+	NewEntityContextIndexer(NewEntityWalker()).Index(storage, context)
+
+	ctrl.AssertNil(context.Parents(stale))
+	ctrl.AssertEqual([]interface{}{}, context.Parents(storage))
+	ctrl.AssertSame(storage, context.Parent(namespace))
+	ctrl.AssertSame(namespace, context.Namespace(mapValue))
+	ctrl.AssertSame(file, context.File(mapValue))
+	ctrl.AssertSame(funcEntity, context.Func(mapValue))
+	ctrl.AssertSame(funcEntity, context.Owner(mapValue))
+	ctrl.AssertSame(param, context.Parent(mapSpec))
+	ctrl.AssertSame(typeEntity, context.Type(field.Spec.(*FuncSpec).Results[0].Spec))
+	ctrl.AssertSame(field, context.Parent(field.Spec))
+}
+
+func TestEntityContextIndexer_Index_WithNilStorage(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityContextIndexer(NewEntityWalker()).Index, nil, NewEntityContext()).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'storage' must be not nil"))
+}
+
+func TestEntityContextIndexer_Index_WithNilContext(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityContextIndexer(NewEntityWalker()).Index, &Storage{}, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'context' must be not nil"))
+}
+
+func TestEntityContextIndexer_Update(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	removed := &Field{Name: "Removed", Spec: &SimpleSpec{TypeName: "int"}}
+	structSpec := &StructSpec{Fields: []*Field{removed}}
+	typeEntity := &Type{Name: "Model", Spec: structSpec}
+	file := &File{Name: "file.go", PackageName: "model", TypeGroups: []*TypeGroup{{Types: []*Type{typeEntity}}}}
+	namespace := &Namespace{Name: "model", Path: "/model", Files: []*File{file}}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+	context := NewEntityContext()
+	indexer := NewEntityContextIndexer(NewEntityWalker())
+
+	indexer.Index(storage, context)
+
+	added := &Field{Name: "Added", Spec: &SimpleSpec{TypeName: "string"}}
+	structSpec.Fields = []*Field{added}
+
+	indexer.Update(typeEntity, context)
+
+	ctrl.AssertNil(context.Parents(removed))
+	ctrl.AssertNil(context.Parents(removed.Spec))
+	ctrl.AssertEqual([]interface{}{storage, namespace, file, file.TypeGroups[0], typeEntity}, context.Parents(structSpec))
+	ctrl.AssertSame(typeEntity, context.Type(added.Spec))
+	ctrl.AssertSame(namespace, context.Namespace(added))
+	ctrl.AssertSame(storage, context.Parent(namespace))
+}
+
+func TestEntityContextIndexer_Update_WithNotIndexedEntity(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityContextIndexer(NewEntityWalker()).Update, &Field{}, NewEntityContext()).
+		ExpectPanic(NewErrorMessageConstraint("Entity with type '*annotation.Field' is not indexed"))
+}
+
+func TestEntityContextIndexer_Update_WithNilEntity(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityContextIndexer(NewEntityWalker()).Update, nil, NewEntityContext()).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'entity' must be not nil"))
+}
+
+func TestEntityContextIndexer_Update_WithNilContext(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityContextIndexer(NewEntityWalker()).Update, &Field{}, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'context' must be not nil"))
+}
@@ -0,0 +1,78 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestEntityContext(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	spec := &SimpleSpec{TypeName: "int"}
+	field := &Field{Name: "ID", Spec: spec}
+	structSpec := &StructSpec{Fields: []*Field{field}}
+	typeEntity := &Type{Name: "Model", Spec: structSpec}
+	file := &File{Name: "file.go"}
+	namespace := &Namespace{Name: "namespace"}
+	storage := &Storage{}
+
+	model := NewEntityContext()
+	model.Set(storage, []interface{}{})
+	model.Set(namespace, []interface{}{storage})
+	model.Set(file, []interface{}{storage, namespace})
+	model.Set(typeEntity, []interface{}{storage, namespace, file})
+	model.Set(structSpec, []interface{}{storage, namespace, file, typeEntity})
+	model.Set(field, []interface{}{storage, namespace, file, typeEntity, structSpec})
+	model.Set(spec, []interface{}{storage, namespace, file, typeEntity, structSpec, field})
+
+	ctrl.AssertEqual([]interface{}{storage, namespace, file, typeEntity, structSpec}, model.Parents(field))
+	ctrl.AssertSame(structSpec, model.Parent(field))
+	ctrl.AssertSame(namespace, model.Namespace(spec))
+	ctrl.AssertSame(file, model.File(spec))
+	ctrl.AssertSame(typeEntity, model.Type(spec))
+	ctrl.AssertSame(typeEntity, model.Owner(spec))
+	ctrl.AssertNil(model.Func(spec))
+	ctrl.AssertNil(model.Parent(storage))
+	ctrl.AssertNil(model.Namespace(namespace))
+	ctrl.AssertNil(model.Parents(&Field{}))
+	ctrl.AssertNil(model.Owner(&Field{}))
+
+	model.Remove(structSpec)
+
+	ctrl.AssertNil(model.Parents(structSpec))
+	ctrl.AssertNil(model.Parents(field))
+	ctrl.AssertNil(model.Parents(spec))
+	ctrl.AssertSame(file, model.Parent(typeEntity))
+
+	model.Reset()
+
+	ctrl.AssertNil(model.Parents(storage))
+}
+
+func TestEntityContext_WithNilEntity(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	model := NewEntityContext()
+
+	ctrl.Subtest("Parents").
+		Call(model.Parents, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'entity' must be not nil"))
+	ctrl.Subtest("Set").
+		Call(model.Set, nil, []interface{}{}).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'entity' must be not nil"))
+	ctrl.Subtest("Remove").
+		Call(model.Remove, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'entity' must be not nil"))
+}
+
+func TestEntityContext_Set_WithNilParents(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityContext().Set, &Field{}, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'parents' must be not nil"))
+}
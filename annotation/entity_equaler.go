@@ -13,6 +13,10 @@ func (c *EntityEqualer) Equal(x interface{}, y interface{}) bool {
 		if yValue, ok := y.(*SimpleSpec); ok {
 			return c.equalSimpleSpec(x, yValue)
 		}
+	case *PointerSpec:
+		if yValue, ok := y.(*PointerSpec); ok {
+			return c.equalPointerSpec(x, yValue)
+		}
 	case *ArraySpec:
 		if yValue, ok := y.(*ArraySpec); ok {
 			return c.equalArraySpec(x, yValue)
@@ -82,6 +86,10 @@ func (c *EntityEqualer) equalSimpleSpec(x *SimpleSpec, y *SimpleSpec) bool {
 	return y.PackageName == x.PackageName && y.TypeName == x.TypeName && y.IsPointer == x.IsPointer
 }
 
+func (c *EntityEqualer) equalPointerSpec(x *PointerSpec, y *PointerSpec) bool {
+	return c.Equal(x.Value, y.Value)
+}
+
 func (c *EntityEqualer) equalArraySpec(x *ArraySpec, y *ArraySpec) bool {
 	return y.Length == x.Length && c.Equal(x.Value, y.Value)
 }
@@ -91,7 +99,7 @@ func (c *EntityEqualer) equalMapSpec(x *MapSpec, y *MapSpec) bool {
 }
 
 func (c *EntityEqualer) equalField(x *Field, y *Field) bool {
-	return x.Name == y.Name && x.Tag == y.Tag && c.Equal(x.Spec, y.Spec)
+	return x.Name == y.Name && x.Tag == y.Tag && x.IsEmbedded == y.IsEmbedded && c.Equal(x.Spec, y.Spec)
 }
 
 func (c *EntityEqualer) equalFuncSpec(x *FuncSpec, y *FuncSpec) bool {
@@ -110,6 +110,56 @@ func TestEntityEqualer_Equal_WithSimpleSpecAndIsPointer(t *testing.T) {
 	ctrl.AssertFalse(actual)
 }
 
+func TestEntityEqualer_Equal_WithPointerSpec(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	x := &PointerSpec{
+		Value: &ArraySpec{
+			Value: &SimpleSpec{
+				TypeName: "typeName",
+			},
+		},
+	}
+
+	y := &PointerSpec{
+		Value: &ArraySpec{
+			Value: &SimpleSpec{
+				TypeName: "typeName",
+			},
+		},
+	}
+
+	actual := (&EntityEqualer{}).Equal(x, y)
+
+	ctrl.AssertTrue(actual)
+}
+
+func TestEntityEqualer_Equal_WithPointerSpecAndValue(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	x := &PointerSpec{
+		Value: &ArraySpec{
+			Value: &SimpleSpec{
+				TypeName: "typeName",
+			},
+		},
+	}
+
+	y := &PointerSpec{
+		Value: &ArraySpec{
+			Value: &SimpleSpec{
+				TypeName: "another",
+			},
+		},
+	}
+
+	actual := (&EntityEqualer{}).Equal(x, y)
+
+	ctrl.AssertFalse(actual)
+}
+
 func TestEntityEqualer_Equal_WithArraySpec(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
@@ -361,6 +411,28 @@ func TestEntityEqualer_Equal_WithFieldAndTag(t *testing.T) {
 	ctrl.AssertFalse(actual)
 }
 
+func TestEntityEqualer_Equal_WithFieldAndIsEmbedded(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	x := &Field{
+		Spec: &SimpleSpec{
+			TypeName: "typeName",
+		},
+		IsEmbedded: true,
+	}
+
+	y := &Field{
+		Spec: &SimpleSpec{
+			TypeName: "typeName",
+		},
+	}
+
+	actual := (&EntityEqualer{}).Equal(x, y)
+
+	ctrl.AssertFalse(actual)
+}
+
 func TestEntityEqualer_Equal_WithFieldAndSpec(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
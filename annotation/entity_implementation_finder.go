@@ -0,0 +1,212 @@
+package annotation
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Keys of predeclared aliases are keys of their types, e.g. any is the same as interface{}.
+var predeclaredAliasKeys = map[string]string{
+	"any":  "interface{}",
+	"byte": "uint8",
+	"rune": "int32",
+}
+
+// Finds types in Storage, which implement interface.
+type EntityImplementationFinder struct {
+	methodSetFetcher MethodSetFetcher
+}
+
+// Creates new instance of EntityImplementationFinder.
+func NewEntityImplementationFinder(methodSetFetcher MethodSetFetcher) *EntityImplementationFinder {
+	if methodSetFetcher == nil {
+		panic(errors.New("Variable 'methodSetFetcher' must be not nil"))
+	}
+
+	return &EntityImplementationFinder{
+		methodSetFetcher: methodSetFetcher,
+	}
+}
+
+// Returns all types of storage, which implement interface. Argument spec must be *InterfaceSpec or *SimpleSpec, which
+// references named interface, file is context of spec. Methods are compared by names and signatures, packages of
+// signatures are compared by namespaces of imports, so different aliases of the same import are equal.
+// Predeclared aliases are equal to their types, e.g. byte to uint8 and any to interface{}.
+func (f *EntityImplementationFinder) Find(storage *Storage, file *File, spec interface{}) []*Implementation {
+	if storage == nil {
+		panic(errors.New("Variable 'storage' must be not nil"))
+	}
+
+	if file == nil {
+		panic(errors.New("Variable 'file' must be not nil"))
+	}
+
+	var reference *TypeReference
+
+	switch spec := spec.(type) {
+	case *SimpleSpec:
+		if reference = storage.ResolveType(file, spec); reference == nil {
+			panic(errors.Errorf("Type '%s' is not found", spec.TypeName))
+		}
+	case *InterfaceSpec:
+		reference = &TypeReference{
+			Namespace: storage.FindNamespaceByFile(file),
+			File:      file,
+			Type:      &Type{Spec: spec},
+		}
+	default:
+		panic(errors.Errorf("Variable 'spec' has invalid type: '%T'", spec))
+	}
+
+	required := f.methodSetFetcher.Fetch(storage, reference, false)
+	candidates := []*TypeReference{}
+
+	// Method sets are fetched after read, because storage methods could not be called under its lock
+	storage.Read(func() {
+		for _, namespace := range storage.Namespaces {
+			for _, typeFile := range namespace.Files {
+				for _, typeGroup := range typeFile.TypeGroups {
+					for _, element := range typeGroup.Types {
+						if element != reference.Type {
+							candidates = append(
+								candidates,
+								&TypeReference{Namespace: namespace, File: typeFile, Type: element},
+							)
+						}
+					}
+				}
+			}
+		}
+	})
+
+	result := []*Implementation{}
+
+	for _, candidate := range candidates {
+		implementation := &Implementation{Namespace: candidate.Namespace, File: candidate.File, Type: candidate.Type}
+
+		if f.implements(storage, required, f.methodSetFetcher.Fetch(storage, candidate, false)) {
+			result = append(result, implementation)
+		} else if f.implements(storage, required, f.methodSetFetcher.Fetch(storage, candidate, true)) {
+			implementation.IsPointer = true
+			result = append(result, implementation)
+		}
+	}
+
+	return result
+}
+
+func (f *EntityImplementationFinder) implements(storage *Storage, required []*Method, methods []*Method) bool {
+	byName := map[string]*Method{}
+
+	for _, method := range methods {
+		byName[method.Name] = method
+	}
+
+	for _, method := range required {
+		candidate, ok := byName[method.Name]
+
+		if !ok || f.specKey(storage, method, method.Spec) != f.specKey(storage, candidate, candidate.Spec) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Builds canonical representation of spec, where package names are replaced by namespace names.
+func (f *EntityImplementationFinder) specKey(storage *Storage, context *Method, spec interface{}) string {
+	switch spec := spec.(type) {
+	case *SimpleSpec:
+		return f.simpleSpecKey(storage, context, spec)
+	case *PointerSpec:
+		return "*" + f.specKey(storage, context, spec.Value)
+	case *ArraySpec:
+		return "[" + spec.Length + "]" + f.specKey(storage, context, spec.Value)
+	case *MapSpec:
+		return "map[" + f.specKey(storage, context, spec.Key) + "]" + f.specKey(storage, context, spec.Value)
+	case *FuncSpec:
+		params := make([]string, len(spec.Params))
+		results := make([]string, len(spec.Results))
+
+		for i, field := range spec.Params {
+			params[i] = f.specKey(storage, context, field.Spec)
+		}
+
+		for i, field := range spec.Results {
+			results[i] = f.specKey(storage, context, field.Spec)
+		}
+
+		return "func(" + strings.Join(params, ",") + "," + strconv.FormatBool(spec.IsVariadic) + ")(" +
+			strings.Join(results, ",") + ")"
+	case *StructSpec:
+		fields := make([]string, len(spec.Fields))
+
+		for i, field := range spec.Fields {
+			fields[i] = field.Name + " " + f.specKey(storage, context, field.Spec) + " " + strconv.Quote(field.Tag)
+		}
+
+		return "struct{" + strings.Join(fields, ";") + "}"
+	case *InterfaceSpec:
+		fields := make([]string, len(spec.Fields))
+
+		for i, field := range spec.Fields {
+			fields[i] = field.Name + f.specKey(storage, context, field.Spec)
+		}
+
+		return "interface{" + strings.Join(fields, ";") + "}"
+	default:
+		return ""
+	}
+}
+
+func (f *EntityImplementationFinder) simpleSpecKey(storage *Storage, context *Method, spec *SimpleSpec) string {
+	result := ""
+
+	if spec.IsPointer {
+		result = "*"
+	}
+
+	if context.File == nil {
+		if spec.PackageName == "" {
+			return result + f.predeclaredKey(spec.TypeName)
+		}
+
+		return result + spec.PackageName + "." + spec.TypeName
+	}
+
+	if reference := storage.ResolveType(context.File, spec); reference != nil {
+		if reference.IsPredeclared {
+			return result + f.predeclaredKey(spec.TypeName)
+		}
+
+		return result + reference.Namespace.Name + "." + spec.TypeName
+	}
+
+	if spec.PackageName == "" {
+		if context.Namespace != nil {
+			return result + context.Namespace.Name + "." + spec.TypeName
+		}
+
+		return result + spec.TypeName
+	}
+
+	for _, importGroup := range context.File.ImportGroups {
+		for _, element := range importGroup.Imports {
+			if element.RealAlias() == spec.PackageName {
+				return result + element.Namespace + "." + spec.TypeName
+			}
+		}
+	}
+
+	return result + spec.PackageName + "." + spec.TypeName
+}
+
+func (f *EntityImplementationFinder) predeclaredKey(typeName string) string {
+	if key, ok := predeclaredAliasKeys[typeName]; ok {
+		return key
+	}
+
+	return typeName
+}
@@ -0,0 +1,234 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestNewEntityImplementationFinder(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	methodSetFetcher := NewEntityMethodSetFetcher()
+
+	actual := NewEntityImplementationFinder(methodSetFetcher)
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(methodSetFetcher, actual.methodSetFetcher)
+}
+
+func TestNewEntityImplementationFinder_WithNilMethodSetFetcher(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityImplementationFinder, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'methodSetFetcher' must be not nil"))
+}
+
+func TestEntityImplementationFinder_Find(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	sourceParser := NewGoSourceParser(NewJSONAnnotationParser())
+	contractFile := sourceParser.Parse("contract.go", `package contract
+
+import "example.com/entity"
+
+type Saver interface {
+	Save(model *entity.Model, force bool) error
+}
+`)
+	entityFile := sourceParser.Parse("entity.go", `package entity
+
+type Model struct{}
+`)
+	storageFile := sourceParser.Parse("storage.go", `package storage
+
+import model "example.com/entity"
+
+type ValueSaver struct{}
+
+func (s ValueSaver) Save(m *model.Model, f bool) error {
+	return nil
+}
+
+type PointerSaver struct{}
+
+func (s *PointerSaver) Save(m *model.Model, f bool) error {
+	return nil
+}
+
+type EmbeddedSaver struct {
+	ValueSaver
+}
+
+type WrongSaver struct{}
+
+func (s WrongSaver) Save(m model.Model, f bool) error {
+	return nil
+}
+`)
+	contractNamespace := &Namespace{Name: "example.com/contract", Path: "/contract", Files: []*File{contractFile}}
+	entityNamespace := &Namespace{Name: "example.com/entity", Path: "/entity", Files: []*File{entityFile}}
+	storageNamespace := &Namespace{Name: "example.com/storage", Path: "/storage", Files: []*File{storageFile}}
+	storage := &Storage{Namespaces: []*Namespace{contractNamespace, entityNamespace, storageNamespace}}
+
+	expected := []*Implementation{
+		{Namespace: storageNamespace, File: storageFile, Type: storageFile.TypeGroups[0].Types[0]},
+		{Namespace: storageNamespace, File: storageFile, Type: storageFile.TypeGroups[1].Types[0], IsPointer: true},
+		{Namespace: storageNamespace, File: storageFile, Type: storageFile.TypeGroups[2].Types[0]},
+	}
+
+	finder := NewEntityImplementationFinder(NewEntityMethodSetFetcher())
+
+	ctrl.AssertEqual(expected, finder.Find(storage, contractFile, &SimpleSpec{TypeName: "Saver"}))
+
+	// Named interface implements the same interface literal
+	expected = append(
+		[]*Implementation{{Namespace: contractNamespace, File: contractFile, Type: contractFile.TypeGroups[0].Types[0]}},
+		expected...,
+	)
+
+	ctrl.AssertEqual(
+		expected,
+		finder.Find(storage, contractFile, contractFile.TypeGroups[0].Types[0].Spec.(*InterfaceSpec)),
+	)
+}
+
+func TestEntityImplementationFinder_Find_WithError(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	file := NewGoSourceParser(NewJSONAnnotationParser()).Parse("model.go", `package model
+
+type Failure string
+
+func (f Failure) Error() string {
+	return string(f)
+}
+
+type Other string
+`)
+	namespace := &Namespace{Name: "example.com/model", Path: "/model", Files: []*File{file}}
+
+	actual := NewEntityImplementationFinder(NewEntityMethodSetFetcher()).Find(
+		&Storage{Namespaces: []*Namespace{namespace}},
+		file,
+		&SimpleSpec{TypeName: "error"},
+	)
+
+	ctrl.AssertEqual([]*Implementation{{Namespace: namespace, File: file, Type: file.TypeGroups[0].Types[0]}}, actual)
+}
+
+func TestEntityImplementationFinder_Find_WithPointerSpec(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	file := NewGoSourceParser(NewJSONAnnotationParser()).Parse("model.go", `package model
+
+type Loader interface {
+	Load(target *[]string) error
+}
+
+type SliceLoader struct{}
+
+func (l SliceLoader) Load(t *[]string) error {
+	return nil
+}
+
+type WrongLoader struct{}
+
+func (l WrongLoader) Load(t *map[string]string) error {
+	return nil
+}
+`)
+	namespace := &Namespace{Name: "example.com/model", Path: "/model", Files: []*File{file}}
+
+	actual := NewEntityImplementationFinder(NewEntityMethodSetFetcher()).Find(
+		&Storage{Namespaces: []*Namespace{namespace}},
+		file,
+		&SimpleSpec{TypeName: "Loader"},
+	)
+
+	expected := []*Implementation{{Namespace: namespace, File: file, Type: file.TypeGroups[1].Types[0]}}
+
+	ctrl.AssertEqual(expected, actual)
+}
+
+func TestEntityImplementationFinder_Find_WithPredeclaredAliases(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	file := NewGoSourceParser(NewJSONAnnotationParser()).Parse("model.go", `package model
+
+type Handler interface {
+	Handle(data []byte, value any) rune
+}
+
+type AliasHandler struct{}
+
+func (h AliasHandler) Handle(data []uint8, value interface{}) int32 {
+	return 0
+}
+
+type WrongHandler struct{}
+
+func (h WrongHandler) Handle(data []int8, value interface{}) int32 {
+	return 0
+}
+`)
+	namespace := &Namespace{Name: "example.com/model", Path: "/model", Files: []*File{file}}
+
+	actual := NewEntityImplementationFinder(NewEntityMethodSetFetcher()).Find(
+		&Storage{Namespaces: []*Namespace{namespace}},
+		file,
+		&SimpleSpec{TypeName: "Handler"},
+	)
+
+	expected := []*Implementation{{Namespace: namespace, File: file, Type: file.TypeGroups[1].Types[0]}}
+
+	ctrl.AssertEqual(expected, actual)
+}
+
+func TestEntityImplementationFinder_Find_WithNilStorage(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityImplementationFinder(NewEntityMethodSetFetcher()).Find, nil, &File{}, &InterfaceSpec{}).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'storage' must be not nil"))
+}
+
+func TestEntityImplementationFinder_Find_WithNilFile(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityImplementationFinder(NewEntityMethodSetFetcher()).Find, &Storage{}, nil, &InterfaceSpec{}).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'file' must be not nil"))
+}
+
+func TestEntityImplementationFinder_Find_WithNotFoundType(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(
+			NewEntityImplementationFinder(NewEntityMethodSetFetcher()).Find,
+			&Storage{},
+			&File{},
+			&SimpleSpec{TypeName: "Unknown"},
+		).
+		ExpectPanic(NewErrorMessageConstraint("Type 'Unknown' is not found"))
+}
+
+func TestEntityImplementationFinder_Find_WithInvalidSpec(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityImplementationFinder(NewEntityMethodSetFetcher()).Find, &Storage{}, &File{}, &MapSpec{}).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'spec' has invalid type: '*annotation.MapSpec'"))
+}
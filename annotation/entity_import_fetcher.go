@@ -24,6 +24,8 @@ func (f *EntityImportFetcher) Fetch(file *File, entity interface{}) []*Import {
 	switch entity := entity.(type) {
 	case *SimpleSpec:
 		return f.fetchSimpleSpec(file, entity)
+	case *PointerSpec:
+		return f.Fetch(file, entity.Value)
 	case *ArraySpec:
 		return f.fetchArraySpec(file, entity)
 	case *MapSpec:
@@ -120,6 +120,49 @@ func TestImportFetcher_Fetch_WithSimpleSpecAndPackageNameAndNotFound(t *testing.
 	ctrl.AssertEmpty(actual)
 }
 
+func TestImportFetcher_Fetch_WithPointerSpec(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	file := &File{
+		ImportGroups: []*ImportGroup{
+			{
+				Imports: []*Import{
+					{
+						Namespace: "namespace/packageName",
+					},
+				},
+			},
+		},
+	}
+
+	expected := []*Import{
+		file.ImportGroups[0].Imports[0],
+	}
+
+	entity := &PointerSpec{
+		Value: &ArraySpec{
+			Value: &SimpleSpec{
+				PackageName: "packageName",
+				TypeName:    "typeName",
+			},
+		},
+	}
+
+	importUniquer := NewImportUniquerMock(ctrl)
+
+	entityImportFetcher := &EntityImportFetcher{importUniquer: importUniquer}
+
+	importUniquer.
+		EXPECT().
+		Unique(file.ImportGroups[0].Imports).
+		Return(expected)
+
+	actual := entityImportFetcher.Fetch(file, entity)
+
+	ctrl.AssertEqual(expected, actual)
+}
+
 func TestImportFetcher_Fetch_WithArraySpec(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
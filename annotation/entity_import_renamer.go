@@ -25,6 +25,8 @@ func (r *EntityImportRenamer) Rename(entity interface{}, oldAlias string, newAli
 	switch entity := entity.(type) {
 	case *SimpleSpec:
 		r.renameInSimpleSpec(entity, oldAlias, newAlias)
+	case *PointerSpec:
+		r.Rename(entity.Value, oldAlias, newAlias)
 	case *ArraySpec:
 		r.renameInArraySpec(entity, oldAlias, newAlias)
 	case *MapSpec:
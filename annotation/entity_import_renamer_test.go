@@ -59,6 +59,36 @@ func TestEntityImportRenamer_Rename_WithSimpleSpecAndNotRenamed(t *testing.T) {
 	ctrl.AssertEqual(expected, entity)
 }
 
+func TestEntityImportRenamer_Rename_WithPointerSpec(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	oldAlias := "oldPackageName"
+	newAlias := "newPackageName"
+
+	entity := &PointerSpec{
+		Value: &ArraySpec{
+			Value: &SimpleSpec{
+				PackageName: oldAlias,
+				TypeName:    "typeName",
+			},
+		},
+	}
+
+	expected := &PointerSpec{
+		Value: &ArraySpec{
+			Value: &SimpleSpec{
+				PackageName: newAlias,
+				TypeName:    "typeName",
+			},
+		},
+	}
+
+	(&EntityImportRenamer{}).Rename(entity, oldAlias, newAlias)
+
+	ctrl.AssertEqual(expected, entity)
+}
+
 func TestEntityImportRenamer_Rename_WithArraySpec(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,335 @@
+package annotation
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Limit of SimpleSpec chain, which is followed to find underlying spec of type.
+const maxUnderlyingSpecLevel = 1024
+
+// Candidate of selector: field or method, which could be selected from type at some embedding depth.
+type selectorCandidate struct {
+	name   string
+	depth  int
+	method *Method
+	field  *PromotedField
+}
+
+// Fetches method sets and promoted fields of types, declared in Storage.
+type EntityMethodSetFetcher struct {
+}
+
+// Creates new instance of EntityMethodSetFetcher.
+func NewEntityMethodSetFetcher() *EntityMethodSetFetcher {
+	return &EntityMethodSetFetcher{}
+}
+
+// Returns method set of referenced type (or pointer to it) sorted by name.
+// Method set contains methods, declared in any file of namespace, methods of interface and methods promoted
+// through embedded fields. Methods, which are shadowed or ambiguous at the same depth, are excluded.
+func (f *EntityMethodSetFetcher) Fetch(storage *Storage, reference *TypeReference, isPointer bool) []*Method {
+	if storage == nil {
+		panic(errors.New("Variable 'storage' must be not nil"))
+	}
+
+	if reference == nil {
+		panic(errors.New("Variable 'reference' must be not nil"))
+	}
+
+	result := []*Method{}
+
+	candidates := f.collect(storage, reference, isPointer, []*Field{}, map[*Type]bool{})
+
+	for _, candidate := range f.selectCandidates(candidates) {
+		if candidate.method != nil {
+			result = append(result, candidate.method)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// Returns fields of referenced type including fields promoted through embedded fields, ordered by depth of embedding
+// and then by declaration.
+// Fields, which are shadowed or ambiguous at the same depth, are excluded.
+func (f *EntityMethodSetFetcher) FetchFields(storage *Storage, reference *TypeReference) []*PromotedField {
+	if storage == nil {
+		panic(errors.New("Variable 'storage' must be not nil"))
+	}
+
+	if reference == nil {
+		panic(errors.New("Variable 'reference' must be not nil"))
+	}
+
+	result := []*PromotedField{}
+	candidates := f.collect(storage, reference, true, []*Field{}, map[*Type]bool{})
+
+	for _, candidate := range f.selectCandidates(candidates) {
+		if candidate.field != nil {
+			result = append(result, candidate.field)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return len(result[i].Path) < len(result[j].Path)
+	})
+
+	return result
+}
+
+// Keeps only candidates with minimal depth for each name, ambiguous names are removed. Order is not changed.
+func (f *EntityMethodSetFetcher) selectCandidates(candidates []*selectorCandidate) []*selectorCandidate {
+	selected := map[string]*selectorCandidate{}
+	ambiguous := map[string]bool{}
+
+	for _, candidate := range candidates {
+		current, ok := selected[candidate.name]
+
+		switch {
+		case !ok || candidate.depth < current.depth:
+			selected[candidate.name] = candidate
+			ambiguous[candidate.name] = false
+		case candidate.depth == current.depth:
+			ambiguous[candidate.name] = true
+		}
+	}
+
+	result := []*selectorCandidate{}
+
+	for _, candidate := range candidates {
+		if selected[candidate.name] == candidate && !ambiguous[candidate.name] {
+			result = append(result, candidate)
+		}
+	}
+
+	return result
+}
+
+func (f *EntityMethodSetFetcher) collect(
+	storage *Storage,
+	reference *TypeReference,
+	isPointer bool,
+	path []*Field,
+	visited map[*Type]bool,
+) []*selectorCandidate {
+	if reference.IsPredeclared {
+		return f.collectPredeclared(reference, path)
+	}
+
+	if visited[reference.Type] {
+		return []*selectorCandidate{}
+	}
+
+	visited[reference.Type] = true
+	defer delete(visited, reference.Type)
+
+	result := f.collectDeclared(reference, isPointer, path)
+	spec, context := f.underlying(storage, reference)
+
+	switch spec := spec.(type) {
+	case *InterfaceSpec:
+		// Pointer to interface has no methods, but methods of embedded interface are promoted to pointer of struct
+		if !isPointer || len(path) > 0 {
+			for _, method := range f.interfaceMethods(storage, context, spec, map[*InterfaceSpec]bool{}) {
+				method.Path = path
+				result = append(result, &selectorCandidate{name: method.Name, depth: len(path), method: method})
+			}
+		}
+	case *StructSpec:
+		result = append(result, f.collectStruct(storage, context, spec, isPointer, path, visited)...)
+	case nil:
+		if context != nil {
+			result = append(result, f.collectPredeclared(context, path)...)
+		}
+	}
+
+	return result
+}
+
+// Only predeclared error type has method.
+func (f *EntityMethodSetFetcher) collectPredeclared(reference *TypeReference, path []*Field) []*selectorCandidate {
+	if !reference.IsPredeclared || reference.Type.Name != "error" {
+		return []*selectorCandidate{}
+	}
+
+	return []*selectorCandidate{
+		{
+			name:  "Error",
+			depth: len(path),
+			method: &Method{
+				Name: "Error",
+				Spec: &FuncSpec{
+					Params:  []*Field{},
+					Results: []*Field{{Spec: &SimpleSpec{TypeName: "string"}}},
+				},
+				Path: path,
+			},
+		},
+	}
+}
+
+func (f *EntityMethodSetFetcher) collectDeclared(
+	reference *TypeReference,
+	isPointer bool,
+	path []*Field,
+) []*selectorCandidate {
+	result := []*selectorCandidate{}
+
+	if reference.Namespace == nil || reference.Type.Name == "" {
+		return result
+	}
+
+	for _, method := range reference.Namespace.FindMethodsByTypeName(reference.Type.Name) {
+		if method.IsPointerReceiver() && !isPointer {
+			continue
+		}
+
+		method.Path = path
+		result = append(result, &selectorCandidate{name: method.Name, depth: len(path), method: method})
+	}
+
+	return result
+}
+
+func (f *EntityMethodSetFetcher) collectStruct(
+	storage *Storage,
+	context *TypeReference,
+	spec *StructSpec,
+	isPointer bool,
+	path []*Field,
+	visited map[*Type]bool,
+) []*selectorCandidate {
+	result := []*selectorCandidate{}
+
+	for _, field := range spec.Fields {
+		name := field.Name
+		embeddedSpec, isEmbedded := field.Spec.(*SimpleSpec)
+		isEmbedded = isEmbedded && (field.IsEmbedded || name == "")
+
+		if isEmbedded {
+			name = embeddedSpec.TypeName
+		}
+
+		result = append(
+			result,
+			&selectorCandidate{
+				name:  name,
+				depth: len(path),
+				field: &PromotedField{Field: field, Namespace: context.Namespace, File: context.File, Path: path},
+			},
+		)
+
+		if !isEmbedded || context.File == nil {
+			continue
+		}
+
+		if embedded := storage.ResolveType(context.File, embeddedSpec); embedded != nil {
+			embeddedPath := append(append([]*Field{}, path...), field)
+
+			result = append(
+				result,
+				f.collect(storage, embedded, isPointer || embeddedSpec.IsPointer, embeddedPath, visited)...,
+			)
+		}
+	}
+
+	return result
+}
+
+// Returns methods of interface including embedded interfaces, duplicates are ignored.
+func (f *EntityMethodSetFetcher) interfaceMethods(
+	storage *Storage,
+	context *TypeReference,
+	spec *InterfaceSpec,
+	visited map[*InterfaceSpec]bool,
+) []*Method {
+	result := []*Method{}
+
+	if visited[spec] {
+		return result
+	}
+
+	visited[spec] = true
+
+	for _, field := range spec.Fields {
+		if funcSpec, ok := field.Spec.(*FuncSpec); ok {
+			result = append(
+				result,
+				&Method{Name: field.Name, Spec: funcSpec, Namespace: context.Namespace, File: context.File},
+			)
+
+			continue
+		}
+
+		embeddedSpec, ok := field.Spec.(*SimpleSpec)
+
+		if !ok || context.File == nil {
+			continue
+		}
+
+		embedded := storage.ResolveType(context.File, embeddedSpec)
+
+		if embedded != nil && !embedded.IsPredeclared {
+			var embeddedInterface interface{}
+
+			embeddedInterface, embedded = f.underlying(storage, embedded)
+
+			if embeddedInterface, ok := embeddedInterface.(*InterfaceSpec); ok {
+				result = append(result, f.interfaceMethods(storage, embedded, embeddedInterface, visited)...)
+
+				continue
+			}
+		}
+
+		if embedded != nil {
+			for _, candidate := range f.collectPredeclared(embedded, []*Field{}) {
+				result = append(result, candidate.method)
+			}
+		}
+	}
+
+	unique := []*Method{}
+	names := map[string]bool{}
+
+	for _, method := range result {
+		if !names[method.Name] {
+			names[method.Name] = true
+			unique = append(unique, method)
+		}
+	}
+
+	return unique
+}
+
+// Follows chain of named types and returns not *SimpleSpec spec with reference to its declaration.
+// If chain ends with predeclared or unknown type, spec is nil.
+func (f *EntityMethodSetFetcher) underlying(storage *Storage, reference *TypeReference) (interface{}, *TypeReference) {
+	for i := 0; i < maxUnderlyingSpecLevel; i++ {
+		spec, ok := reference.Type.Spec.(*SimpleSpec)
+
+		if !ok {
+			return reference.Type.Spec, reference
+		}
+
+		// Pointer type has no methods and fields
+		if spec.IsPointer || reference.File == nil {
+			return nil, nil
+		}
+
+		next := storage.ResolveType(reference.File, spec)
+
+		if next == nil || next.IsPredeclared {
+			return nil, next
+		}
+
+		reference = next
+	}
+
+	panic(errors.Errorf("Type '%s' has too deep chain of underlying types", reference.Type.Name))
+}
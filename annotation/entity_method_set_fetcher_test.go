@@ -0,0 +1,231 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestNewEntityMethodSetFetcher(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	actual := NewEntityMethodSetFetcher()
+
+	ctrl.AssertNotNil(actual)
+}
+
+func TestEntityMethodSetFetcher_Fetch(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := `package model
+
+type Base struct{}
+
+func (b Base) Name() string {
+	return ""
+}
+
+func (b *Base) SetName(name string) {}
+
+func (b Base) ID() int {
+	return 0
+}
+
+type Other struct{}
+
+func (o Other) ID() int {
+	return 0
+}
+
+type Model struct {
+	Base
+	*Other
+	Name string
+}
+
+func (m Model) Validate() error {
+	return nil
+}
+
+type Failer interface {
+	error
+	Fail()
+}
+
+type Wrapper struct {
+	Failer
+}
+
+type Alias Base
+`
+	file := NewGoSourceParser(NewJSONAnnotationParser()).Parse("model.go", content)
+	namespace := &Namespace{Name: "example.com/model", Path: "/model", Files: []*File{file}}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+
+	fetch := func(name string, isPointer bool) []string {
+		_, element := namespace.FindTypeByName(name)
+		result := []string{}
+
+		for _, method := range NewEntityMethodSetFetcher().Fetch(
+			storage,
+			&TypeReference{Namespace: namespace, File: file, Type: element},
+			isPointer,
+		) {
+			result = append(result, method.Name)
+		}
+
+		return result
+	}
+
+	ctrl.AssertEqual([]string{"ID", "Name"}, fetch("Base", false))
+	ctrl.AssertEqual([]string{"ID", "Name", "SetName"}, fetch("Base", true))
+	ctrl.AssertEqual([]string{"Validate"}, fetch("Model", false))
+	ctrl.AssertEqual([]string{"SetName", "Validate"}, fetch("Model", true))
+	ctrl.AssertEqual([]string{"Error", "Fail"}, fetch("Failer", false))
+	ctrl.AssertEqual([]string{}, fetch("Failer", true))
+	ctrl.AssertEqual([]string{"Error", "Fail"}, fetch("Wrapper", false))
+	ctrl.AssertEqual([]string{"Error", "Fail"}, fetch("Wrapper", true))
+	ctrl.AssertEqual([]string{}, fetch("Alias", true))
+
+	_, baseType := namespace.FindTypeByName("Base")
+	methods := NewEntityMethodSetFetcher().Fetch(
+		storage,
+		&TypeReference{Namespace: namespace, File: file, Type: baseType},
+		true,
+	)
+
+	ctrl.AssertSame(file.Funcs[1], methods[2].Func)
+	ctrl.AssertSame(file.Funcs[1].Spec, methods[2].Spec)
+	ctrl.AssertSame(namespace, methods[2].Namespace)
+	ctrl.AssertSame(file, methods[2].File)
+}
+
+func TestEntityMethodSetFetcher_Fetch_WithEmbeddedPointer(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := `package model
+
+type Base struct{}
+
+func (b *Base) SetName(name string) {}
+
+type Model struct {
+	*Base
+}
+`
+	file := NewGoSourceParser(NewJSONAnnotationParser()).Parse("model.go", content)
+	namespace := &Namespace{Name: "example.com/model", Path: "/model", Files: []*File{file}}
+	_, element := namespace.FindTypeByName("Model")
+
+	actual := NewEntityMethodSetFetcher().Fetch(
+		&Storage{Namespaces: []*Namespace{namespace}},
+		&TypeReference{Namespace: namespace, File: file, Type: element},
+		false,
+	)
+
+	ctrl.AssertSame(1, len(actual))
+	ctrl.AssertSame("SetName", actual[0].Name)
+	ctrl.AssertEqual([]*Field{element.Spec.(*StructSpec).Fields[0]}, actual[0].Path)
+	ctrl.AssertTrue(actual[0].IsPointerReceiver())
+}
+
+func TestEntityMethodSetFetcher_Fetch_WithNilStorage(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityMethodSetFetcher().Fetch, nil, &TypeReference{}, false).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'storage' must be not nil"))
+}
+
+func TestEntityMethodSetFetcher_Fetch_WithNilReference(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityMethodSetFetcher().Fetch, &Storage{}, nil, false).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'reference' must be not nil"))
+}
+
+func TestEntityMethodSetFetcher_FetchFields(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	sourceParser := NewGoSourceParser(NewJSONAnnotationParser())
+	baseFile := sourceParser.Parse("base.go", `package base
+
+type Base struct {
+	ID   int
+	Name string
+}
+`)
+	modelFile := sourceParser.Parse("model.go", `package model
+
+import "example.com/base"
+
+type Other struct {
+	ID int
+}
+
+type Model struct {
+	*base.Base
+	Other
+	Name string
+}
+`)
+	baseNamespace := &Namespace{Name: "example.com/base", Path: "/base", Files: []*File{baseFile}}
+	modelNamespace := &Namespace{Name: "example.com/model", Path: "/model", Files: []*File{modelFile}}
+	_, modelType := modelNamespace.FindTypeByName("Model")
+	baseField := modelType.Spec.(*StructSpec).Fields[0]
+	otherField := modelType.Spec.(*StructSpec).Fields[1]
+
+	expected := []*PromotedField{
+		{Field: baseField, Namespace: modelNamespace, File: modelFile, Path: []*Field{}},
+		{Field: otherField, Namespace: modelNamespace, File: modelFile, Path: []*Field{}},
+		{Field: modelType.Spec.(*StructSpec).Fields[2], Namespace: modelNamespace, File: modelFile, Path: []*Field{}},
+	}
+
+	actual := NewEntityMethodSetFetcher().FetchFields(
+		&Storage{Namespaces: []*Namespace{baseNamespace, modelNamespace}},
+		&TypeReference{Namespace: modelNamespace, File: modelFile, Type: modelType},
+	)
+
+	ctrl.AssertEqual(expected, actual)
+
+	_, otherType := modelNamespace.FindTypeByName("Other")
+	otherType.Spec.(*StructSpec).Fields[0].Name = "Code"
+
+	actual = NewEntityMethodSetFetcher().FetchFields(
+		&Storage{Namespaces: []*Namespace{baseNamespace, modelNamespace}},
+		&TypeReference{Namespace: modelNamespace, File: modelFile, Type: modelType},
+	)
+
+	ctrl.AssertSame(5, len(actual))
+	ctrl.AssertSame(baseFile.TypeGroups[0].Types[0].Spec.(*StructSpec).Fields[0], actual[3].Field)
+	ctrl.AssertSame(baseNamespace, actual[3].Namespace)
+	ctrl.AssertSame(baseFile, actual[3].File)
+	ctrl.AssertEqual([]*Field{baseField}, actual[3].Path)
+	ctrl.AssertSame(otherType.Spec.(*StructSpec).Fields[0], actual[4].Field)
+	ctrl.AssertEqual([]*Field{otherField}, actual[4].Path)
+}
+
+func TestEntityMethodSetFetcher_FetchFields_WithNilStorage(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityMethodSetFetcher().FetchFields, nil, &TypeReference{}).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'storage' must be not nil"))
+}
+
+func TestEntityMethodSetFetcher_FetchFields_WithNilReference(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityMethodSetFetcher().FetchFields, &Storage{}, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'reference' must be not nil"))
+}
@@ -19,6 +19,8 @@ func (r *EntityRenderer) Render(entity interface{}) string {
 	switch entity := entity.(type) {
 	case *SimpleSpec:
 		return r.renderSimpleSpec(entity)
+	case *PointerSpec:
+		return r.renderPointerSpec(entity)
 	case *ArraySpec:
 		return r.renderArraySpec(entity)
 	case *MapSpec:
@@ -68,6 +70,14 @@ func (r *EntityRenderer) renderSimpleSpec(entity *SimpleSpec) string {
 	return result + entity.TypeName
 }
 
+func (r *EntityRenderer) renderPointerSpec(entity *PointerSpec) string {
+	if _, ok := entity.Value.(*FuncSpec); ok {
+		return "*func " + r.Render(entity.Value)
+	}
+
+	return "*" + r.Render(entity.Value)
+}
+
 func (r *EntityRenderer) renderArraySpec(entity *ArraySpec) string {
 	result := ""
 
@@ -400,6 +410,10 @@ func (r *EntityRenderer) renderFile(entity *File) string {
 		return entity.Content
 	}
 
+	if entity.Layout != nil {
+		return r.renderLayout(entity)
+	}
+
 	result := r.renderComment(entity.Comment)
 
 	result += "package " + entity.PackageName + "\n\n"
@@ -433,6 +447,102 @@ func (r *EntityRenderer) renderFile(entity *File) string {
 	return string(formattedResult)
 }
 
+// Renders file by original layout: unchanged declarations are rendered as original source with their leading
+// comments and blank lines, changed ones are rendered again with original source of their unchanged specs and fields,
+// line comments and blank lines between them. Declarations are matched by names, removed ones are skipped, new ones
+// are added after the last original declaration of the same or previous kind.
+func (r *EntityRenderer) renderLayout(entity *File) string {
+	layout := entity.Layout
+	declarations := fileDeclarations(entity)
+	originalDeclarations := layoutDeclarations(layout)
+	result := layout.Header
+
+	if entity.PackageName != layout.PackageName || entity.Comment != layout.Comment {
+		result = r.renderComment(entity.Comment) + "package " + entity.PackageName
+	}
+
+	// Indexes of current declarations by kind and index of original declaration
+	currentIndexes := map[string]map[int]int{}
+	// Position of original declaration, after which new declarations of kind are added, -1 is the package clause
+	positions := map[string]int{}
+
+	for i, kind := range declarationKinds {
+		currentIndexes[kind] = map[int]int{}
+
+		for j, k := range pairDeclarations(kind, originalDeclarations[kind], declarations[kind]) {
+			currentIndexes[kind][k] = j
+		}
+
+		positions[kind] = -1
+
+		for j, element := range layout.Declarations {
+			for _, previousKind := range declarationKinds[:i+1] {
+				if element.Kind == previousKind {
+					positions[kind] = j
+				}
+			}
+		}
+	}
+
+	result += r.renderNewDeclarations(declarations, currentIndexes, positions, -1)
+
+	for i, element := range layout.Declarations {
+		if j, ok := currentIndexes[element.Kind][element.Index]; ok {
+			current := declarations[element.Kind][j]
+
+			if r.Render(current) == r.Render(element.Original) {
+				result += element.Leading + element.Source
+			} else {
+				result += element.Leading + keepDeclarationLayout(element.Source, r.renderDeclaration(current))
+			}
+		}
+
+		result += r.renderNewDeclarations(declarations, currentIndexes, positions, i)
+	}
+
+	return result + layout.Trailer
+}
+
+// Renders declarations, which are absent in layout and are added after original declaration with position.
+func (r *EntityRenderer) renderNewDeclarations(
+	declarations map[string][]interface{},
+	currentIndexes map[string]map[int]int,
+	positions map[string]int,
+	position int,
+) string {
+	result := ""
+
+	for _, kind := range declarationKinds {
+		if positions[kind] != position {
+			continue
+		}
+
+		isPaired := map[int]bool{}
+
+		for _, j := range currentIndexes[kind] {
+			isPaired[j] = true
+		}
+
+		for i, element := range declarations[kind] {
+			if !isPaired[i] {
+				result += "\n\n" + r.renderDeclaration(element)
+			}
+		}
+	}
+
+	return result
+}
+
+func (r *EntityRenderer) renderDeclaration(entity interface{}) string {
+	formattedResult, err := format.Source([]byte(r.Render(entity)))
+
+	if err != nil {
+		panic(err)
+	}
+
+	return strings.TrimRight(string(formattedResult), "\n")
+}
+
 func (r *EntityRenderer) renderComment(comment string) string {
 	if comment == "" {
 		return ""
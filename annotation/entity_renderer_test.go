@@ -82,6 +82,40 @@ func TestEntityRenderer_Render_WithSimpleSpecAndTypeName(t *testing.T) {
 	ctrl.AssertSame(expected, actual)
 }
 
+func TestEntityRenderer_Render_WithPointerSpec(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	expected := "*[]valueTypeName"
+
+	entity := &PointerSpec{
+		Value: &ArraySpec{
+			Value: &SimpleSpec{
+				TypeName: "valueTypeName",
+			},
+		},
+	}
+
+	actual := (&EntityRenderer{}).Render(entity)
+
+	ctrl.AssertSame(expected, actual)
+}
+
+func TestEntityRenderer_Render_WithPointerSpecAndFuncSpecValue(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	expected := "*func ()"
+
+	entity := &PointerSpec{
+		Value: &FuncSpec{},
+	}
+
+	actual := (&EntityRenderer{}).Render(entity)
+
+	ctrl.AssertSame(expected, actual)
+}
+
 func TestEntityRenderer_Render_WithArraySpec(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
@@ -2213,6 +2247,201 @@ func func1Name() {
 	ctrl.AssertSame(expected, actual)
 }
 
+func TestEntityRenderer_Render_WithFileLayout(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	expected := "// +build tag\n\n" +
+		"// Package comment\n" +
+		"package filePackage\n\n" +
+		"import \"fmt\"\n\n" +
+		"import (\n" +
+		"\t\"strings\"\n\n" +
+		"\t\"github.com/pkg/errors\"\n" +
+		")\n\n" +
+		"// free-floating comment\n\n" +
+		"const Single = 1 // line comment\n\n\n" +
+		"var (\n" +
+		"\tfirst  = 1\n\n" +
+		"\t// second comment\n" +
+		"\tsecond = 2\n" +
+		")\n\n" +
+		"// Model comment\n" +
+		"type Model struct {\n" +
+		"\tName string // name\n\n" +
+		"\tAge int\n" +
+		"}\n\n" +
+		"func Print() { fmt.Print(strings.TrimSpace(errors.New(\"\").Error())) }\n\n" +
+		"// trailing comment\n"
+
+	parser := NewGoSourceParser(NewJSONAnnotationParser())
+	parser.SetLayoutKept(true)
+
+	entity := parser.Parse("fileName.go", expected)
+	entity.Content = ""
+
+	actual := (&EntityRenderer{}).Render(entity)
+
+	ctrl.AssertSame(expected, actual)
+}
+
+func TestEntityRenderer_Render_WithFileLayoutAndChangedDeclarations(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := "package filePackage\n\n" +
+		"// free-floating comment\n\n" +
+		"const Single = 1 // line comment\n\n" +
+		"// Model comment\n" +
+		"type Model struct{}\n\n" +
+		"func Remove() {}\n\n" +
+		"// trailing comment\n"
+	expected := "package filePackage\n\n" +
+		"// free-floating comment\n\n" +
+		"const Single = 1 // line comment\n\n" +
+		"var Added = 2\n\n" +
+		"// Model comment\n" +
+		"type Model struct {\n" +
+		"\tName string\n" +
+		"}\n\n" +
+		"// trailing comment\n"
+
+	parser := NewGoSourceParser(NewJSONAnnotationParser())
+	parser.SetLayoutKept(true)
+
+	entity := parser.Parse("fileName.go", content)
+	entity.Content = ""
+	entity.VarGroups = []*VarGroup{{Vars: []*Var{{Name: "Added", Value: "2"}}}}
+	entity.TypeGroups[0].Types[0].Spec = &StructSpec{
+		Fields: []*Field{{Name: "Name", Spec: &SimpleSpec{TypeName: "string"}}},
+	}
+	entity.Funcs = []*Func{}
+
+	actual := (&EntityRenderer{}).Render(entity)
+
+	ctrl.AssertSame(expected, actual)
+}
+
+func TestEntityRenderer_Render_WithFileLayoutAndChangedFields(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := "package filePackage\n\n" +
+		"// Model comment\n" +
+		"type Model struct {\n" +
+		"\t// Name comment\n" +
+		"\tName string // Name line comment\n" +
+		"\n" +
+		"\t// free-floating comment\n" +
+		"\n" +
+		"\tAge  int    // Age line comment\n" +
+		"\tCity string // City line comment\n" +
+		"}\n"
+	expected := "package filePackage\n\n" +
+		"// Model comment\n" +
+		"type Model struct {\n" +
+		"\t// Name comment\n" +
+		"\tName string // Name line comment\n" +
+		"\n" +
+		"\t// free-floating comment\n" +
+		"\n" +
+		"\tAge  uint8  // Age line comment\n" +
+		"\tCity string // City line comment\n" +
+		"\tZip  string\n" +
+		"}\n"
+
+	parser := NewGoSourceParser(NewJSONAnnotationParser())
+	parser.SetLayoutKept(true)
+
+	entity := parser.Parse("fileName.go", content)
+	entity.Content = ""
+	spec := entity.TypeGroups[0].Types[0].Spec.(*StructSpec)
+	spec.Fields[1].Spec = &SimpleSpec{TypeName: "uint8"}
+	spec.Fields = append(spec.Fields, &Field{Name: "Zip", Spec: &SimpleSpec{TypeName: "string"}})
+
+	actual := (&EntityRenderer{}).Render(entity)
+
+	ctrl.AssertSame(expected, actual)
+}
+
+func TestEntityRenderer_Render_WithFileLayoutAndRemovedDeclaration(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := "package filePackage\n\n" +
+		"type A struct{}\n\n" +
+		"// z\n" +
+		"type B struct{}\n"
+	expected := "package filePackage\n\n" +
+		"// z\n" +
+		"type B struct{}\n"
+
+	parser := NewGoSourceParser(NewJSONAnnotationParser())
+	parser.SetLayoutKept(true)
+
+	entity := parser.Parse("fileName.go", content)
+	entity.Content = ""
+	entity.TypeGroups = entity.TypeGroups[1:]
+
+	actual := (&EntityRenderer{}).Render(entity)
+
+	ctrl.AssertSame(expected, actual)
+}
+
+func TestEntityRenderer_Render_WithFileLayoutAndInsertedDeclaration(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := "package filePackage\n\n" +
+		"type A struct{}\n\n" +
+		"// z\n" +
+		"type B struct{}\n"
+	expected := "package filePackage\n\n" +
+		"type A struct{}\n\n" +
+		"// z\n" +
+		"type B struct{}\n\n" +
+		"type C struct{}\n"
+
+	parser := NewGoSourceParser(NewJSONAnnotationParser())
+	parser.SetLayoutKept(true)
+
+	entity := parser.Parse("fileName.go", content)
+	entity.Content = ""
+	entity.TypeGroups = append(
+		[]*TypeGroup{{Types: []*Type{{Name: "C", Spec: &StructSpec{}}}}},
+		entity.TypeGroups...,
+	)
+
+	actual := (&EntityRenderer{}).Render(entity)
+
+	ctrl.AssertSame(expected, actual)
+}
+
+func TestEntityRenderer_Render_WithFileLayoutAndChangedPackageName(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := "// old comment\n" +
+		"package filePackage\n\n" +
+		"type Model struct{}\n"
+	expected := "// new\n" +
+		"// comment\n" +
+		"package newPackage\n\n" +
+		"type Model struct{}\n"
+
+	parser := NewGoSourceParser(NewJSONAnnotationParser())
+	parser.SetLayoutKept(true)
+
+	entity := parser.Parse("fileName.go", content)
+	entity.Content = ""
+	entity.PackageName = "newPackage"
+	entity.Comment = "new\ncomment"
+
+	actual := (&EntityRenderer{}).Render(entity)
+
+	ctrl.AssertSame(expected, actual)
+}
+
 func TestEntityRenderer_Render_WithUnexpectedEntity(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
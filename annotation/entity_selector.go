@@ -0,0 +1,458 @@
+package annotation
+
+import (
+	"encoding/json"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Kinds of selector steps by kind of previous step, empty kind is namespace.
+var selectorKinds = map[string][]string{
+	"":       {"File", "Import", "Const", "Var", "Type", "Func", "Method"},
+	"File":   {"Import", "Const", "Var", "Type", "Func", "Method"},
+	"Type":   {"Field", "Method"},
+	"Field":  {"Field"},
+	"Func":   {"Param", "Result"},
+	"Method": {"Param", "Result"},
+}
+
+var selectorPredicateRegexp = regexp.MustCompile(`^(!?)(@?)([\p{L}_][\p{L}\d_]*)(?:(\.[\p{L}_][\p{L}\d_]*)?(!?=)(.*))?$`)
+
+type selectorQuery struct {
+	namespace *regexp.Regexp
+	steps     []*selectorStep
+}
+
+type selectorStep struct {
+	kind       string
+	predicates []*selectorPredicate
+}
+
+// Predicate checks name of entity, presence of annotation or field of annotation.
+type selectorPredicate struct {
+	isNegative   bool
+	isAnnotation bool
+	name         string
+	key          string
+	isNotEqual   bool
+	pattern      string
+}
+
+// Candidate of selection with comments, which are used to find annotations.
+type selectorMatch struct {
+	entity   *SelectedEntity
+	name     string
+	comments []string
+}
+
+// Selects entities from Storage by selector, e.g. "my/pkg/...:Type[@Entity].Field[Name=ID]".
+//
+// Selector consists of optional namespace pattern with ":" suffix and list of steps separated by ".".
+// Namespace pattern supports "*" for any part of path and "..." for any path, "my/pkg/..." matches "my/pkg" too.
+// Step consists of kind (File, Import, Const, Var, Type, Func, Method, Field, Param, Result) and predicates in square
+// brackets: [Name=pattern], [Name!=pattern], [@Annotation], [@Annotation.key=pattern], [@Annotation.key!=pattern].
+// Predicate with "!" prefix is negated. Patterns have path.Match syntax, annotations are found in comments.
+type EntitySelector struct {
+}
+
+// Creates new instance of EntitySelector.
+func NewEntitySelector() *EntitySelector {
+	return &EntitySelector{}
+}
+
+// Returns entities matched by selector, or error if selector is invalid.
+func (s *EntitySelector) Select(storage *Storage, selector string) ([]*SelectedEntity, error) {
+	if storage == nil {
+		panic(errors.New("Variable 'storage' must be not nil"))
+	}
+
+	query, err := s.parse(selector)
+
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []*selectorMatch{}
+
+	for _, namespace := range storage.Namespaces {
+		if query.namespace == nil || query.namespace.MatchString(namespace.Name) {
+			matches = append(matches, &selectorMatch{entity: &SelectedEntity{Namespace: namespace, Parents: []interface{}{}}})
+		}
+	}
+
+	previousKind := ""
+
+	for _, step := range query.steps {
+		next := []*selectorMatch{}
+
+		for _, match := range matches {
+			for _, child := range s.children(match, previousKind, step.kind) {
+				if s.matchStep(step, child) {
+					next = append(next, child)
+				}
+			}
+		}
+
+		matches = next
+		previousKind = step.kind
+	}
+
+	result := make([]*SelectedEntity, len(matches))
+
+	for i, match := range matches {
+		result[i] = match.entity
+	}
+
+	return result, nil
+}
+
+func (s *EntitySelector) parse(selector string) (*selectorQuery, error) {
+	result := &selectorQuery{steps: []*selectorStep{}}
+	steps := selector
+
+	if index := s.indexOutsideBrackets(selector, ':'); index >= 0 {
+		if selector[:index] == "" {
+			return nil, errors.Errorf("Selector '%s' has empty namespace pattern", selector)
+		}
+
+		result.namespace = s.compileNamespace(selector[:index])
+		steps = selector[index+1:]
+	}
+
+	previousKind := ""
+
+	for steps != "" {
+		index := s.indexOutsideBrackets(steps, '.')
+		part := steps
+
+		if index >= 0 {
+			part, steps = steps[:index], steps[index+1:]
+
+			if steps == "" {
+				return nil, errors.Errorf("Selector '%s' has empty step", selector)
+			}
+		} else {
+			steps = ""
+		}
+
+		step, err := s.parseStep(part)
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "Selector '%s' is invalid", selector)
+		}
+
+		if !s.isKindAllowed(previousKind, step.kind) {
+			parentKind := previousKind
+
+			if parentKind == "" {
+				parentKind = "Namespace"
+			}
+
+			return nil, errors.Errorf("Selector '%s' has step '%s' not allowed in '%s'", selector, step.kind, parentKind)
+		}
+
+		result.steps = append(result.steps, step)
+		previousKind = step.kind
+	}
+
+	if len(result.steps) == 0 {
+		return nil, errors.Errorf("Selector '%s' must have at least one step", selector)
+	}
+
+	return result, nil
+}
+
+func (s *EntitySelector) parseStep(part string) (*selectorStep, error) {
+	index := strings.IndexByte(part, '[')
+
+	if index < 0 {
+		index = len(part)
+	}
+
+	result := &selectorStep{kind: part[:index], predicates: []*selectorPredicate{}}
+	part = part[index:]
+
+	if !s.isKindKnown(result.kind) {
+		return nil, errors.Errorf("Step '%s' has unknown kind", result.kind)
+	}
+
+	for part != "" {
+		end := strings.IndexByte(part, ']')
+
+		if part[0] != '[' || end < 0 {
+			return nil, errors.Errorf("Step '%s' has invalid predicate '%s'", result.kind, part)
+		}
+
+		predicate, err := s.parsePredicate(strings.TrimSpace(part[1:end]))
+
+		if err != nil {
+			return nil, err
+		}
+
+		result.predicates = append(result.predicates, predicate)
+		part = part[end+1:]
+	}
+
+	return result, nil
+}
+
+func (s *EntitySelector) parsePredicate(content string) (*selectorPredicate, error) {
+	parts := selectorPredicateRegexp.FindStringSubmatch(content)
+
+	if parts == nil {
+		return nil, errors.Errorf("Predicate '%s' is invalid", content)
+	}
+
+	result := &selectorPredicate{
+		isNegative:   parts[1] == "!",
+		isAnnotation: parts[2] == "@",
+		name:         parts[3],
+		key:          strings.TrimPrefix(parts[4], "."),
+		isNotEqual:   parts[5] == "!=",
+		pattern:      parts[6],
+	}
+
+	if !result.isAnnotation && (result.name != "Name" || parts[5] == "") {
+		return nil, errors.Errorf("Predicate '%s' must check Name or annotation", content)
+	}
+
+	if result.isAnnotation && parts[5] != "" && result.key == "" {
+		return nil, errors.Errorf("Predicate '%s' must have key of annotation", content)
+	}
+
+	if _, err := path.Match(result.pattern, ""); err != nil {
+		return nil, errors.Wrapf(err, "Predicate '%s' has invalid pattern", content)
+	}
+
+	return result, nil
+}
+
+func (s *EntitySelector) compileNamespace(pattern string) *regexp.Regexp {
+	result := regexp.QuoteMeta(pattern)
+	result = strings.Replace(result, `/\.\.\.`, `(/.*)?`, -1)
+	result = strings.Replace(result, `\.\.\.`, `.*`, -1)
+	result = strings.Replace(result, `\*`, `[^/]*`, -1)
+
+	return regexp.MustCompile("^" + result + "$")
+}
+
+func (s *EntitySelector) indexOutsideBrackets(value string, char byte) int {
+	isInBrackets := false
+
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '[':
+			isInBrackets = true
+		case value[i] == ']':
+			isInBrackets = false
+		case value[i] == char && !isInBrackets:
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (s *EntitySelector) isKindKnown(kind string) bool {
+	for previousKind := range selectorKinds {
+		if s.isKindAllowed(previousKind, kind) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *EntitySelector) isKindAllowed(previousKind string, kind string) bool {
+	for _, allowedKind := range selectorKinds[previousKind] {
+		if allowedKind == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *EntitySelector) matchStep(step *selectorStep, match *selectorMatch) bool {
+	for _, predicate := range step.predicates {
+		if s.matchPredicate(predicate, match) == predicate.isNegative {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *EntitySelector) matchPredicate(predicate *selectorPredicate, match *selectorMatch) bool {
+	if !predicate.isAnnotation {
+		ok, _ := path.Match(predicate.pattern, match.name)
+
+		return ok != predicate.isNotEqual
+	}
+
+	for _, comment := range match.comments {
+		for _, part := range jsonAnnotationRegexp.FindAllStringSubmatch(comment, -1) {
+			if part[1] != predicate.name {
+				continue
+			}
+
+			if predicate.key == "" {
+				return true
+			}
+
+			if value, ok := s.annotationValue(strings.TrimSpace(part[2]), predicate.key); ok {
+				if isMatched, _ := path.Match(predicate.pattern, value); isMatched != predicate.isNotEqual {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// Returns value of annotation field in JSON data, not string values are encoded to JSON.
+func (s *EntitySelector) annotationValue(data string, key string) (string, bool) {
+	fields := map[string]interface{}{}
+
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return "", false
+	}
+
+	value, ok := fields[key]
+
+	if !ok {
+		return "", false
+	}
+
+	if value, ok := value.(string); ok {
+		return value, true
+	}
+
+	encoded, _ := json.Marshal(value)
+
+	return string(encoded), true
+}
+
+func (s *EntitySelector) children(match *selectorMatch, previousKind string, kind string) []*selectorMatch {
+	result := []*selectorMatch{}
+	parents := match.entity.Parents
+
+	if previousKind != "" {
+		parents = append(parents[:len(parents):len(parents)], match.entity.Entity)
+	}
+
+	add := func(file *File, entity interface{}, name string, comments ...string) {
+		result = append(
+			result,
+			&selectorMatch{
+				entity:   &SelectedEntity{Namespace: match.entity.Namespace, File: file, Entity: entity, Parents: parents},
+				name:     name,
+				comments: comments,
+			},
+		)
+	}
+
+	switch entity := match.entity.Entity.(type) {
+	case nil:
+		for _, file := range match.entity.Namespace.Files {
+			s.fileChildren(file, kind, add)
+		}
+	case *File:
+		s.fileChildren(entity, kind, add)
+	case *Type:
+		switch kind {
+		case "Method":
+			for _, method := range match.entity.Namespace.FindMethodsByTypeName(entity.Name) {
+				add(method.File, method.Func, method.Name, method.Func.Comment)
+			}
+		case "Field":
+			s.fieldChildren(match.entity.File, entity.Spec, add)
+		}
+	case *Field:
+		s.fieldChildren(match.entity.File, entity.Spec, add)
+	case *Func:
+		fields := []*Field{}
+
+		if entity.Spec != nil && kind == "Param" {
+			fields = entity.Spec.Params
+		} else if entity.Spec != nil {
+			fields = entity.Spec.Results
+		}
+
+		for _, field := range fields {
+			add(match.entity.File, field, field.Name, field.Comment)
+		}
+	}
+
+	return result
+}
+
+func (s *EntitySelector) fileChildren(
+	file *File,
+	kind string,
+	add func(file *File, entity interface{}, name string, comments ...string),
+) {
+	switch kind {
+	case "File":
+		add(file, file, file.Name, file.Comment)
+	case "Import":
+		for _, importGroup := range file.ImportGroups {
+			for _, element := range importGroup.Imports {
+				add(file, element, element.Namespace, importGroup.Comment, element.Comment)
+			}
+		}
+	case "Const":
+		for _, constGroup := range file.ConstGroups {
+			for _, element := range constGroup.Consts {
+				add(file, element, element.Name, constGroup.Comment, element.Comment)
+			}
+		}
+	case "Var":
+		for _, varGroup := range file.VarGroups {
+			for _, element := range varGroup.Vars {
+				add(file, element, element.Name, varGroup.Comment, element.Comment)
+			}
+		}
+	case "Type":
+		for _, typeGroup := range file.TypeGroups {
+			for _, element := range typeGroup.Types {
+				add(file, element, element.Name, typeGroup.Comment, element.Comment)
+			}
+		}
+	case "Func", "Method":
+		for _, element := range file.Funcs {
+			if (element.Related != nil) == (kind == "Method") {
+				add(file, element, element.Name, element.Comment)
+			}
+		}
+	}
+}
+
+func (s *EntitySelector) fieldChildren(
+	file *File,
+	spec interface{},
+	add func(file *File, entity interface{}, name string, comments ...string),
+) {
+	var fields []*Field
+
+	switch spec := spec.(type) {
+	case *StructSpec:
+		fields = spec.Fields
+	case *InterfaceSpec:
+		fields = spec.Fields
+	}
+
+	for _, field := range fields {
+		name := field.Name
+
+		if embeddedSpec, ok := field.Spec.(*SimpleSpec); ok && name == "" {
+			name = embeddedSpec.TypeName
+		}
+
+		add(file, field, name, field.Comment)
+	}
+}
@@ -0,0 +1,168 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestNewEntitySelector(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	actual := NewEntitySelector()
+
+	ctrl.AssertNotNil(actual)
+}
+
+func TestEntitySelector_Select(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	sourceParser := NewGoSourceParser(NewJSONAnnotationParser())
+	modelFile := sourceParser.Parse("model.go", `package model
+
+import "time"
+
+const Version = 1
+
+// @Entity({"table": "users", "version": 2})
+type User struct {
+	ID      int
+	Profile struct {
+		Name string
+	}
+	// @Column({"name": "created_at"})
+	Created time.Time
+}
+
+// @Entity({"table": "orders"})
+type Order struct {
+	ID int
+}
+
+type Other struct {
+	ID int
+}
+
+func (u *User) Validate(strict bool) error {
+	return nil
+}
+
+func New() *User {
+	return &User{}
+}
+`)
+	internalFile := sourceParser.Parse("internal.go", `package internal
+
+// @Entity({"table": "logs"})
+type Log struct {
+	ID int
+}
+`)
+	modelNamespace := &Namespace{Name: "example.com/model", Path: "/model", Files: []*File{modelFile}}
+	internalNamespace := &Namespace{
+		Name:  "example.com/model/internal",
+		Path:  "/model/internal",
+		Files: []*File{internalFile},
+	}
+	storage := &Storage{
+		Namespaces: []*Namespace{modelNamespace, internalNamespace, {Name: "example.com/other", Path: "/other"}},
+	}
+	userType := modelFile.TypeGroups[0].Types[0]
+	orderType := modelFile.TypeGroups[1].Types[0]
+	otherType := modelFile.TypeGroups[2].Types[0]
+	logType := internalFile.TypeGroups[0].Types[0]
+	userFields := userType.Spec.(*StructSpec).Fields
+
+	select_ := func(selector string) []interface{} {
+		result, err := NewEntitySelector().Select(storage, selector)
+
+		ctrl.AssertNil(err)
+
+		entities := []interface{}{}
+
+		for _, element := range result {
+			entities = append(entities, element.Entity)
+		}
+
+		return entities
+	}
+
+	ctrl.AssertEqual([]interface{}{userType, orderType, logType}, select_("Type[@Entity]"))
+	ctrl.AssertEqual([]interface{}{userType, orderType, logType}, select_("example.com/model/...:Type[@Entity]"))
+	ctrl.AssertEqual([]interface{}{logType}, select_("example.com/*/internal:Type[@Entity]"))
+	ctrl.AssertEqual([]interface{}{userType, orderType}, select_("example.com/model:Type[@Entity]"))
+	ctrl.AssertEqual([]interface{}{otherType}, select_("example.com/model:Type[!@Entity]"))
+	ctrl.AssertEqual([]interface{}{userType}, select_("Type[@Entity.table=us*]"))
+	ctrl.AssertEqual([]interface{}{userType}, select_("Type[@Entity.version=2]"))
+	ctrl.AssertEqual([]interface{}{orderType, logType}, select_("Type[@Entity.table!=users]"))
+	ctrl.AssertEqual([]interface{}{userType, otherType}, select_("example.com/model:Type[Name!=Order]"))
+	ctrl.AssertEqual(
+		[]interface{}{userFields[0], orderType.Spec.(*StructSpec).Fields[0], logType.Spec.(*StructSpec).Fields[0]},
+		select_("Type[@Entity].Field[Name=ID]"),
+	)
+	ctrl.AssertEqual([]interface{}{userFields[2]}, select_("Type.Field[@Column.name=created_at]"))
+	ctrl.AssertEqual(
+		[]interface{}{userFields[1].Spec.(*StructSpec).Fields[0]},
+		select_("Type[Name=User].Field[Name=Profile].Field"),
+	)
+	ctrl.AssertEqual([]interface{}{modelFile.Funcs[0]}, select_("Type[Name=User].Method"))
+	ctrl.AssertEqual([]interface{}{modelFile.Funcs[0]}, select_("Method[Name=Validate]"))
+	ctrl.AssertEqual([]interface{}{modelFile.Funcs[1]}, select_("Func"))
+	ctrl.AssertEqual([]interface{}{modelFile.Funcs[0].Spec.Params[0]}, select_("Method.Param[Name=strict]"))
+	ctrl.AssertEqual([]interface{}{modelFile.Funcs[1].Spec.Results[0]}, select_("Func.Result"))
+	ctrl.AssertEqual([]interface{}{modelFile.ImportGroups[0].Imports[0]}, select_("Import[Name=time]"))
+	ctrl.AssertEqual([]interface{}{modelFile.ConstGroups[0].Consts[0]}, select_("File[Name=model.go].Const"))
+	ctrl.AssertEqual([]interface{}{}, select_("example.com/unknown:File"))
+
+	result, err := NewEntitySelector().Select(storage, "Type[Name=User].Field[Name=ID]")
+
+	ctrl.AssertNil(err)
+	ctrl.AssertEqual(
+		[]*SelectedEntity{
+			{Namespace: modelNamespace, File: modelFile, Entity: userFields[0], Parents: []interface{}{userType}},
+		},
+		result,
+	)
+}
+
+func TestEntitySelector_Select_WithInvalidSelector(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	expected := map[string]string{
+		"":                "Selector '' must have at least one step",
+		":Type":           "Selector ':Type' has empty namespace pattern",
+		"Type.":           "Selector 'Type.' has empty step",
+		"Unknown":         "Selector 'Unknown' is invalid: Step 'Unknown' has unknown kind",
+		"Field":           "Selector 'Field' has step 'Field' not allowed in 'Namespace'",
+		"Type.Param":      "Selector 'Type.Param' has step 'Param' not allowed in 'Type'",
+		"Type[Name=User":  "Selector 'Type[Name=User' is invalid: Step 'Type' has invalid predicate '[Name=User'",
+		"Type[+]":         "Selector 'Type[+]' is invalid: Predicate '+' is invalid",
+		"Type[Comment=a]": "Selector 'Type[Comment=a]' is invalid: Predicate 'Comment=a' must check Name or annotation",
+		"Type[Name]":      "Selector 'Type[Name]' is invalid: Predicate 'Name' must check Name or annotation",
+		"Type[@Entity=a]": "Selector 'Type[@Entity=a]' is invalid: Predicate '@Entity=a' must have key of annotation",
+		"Type[Name=[]": "Selector 'Type[Name=[]' is invalid: Predicate 'Name=[' has invalid pattern: " +
+			"syntax error in pattern",
+		"Type[@Entity.a=\\]": "Selector 'Type[@Entity.a=\\]' is invalid: Predicate '@Entity.a=\\' has invalid " +
+			"pattern: syntax error in pattern",
+	}
+
+	for selector, message := range expected {
+		actual, err := NewEntitySelector().Select(&Storage{}, selector)
+
+		ctrl.AssertNil(actual)
+		ctrl.AssertNotNil(err)
+		ctrl.AssertSame(message, err.Error())
+	}
+}
+
+func TestEntitySelector_Select_WithNilStorage(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntitySelector().Select, nil, "Type").
+		ExpectPanic(NewErrorMessageConstraint("Variable 'storage' must be not nil"))
+}
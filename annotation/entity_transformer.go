@@ -0,0 +1,126 @@
+package annotation
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// Used to stop traversal, when post ApplyFunc returns false.
+type transformerAbort struct{}
+
+// Traverses entity tree like EntityWalker and allows to modify it by Cursor.
+type EntityTransformer struct {
+	validator Validator
+	walker    *EntityWalker
+}
+
+// Creates new instance of EntityTransformer.
+func NewEntityTransformer(validator Validator) *EntityTransformer {
+	if validator == nil {
+		panic(errors.New("Variable 'validator' must be not nil"))
+	}
+
+	return &EntityTransformer{
+		validator: validator,
+		walker:    NewEntityWalker(),
+	}
+}
+
+// Traverses entity recursively, pre is called before children of entity and post after them, both could be nil.
+// If pre returns false or deletes entity, children and post are skipped. If post returns false, traversal is stopped.
+// Returns root entity, which could be replaced by Cursor.
+func (t *EntityTransformer) Apply(entity interface{}, pre ApplyFunc, post ApplyFunc) interface{} {
+	if entity == nil {
+		panic(errors.New("Variable 'entity' must be not nil"))
+	}
+
+	cursor := &Cursor{
+		validator: t.validator,
+		entity:    entity,
+		parents:   []interface{}{},
+	}
+
+	t.run(cursor, pre, post)
+
+	return cursor.entity
+}
+
+func (t *EntityTransformer) run(cursor *Cursor, pre ApplyFunc, post ApplyFunc) {
+	defer func() {
+		if err := recover(); err != nil {
+			if _, ok := err.(transformerAbort); !ok {
+				panic(err)
+			}
+		}
+	}()
+
+	t.apply(cursor, pre, post)
+}
+
+func (t *EntityTransformer) apply(cursor *Cursor, pre ApplyFunc, post ApplyFunc) {
+	if _, ok := t.walker.fields(cursor.entity); !ok {
+		panic(errors.Errorf("Can't transform entity with type: '%T'", cursor.entity))
+	}
+
+	if pre != nil && (!pre(cursor) || cursor.deleted) {
+		return
+	}
+
+	// Entity could be replaced by pre
+	entity := cursor.entity
+	parents := append(cursor.parents[:len(cursor.parents):len(cursor.parents)], entity)
+	fields, ok := t.walker.fields(entity)
+
+	if !ok {
+		panic(errors.Errorf("Can't transform entity with type: '%T'", entity))
+	}
+
+	for _, field := range fields {
+		if field.value.Kind() == reflect.Slice {
+			t.applyList(entity, parents, field.name, field.value, pre, post)
+		} else if !field.value.IsNil() {
+			t.apply(
+				&Cursor{
+					validator: t.validator,
+					entity:    field.value.Interface(),
+					parents:   parents,
+					name:      field.name,
+					field:     field.value,
+				},
+				pre,
+				post,
+			)
+		}
+	}
+
+	if post != nil && !post(cursor) {
+		panic(transformerAbort{})
+	}
+}
+
+func (t *EntityTransformer) applyList(
+	entity interface{},
+	parents []interface{},
+	name string,
+	field reflect.Value,
+	pre ApplyFunc,
+	post ApplyFunc,
+) {
+	list := &cursorList{value: field}
+
+	for list.step = 1; list.index < field.Len(); list.index += list.step {
+		list.step = 1
+		element := field.Index(list.index)
+
+		if element.IsNil() {
+			panic(errors.Errorf("Variable '%s[%d]' of '%T' must be not nil", name, list.index, entity))
+		}
+
+		t.apply(
+			&Cursor{validator: t.validator, entity: element.Interface(), parents: parents, name: name, list: list},
+			pre,
+			post,
+		)
+	}
+}
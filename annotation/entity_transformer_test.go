@@ -0,0 +1,412 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestNewEntityTransformer(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	validator := NewValidatorMock(ctrl)
+
+	actual := NewEntityTransformer(validator)
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(validator, actual.validator)
+	ctrl.AssertNotNil(actual.walker)
+}
+
+func TestNewEntityTransformer_WithNilValidator(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityTransformer, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'validator' must be not nil"))
+}
+
+func TestEntityTransformer_Apply(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	file := &File{
+		Name:        "file.go",
+		PackageName: "model",
+		TypeGroups: []*TypeGroup{
+			{
+				Types: []*Type{
+					{
+						Name: "Model",
+						Spec: &StructSpec{
+							Fields: []*Field{
+								{Name: "Items", Spec: &SimpleSpec{TypeName: "int"}},
+								{Name: "Removed", Spec: &SimpleSpec{TypeName: "int"}},
+								{Name: "ID", Spec: &SimpleSpec{TypeName: "int"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	expected := &File{
+		Name:        "file.go",
+		PackageName: "model",
+		TypeGroups: []*TypeGroup{
+			{
+				Types: []*Type{
+					{
+						Name: "Model",
+						Spec: &StructSpec{
+							Fields: []*Field{
+								{
+									Name: "Items",
+									Spec: &MapSpec{Key: &SimpleSpec{TypeName: "string"}, Value: &SimpleSpec{TypeName: "int"}},
+								},
+								{Name: "Before", Spec: &SimpleSpec{TypeName: "string"}},
+								{Name: "ID", Spec: &SimpleSpec{TypeName: "int"}},
+								{Name: "After", Spec: &SimpleSpec{TypeName: "string"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		Funcs: []*Func{
+			{Name: "Find", Spec: &FuncSpec{}, Related: &Field{Name: "m", Spec: &SimpleSpec{TypeName: "Model"}}},
+		},
+	}
+	visited := []string{}
+
+	actual := NewEntityTransformer(NewEntityValidator()).Apply(
+		file,
+		func(cursor *Cursor) bool {
+			field, ok := cursor.Entity().(*Field)
+
+			if !ok {
+				return true
+			}
+
+			visited = append(visited, field.Name)
+
+			switch field.Name {
+			case "Removed":
+				cursor.Delete()
+			case "ID":
+				ctrl.AssertSame(1, cursor.Index())
+				ctrl.AssertSame("Fields", cursor.Name())
+				ctrl.AssertSame(file.TypeGroups[0].Types[0].Spec, cursor.Parent())
+				ctrl.AssertSame(4, len(cursor.Parents()))
+				cursor.InsertBefore(&Field{Name: "Before", Spec: &SimpleSpec{TypeName: "string"}})
+				ctrl.AssertSame(2, cursor.Index())
+				cursor.InsertAfter(&Field{Name: "After", Spec: &SimpleSpec{TypeName: "string"}})
+			}
+
+			return true
+		},
+		func(cursor *Cursor) bool {
+			switch entity := cursor.Entity().(type) {
+			case *SimpleSpec:
+				if cursor.Name() == "Spec" && cursor.Parent().(*Field).Name == "Items" {
+					cursor.Replace(&MapSpec{Key: &SimpleSpec{TypeName: "string"}, Value: entity})
+				}
+			case *File:
+				ctrl.AssertSame(-1, cursor.Index())
+				ctrl.AssertNil(cursor.Parent())
+
+				entity.Funcs = append(
+					entity.Funcs,
+					&Func{Name: "Find", Spec: &FuncSpec{}, Related: &Field{Name: "m", Spec: &SimpleSpec{TypeName: "Model"}}},
+				)
+			}
+
+			return true
+		},
+	)
+
+	ctrl.AssertSame(file, actual)
+	ctrl.AssertEqual(expected, actual)
+	ctrl.AssertEqual([]string{"Items", "Removed", "ID"}, visited)
+	ctrl.AssertNil(NewEntityValidator().Validate(actual))
+}
+
+func TestEntityTransformer_Apply_WithReplacedRoot(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	expected := &ArraySpec{Value: &SimpleSpec{TypeName: "int"}}
+	visited := []interface{}{}
+
+	actual := NewEntityTransformer(NewEntityValidator()).Apply(
+		&SimpleSpec{TypeName: "int"},
+		func(cursor *Cursor) bool {
+			visited = append(visited, cursor.Entity())
+
+			if _, ok := cursor.Entity().(*SimpleSpec); ok && cursor.Parent() == nil {
+				cursor.Replace(expected)
+			}
+
+			return true
+		},
+		nil,
+	)
+
+	ctrl.AssertSame(expected, actual)
+	ctrl.AssertEqual([]interface{}{&SimpleSpec{TypeName: "int"}, expected.Value}, visited)
+}
+
+func TestEntityTransformer_Apply_WithPointerSpec(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &PointerSpec{Value: &ArraySpec{Value: &SimpleSpec{TypeName: "int"}}}
+	expected := &PointerSpec{Value: &ArraySpec{Value: &SimpleSpec{TypeName: "string"}}}
+
+	actual := NewEntityTransformer(NewEntityValidator()).Apply(
+		entity,
+		func(cursor *Cursor) bool {
+			if _, ok := cursor.Entity().(*SimpleSpec); ok {
+				cursor.Replace(&SimpleSpec{TypeName: "string"})
+			}
+
+			return true
+		},
+		nil,
+	)
+
+	ctrl.AssertSame(entity, actual)
+	ctrl.AssertEqual(expected, actual)
+}
+
+func TestEntityTransformer_Apply_WithSkippedChildren(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &Type{Name: "Model", Spec: &ArraySpec{Value: &SimpleSpec{TypeName: "int"}}}
+	visited := 0
+
+	NewEntityTransformer(NewEntityValidator()).Apply(
+		entity,
+		func(cursor *Cursor) bool {
+			_, ok := cursor.Entity().(*ArraySpec)
+
+			return !ok
+		},
+		func(cursor *Cursor) bool {
+			visited++
+
+			return true
+		},
+	)
+
+	ctrl.AssertSame(1, visited)
+}
+
+func TestEntityTransformer_Apply_WithStop(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &StructSpec{
+		Fields: []*Field{
+			{Name: "A", Spec: &SimpleSpec{TypeName: "int"}},
+			{Name: "B", Spec: &SimpleSpec{TypeName: "int"}},
+		},
+	}
+	visited := []interface{}{}
+
+	actual := NewEntityTransformer(NewEntityValidator()).Apply(
+		entity,
+		nil,
+		func(cursor *Cursor) bool {
+			visited = append(visited, cursor.Entity())
+
+			return cursor.Entity() != entity.Fields[0]
+		},
+	)
+
+	ctrl.AssertSame(entity, actual)
+	ctrl.AssertEqual([]interface{}{entity.Fields[0].Spec, entity.Fields[0]}, visited)
+}
+
+func TestEntityTransformer_Apply_WithInvalidReplace(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	spec := &SimpleSpec{TypeName: "int"}
+	entity := &Field{Name: "ID", Spec: spec}
+
+	ctrl.Subtest("").
+		Call(
+			NewEntityTransformer(NewEntityValidator()).Apply,
+			&StructSpec{Fields: []*Field{entity}},
+			func(cursor *Cursor) bool {
+				if cursor.Entity() == spec {
+					entity.IsEmbedded = true
+					entity.Name = ""
+					cursor.Replace(&MapSpec{Key: &SimpleSpec{TypeName: "string"}, Value: &SimpleSpec{TypeName: "int"}})
+				}
+
+				return true
+			},
+			nil,
+		).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'Spec' has invalid type for embedded field: '*annotation.MapSpec'"))
+
+	ctrl.AssertSame(spec, entity.Spec)
+}
+
+func TestEntityTransformer_Apply_WithInvalidEntity(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(
+			NewEntityTransformer(NewEntityValidator()).Apply,
+			&StructSpec{Fields: []*Field{{Name: "ID", Spec: &SimpleSpec{TypeName: "int"}}}},
+			func(cursor *Cursor) bool {
+				if _, ok := cursor.Entity().(*Field); ok {
+					cursor.InsertAfter(&Field{Name: "+invalid", Spec: &SimpleSpec{TypeName: "int"}})
+				}
+
+				return true
+			},
+			nil,
+		).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'Name' must be valid identifier, actual value: '+invalid'"))
+}
+
+func TestEntityTransformer_Apply_WithInvalidEntityType(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(
+			NewEntityTransformer(NewEntityValidator()).Apply,
+			&StructSpec{Fields: []*Field{{Name: "ID", Spec: &SimpleSpec{TypeName: "int"}}}},
+			func(cursor *Cursor) bool {
+				if _, ok := cursor.Entity().(*Field); ok {
+					cursor.Replace(&SimpleSpec{TypeName: "int"})
+				}
+
+				return true
+			},
+			nil,
+		).
+		ExpectPanic(
+			NewErrorMessageConstraint(
+				"Can't set entity with type '*annotation.SimpleSpec' to field with type '*annotation.Field'",
+			),
+		)
+}
+
+func TestEntityTransformer_Apply_WithDeleteNotSliceElement(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(
+			NewEntityTransformer(NewEntityValidator()).Apply,
+			&Field{Name: "ID", Spec: &SimpleSpec{TypeName: "int"}},
+			func(cursor *Cursor) bool {
+				cursor.Delete()
+
+				return true
+			},
+			nil,
+		).
+		ExpectPanic(NewErrorMessageConstraint("Entity with type '*annotation.Field' is not element of slice"))
+}
+
+func TestEntityTransformer_Apply_WithDeleteInPre(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	spec := &StructSpec{
+		Fields: []*Field{
+			{Name: "Removed", Spec: &SimpleSpec{TypeName: "int"}},
+			{Name: "ID", Spec: &SimpleSpec{TypeName: "string"}},
+		},
+	}
+	expected := &StructSpec{
+		Fields: []*Field{
+			{Name: "ID", Spec: &SimpleSpec{TypeName: "string"}},
+		},
+	}
+	events := []string{}
+	name := func(entity interface{}) string {
+		switch entity := entity.(type) {
+		case *Field:
+			return entity.Name
+		case *SimpleSpec:
+			return entity.TypeName
+		}
+
+		return "struct"
+	}
+
+	actual := NewEntityTransformer(NewEntityValidator()).Apply(
+		spec,
+		func(cursor *Cursor) bool {
+			events = append(events, "pre "+name(cursor.Entity()))
+
+			if field, ok := cursor.Entity().(*Field); ok && field.Name == "Removed" {
+				cursor.Delete()
+			}
+
+			return true
+		},
+		func(cursor *Cursor) bool {
+			events = append(events, "post "+name(cursor.Entity()))
+
+			return true
+		},
+	)
+
+	ctrl.AssertEqual(expected, actual)
+	ctrl.AssertEqual(
+		[]string{"pre struct", "pre Removed", "pre ID", "pre string", "post string", "post ID", "post struct"},
+		events,
+	)
+}
+
+func TestEntityTransformer_Apply_WithReplaceDeleted(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(
+			NewEntityTransformer(NewEntityValidator()).Apply,
+			&StructSpec{Fields: []*Field{{Name: "ID", Spec: &SimpleSpec{TypeName: "int"}}}},
+			func(cursor *Cursor) bool {
+				if _, ok := cursor.Entity().(*Field); ok {
+					cursor.Delete()
+					cursor.Replace(&Field{Name: "Name", Spec: &SimpleSpec{TypeName: "string"}})
+				}
+
+				return true
+			},
+			nil,
+		).
+		ExpectPanic(NewErrorMessageConstraint("Entity with type '*annotation.Field' is deleted"))
+}
+
+func TestEntityTransformer_Apply_WithNilEntity(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityTransformer(NewEntityValidator()).Apply, nil, nil, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'entity' must be not nil"))
+}
+
+func TestEntityTransformer_Apply_WithNotSupportedEntity(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityTransformer(NewEntityValidator()).Apply, &Var{Name: "name", Spec: "invalid"}, nil, nil).
+		ExpectPanic(NewErrorMessageConstraint("Can't transform entity with type: 'string'"))
+}
@@ -22,6 +22,8 @@ func (v *EntityValidator) Validate(entity interface{}) error {
 	switch entity := entity.(type) {
 	case *SimpleSpec:
 		return v.validateSimpleSpec(entity)
+	case *PointerSpec:
+		return v.validatePointerSpec(entity)
 	case *ArraySpec:
 		return v.validateArraySpec(entity)
 	case *MapSpec:
@@ -79,13 +81,26 @@ func (v *EntityValidator) validateSimpleSpec(entity *SimpleSpec) error {
 	return nil
 }
 
+func (v *EntityValidator) validatePointerSpec(entity *PointerSpec) error {
+	if entity.Value == nil {
+		return errors.New("Variable 'Value' must be not nil")
+	}
+
+	switch entity.Value.(type) {
+	case *SimpleSpec, *PointerSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec:
+		return v.Validate(entity.Value)
+	default:
+		return errors.Errorf("Variable 'Value' has invalid type: '%T'", entity.Value)
+	}
+}
+
 func (v *EntityValidator) validateArraySpec(entity *ArraySpec) error {
 	if entity.Value == nil {
 		return errors.New("Variable 'Value' must be not nil")
 	}
 
 	switch entity.Value.(type) {
-	case *SimpleSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec:
+	case *SimpleSpec, *PointerSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec:
 		if err := v.Validate(entity.Value); err != nil {
 			return err
 		}
@@ -112,7 +127,7 @@ func (v *EntityValidator) validateMapSpec(entity *MapSpec) error {
 	}
 
 	switch entity.Key.(type) {
-	case *SimpleSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec:
+	case *SimpleSpec, *PointerSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec:
 		if err := v.Validate(entity.Key); err != nil {
 			return err
 		}
@@ -121,7 +136,7 @@ func (v *EntityValidator) validateMapSpec(entity *MapSpec) error {
 	}
 
 	switch entity.Value.(type) {
-	case *SimpleSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec:
+	case *SimpleSpec, *PointerSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec:
 		if err := v.Validate(entity.Value); err != nil {
 			return err
 		}
@@ -141,8 +156,18 @@ func (v *EntityValidator) validateField(entity *Field) error {
 		return errors.New("Variable 'Spec' must be not nil")
 	}
 
+	if entity.IsEmbedded {
+		if entity.Name != "" {
+			return errors.Errorf("Variable 'Name' must be empty for embedded field, actual value: '%s'", entity.Name)
+		}
+
+		if _, ok := entity.Spec.(*SimpleSpec); !ok {
+			return errors.Errorf("Variable 'Spec' has invalid type for embedded field: '%T'", entity.Spec)
+		}
+	}
+
 	switch entity.Spec.(type) {
-	case *SimpleSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec:
+	case *SimpleSpec, *PointerSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec:
 		if err := v.Validate(entity.Spec); err != nil {
 			return err
 		}
@@ -339,7 +364,7 @@ func (v *EntityValidator) validateVar(entity *Var) error {
 
 	if entity.Spec != nil {
 		switch entity.Spec.(type) {
-		case *SimpleSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec:
+		case *SimpleSpec, *PointerSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec:
 			if err := v.Validate(entity.Spec); err != nil {
 				return err
 			}
@@ -385,7 +410,7 @@ func (v *EntityValidator) validateType(entity *Type) error {
 	}
 
 	switch entity.Spec.(type) {
-	case *SimpleSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec:
+	case *SimpleSpec, *PointerSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec:
 		if err := v.Validate(entity.Spec); err != nil {
 			return err
 		}
@@ -90,6 +90,64 @@ func TestEntityValidator_Validate_WithSimpleSpecAndInvalidPackageName(t *testing
 	ctrl.AssertSame("Variable 'PackageName' must be valid identifier, actual value: '+invalid'", actual.Error())
 }
 
+func TestEntityValidator_Validate_WithPointerSpec(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &PointerSpec{
+		Value: &MapSpec{
+			Key:   &SimpleSpec{TypeName: "keyTypeName"},
+			Value: &SimpleSpec{TypeName: "valueTypeName"},
+		},
+	}
+
+	actual := (&EntityValidator{}).Validate(entity)
+
+	ctrl.AssertNil(actual)
+}
+
+func TestEntityValidator_Validate_WithPointerSpecAndNilValue(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &PointerSpec{}
+
+	actual := (&EntityValidator{}).Validate(entity)
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame("Variable 'Value' must be not nil", actual.Error())
+}
+
+func TestEntityValidator_Validate_WithPointerSpecAndInvalidValueType(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &PointerSpec{
+		Value: &Import{
+			Namespace: "namespace",
+		},
+	}
+
+	actual := (&EntityValidator{}).Validate(entity)
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame("Variable 'Value' has invalid type: '*annotation.Import'", actual.Error())
+}
+
+func TestEntityValidator_Validate_WithPointerSpecAndInvalidSimpleSpecValue(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &PointerSpec{
+		Value: &SimpleSpec{},
+	}
+
+	actual := (&EntityValidator{}).Validate(entity)
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame("Variable 'TypeName' must be not empty", actual.Error())
+}
+
 func TestEntityValidator_Validate_WithArraySpecAndSimpleSpecValue(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
@@ -829,6 +887,59 @@ func TestEntityValidator_Validate_WithFieldAndInvalidSpecType(t *testing.T) {
 	ctrl.AssertSame("Variable 'Spec' has invalid type: 'string'", actual.Error())
 }
 
+func TestEntityValidator_Validate_WithFieldAndIsEmbedded(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &Field{
+		Spec: &SimpleSpec{
+			TypeName: "typeName",
+		},
+		IsEmbedded: true,
+	}
+
+	actual := (&EntityValidator{}).Validate(entity)
+
+	ctrl.AssertNil(actual)
+}
+
+func TestEntityValidator_Validate_WithFieldAndIsEmbeddedAndName(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &Field{
+		Name: "fieldName",
+		Spec: &SimpleSpec{
+			TypeName: "typeName",
+		},
+		IsEmbedded: true,
+	}
+
+	actual := (&EntityValidator{}).Validate(entity)
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame("Variable 'Name' must be empty for embedded field, actual value: 'fieldName'", actual.Error())
+}
+
+func TestEntityValidator_Validate_WithFieldAndIsEmbeddedAndInvalidSpecType(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &Field{
+		Spec: &ArraySpec{
+			Value: &SimpleSpec{
+				TypeName: "typeName",
+			},
+		},
+		IsEmbedded: true,
+	}
+
+	actual := (&EntityValidator{}).Validate(entity)
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame("Variable 'Spec' has invalid type for embedded field: '*annotation.ArraySpec'", actual.Error())
+}
+
 func TestEntityValidator_Validate_WithFuncSpecAndEmptyFields(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
@@ -0,0 +1,158 @@
+package annotation
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// Traverses entity tree in depth-first order from any entity (*Storage, *Namespace, *File ... *SimpleSpec).
+type EntityWalker struct {
+}
+
+// Creates new instance of EntityWalker.
+func NewEntityWalker() *EntityWalker {
+	return &EntityWalker{}
+}
+
+// Calls visitor.Enter before children of entity and visitor.Leave after them. If Enter returns false, children are
+// skipped, but Leave is called anyway. Parents are ordered from root to direct parent, they must not be modified.
+func (w *EntityWalker) Walk(entity interface{}, visitor Visitor) {
+	if entity == nil {
+		panic(errors.New("Variable 'entity' must be not nil"))
+	}
+
+	if visitor == nil {
+		panic(errors.New("Variable 'visitor' must be not nil"))
+	}
+
+	w.walk(entity, []interface{}{}, visitor)
+}
+
+// Calls callback for entity and its children in pre-order, children are skipped if callback returns false.
+func (w *EntityWalker) Inspect(entity interface{}, callback func(entity interface{}, parents []interface{}) bool) {
+	if callback == nil {
+		panic(errors.New("Variable 'callback' must be not nil"))
+	}
+
+	w.Walk(entity, &inspectVisitor{callback: callback})
+}
+
+func (w *EntityWalker) walk(entity interface{}, parents []interface{}, visitor Visitor) {
+	children := w.children(entity)
+
+	if visitor.Enter(entity, parents) {
+		childParents := append(parents[:len(parents):len(parents)], entity)
+
+		for _, child := range children {
+			w.walk(child, childParents, visitor)
+		}
+	}
+
+	visitor.Leave(entity, parents)
+}
+
+// Returns not nil direct children of entity in order of declaration.
+func (w *EntityWalker) children(entity interface{}) []interface{} {
+	fields, ok := w.fields(entity)
+
+	if !ok {
+		panic(errors.Errorf("Can't walk entity with type: '%T'", entity))
+	}
+
+	result := []interface{}{}
+	add := func(child reflect.Value) {
+		if !child.IsNil() {
+			result = append(result, child.Interface())
+		}
+	}
+
+	for _, field := range fields {
+		if field.value.Kind() != reflect.Slice {
+			add(field.value)
+
+			continue
+		}
+
+		for i := 0; i < field.value.Len(); i++ {
+			add(field.value.Index(i))
+		}
+	}
+
+	return result
+}
+
+// Returns fields of entity, which contain its children, in order of declaration. Returns false for unknown entity.
+func (w *EntityWalker) fields(entity interface{}) ([]entityField, bool) {
+	field := func(name string, pointer interface{}) entityField {
+		return entityField{name: name, value: reflect.ValueOf(pointer).Elem()}
+	}
+
+	switch entity := entity.(type) {
+	case *SimpleSpec, *Import:
+		return []entityField{}, true
+	case *PointerSpec:
+		return []entityField{field("Value", &entity.Value)}, true
+	case *ArraySpec:
+		return []entityField{field("Value", &entity.Value)}, true
+	case *MapSpec:
+		return []entityField{field("Key", &entity.Key), field("Value", &entity.Value)}, true
+	case *Field:
+		return []entityField{field("Spec", &entity.Spec)}, true
+	case *FuncSpec:
+		return []entityField{field("Params", &entity.Params), field("Results", &entity.Results)}, true
+	case *InterfaceSpec:
+		return []entityField{field("Fields", &entity.Fields)}, true
+	case *StructSpec:
+		return []entityField{field("Fields", &entity.Fields)}, true
+	case *ImportGroup:
+		return []entityField{field("Imports", &entity.Imports)}, true
+	case *Const:
+		return []entityField{field("Spec", &entity.Spec)}, true
+	case *ConstGroup:
+		return []entityField{field("Consts", &entity.Consts)}, true
+	case *Var:
+		return []entityField{field("Spec", &entity.Spec)}, true
+	case *VarGroup:
+		return []entityField{field("Vars", &entity.Vars)}, true
+	case *Type:
+		return []entityField{field("Spec", &entity.Spec)}, true
+	case *TypeGroup:
+		return []entityField{field("Types", &entity.Types)}, true
+	case *Func:
+		return []entityField{field("Related", &entity.Related), field("Spec", &entity.Spec)}, true
+	case *File:
+		return []entityField{
+			field("ImportGroups", &entity.ImportGroups),
+			field("ConstGroups", &entity.ConstGroups),
+			field("VarGroups", &entity.VarGroups),
+			field("TypeGroups", &entity.TypeGroups),
+			field("Funcs", &entity.Funcs),
+		}, true
+	case *Namespace:
+		return []entityField{field("Files", &entity.Files)}, true
+	case *Storage:
+		return []entityField{field("Namespaces", &entity.Namespaces)}, true
+	}
+
+	return nil, false
+}
+
+// Field of entity, which contains its children: single child or slice of them.
+type entityField struct {
+	name string
+	// Settable value of field.
+	value reflect.Value
+}
+
+// Adapts callback of Inspect to Visitor.
+type inspectVisitor struct {
+	callback func(entity interface{}, parents []interface{}) bool
+}
+
+func (v *inspectVisitor) Enter(entity interface{}, parents []interface{}) bool {
+	return v.callback(entity, parents)
+}
+
+func (v *inspectVisitor) Leave(entity interface{}, parents []interface{}) {
+}
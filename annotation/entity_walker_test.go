@@ -0,0 +1,208 @@
+package annotation
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+type walkerTestVisitor struct {
+	events []string
+	skip   map[interface{}]bool
+}
+
+func (v *walkerTestVisitor) Enter(entity interface{}, parents []interface{}) bool {
+	v.events = append(v.events, fmt.Sprintf("enter %T %d", entity, len(parents)))
+
+	return !v.skip[entity]
+}
+
+func (v *walkerTestVisitor) Leave(entity interface{}, parents []interface{}) {
+	v.events = append(v.events, fmt.Sprintf("leave %T %d", entity, len(parents)))
+}
+
+func TestNewEntityWalker(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	actual := NewEntityWalker()
+
+	ctrl.AssertNotNil(actual)
+}
+
+func TestEntityWalker_Walk(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	typeEntity := &Type{
+		Name: "Model",
+		Spec: &StructSpec{
+			Fields: []*Field{
+				{
+					Name: "Items",
+					Spec: &MapSpec{
+						Key:   &SimpleSpec{TypeName: "string"},
+						Value: &ArraySpec{Value: &SimpleSpec{TypeName: "int"}},
+					},
+				},
+			},
+		},
+	}
+	funcEntity := &Func{
+		Name:    "Find",
+		Related: &Field{Name: "m", Spec: &SimpleSpec{TypeName: "Model"}},
+		Spec:    &FuncSpec{Params: []*Field{{Name: "id", Spec: &SimpleSpec{TypeName: "int"}}}},
+	}
+	file := &File{
+		Name:         "file.go",
+		PackageName:  "model",
+		ImportGroups: []*ImportGroup{{Imports: []*Import{{Namespace: "time"}}}},
+		ConstGroups:  []*ConstGroup{{Consts: []*Const{{Name: "A", Value: "1"}}}},
+		VarGroups:    []*VarGroup{{Vars: []*Var{{Name: "b", Spec: &InterfaceSpec{}}}}},
+		TypeGroups:   []*TypeGroup{{Types: []*Type{typeEntity}}},
+		Funcs:        []*Func{funcEntity, {Name: "empty"}},
+	}
+	storage := &Storage{Namespaces: []*Namespace{{Name: "model", Files: []*File{file}}}}
+	visitor := &walkerTestVisitor{skip: map[interface{}]bool{funcEntity: true}}
+
+	NewEntityWalker().Walk(storage, visitor)
+
+	ctrl.AssertEqual(
+		[]string{
+			"enter *annotation.Storage 0",
+			"enter *annotation.Namespace 1",
+			"enter *annotation.File 2",
+			"enter *annotation.ImportGroup 3",
+			"enter *annotation.Import 4",
+			"leave *annotation.Import 4",
+			"leave *annotation.ImportGroup 3",
+			"enter *annotation.ConstGroup 3",
+			"enter *annotation.Const 4",
+			"leave *annotation.Const 4",
+			"leave *annotation.ConstGroup 3",
+			"enter *annotation.VarGroup 3",
+			"enter *annotation.Var 4",
+			"enter *annotation.InterfaceSpec 5",
+			"leave *annotation.InterfaceSpec 5",
+			"leave *annotation.Var 4",
+			"leave *annotation.VarGroup 3",
+			"enter *annotation.TypeGroup 3",
+			"enter *annotation.Type 4",
+			"enter *annotation.StructSpec 5",
+			"enter *annotation.Field 6",
+			"enter *annotation.MapSpec 7",
+			"enter *annotation.SimpleSpec 8",
+			"leave *annotation.SimpleSpec 8",
+			"enter *annotation.ArraySpec 8",
+			"enter *annotation.SimpleSpec 9",
+			"leave *annotation.SimpleSpec 9",
+			"leave *annotation.ArraySpec 8",
+			"leave *annotation.MapSpec 7",
+			"leave *annotation.Field 6",
+			"leave *annotation.StructSpec 5",
+			"leave *annotation.Type 4",
+			"leave *annotation.TypeGroup 3",
+			"enter *annotation.Func 3",
+			"leave *annotation.Func 3",
+			"enter *annotation.Func 3",
+			"leave *annotation.Func 3",
+			"leave *annotation.File 2",
+			"leave *annotation.Namespace 1",
+			"leave *annotation.Storage 0",
+		},
+		visitor.events,
+	)
+}
+
+func TestEntityWalker_Walk_WithPointerSpec(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &PointerSpec{Value: &ArraySpec{Value: &SimpleSpec{TypeName: "int"}}}
+	visitor := &walkerTestVisitor{}
+
+	NewEntityWalker().Walk(entity, visitor)
+
+	expected := []string{
+		"enter *annotation.PointerSpec 0",
+		"enter *annotation.ArraySpec 1",
+		"enter *annotation.SimpleSpec 2",
+		"leave *annotation.SimpleSpec 2",
+		"leave *annotation.ArraySpec 1",
+		"leave *annotation.PointerSpec 0",
+	}
+
+	ctrl.AssertEqual(expected, visitor.events)
+}
+
+func TestEntityWalker_Walk_WithNilEntity(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityWalker().Walk, nil, &walkerTestVisitor{}).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'entity' must be not nil"))
+}
+
+func TestEntityWalker_Walk_WithNilVisitor(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityWalker().Walk, &Storage{}, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'visitor' must be not nil"))
+}
+
+func TestEntityWalker_Walk_WithInvalidEntity(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityWalker().Walk, &Var{Name: "name", Spec: "invalid"}, &walkerTestVisitor{}).
+		ExpectPanic(NewErrorMessageConstraint("Can't walk entity with type: 'string'"))
+}
+
+func TestEntityWalker_Inspect(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	field := &Field{Name: "ID", Annotations: []interface{}{TestAnnotation{Name: "id"}}, Spec: &SimpleSpec{TypeName: "int"}}
+	structSpec := &StructSpec{Fields: []*Field{field}}
+	typeEntity := &Type{Name: "Model", Spec: structSpec}
+	typeGroup := &TypeGroup{Types: []*Type{typeEntity}}
+	file := &File{Name: "file.go", PackageName: "model", TypeGroups: []*TypeGroup{typeGroup}}
+
+	var actualParents []interface{}
+
+	NewEntityWalker().Inspect(file, func(entity interface{}, parents []interface{}) bool {
+		if field, ok := entity.(*Field); ok && len(field.Annotations) > 0 {
+			actualParents = parents
+		}
+
+		return true
+	})
+
+	ctrl.AssertEqual([]interface{}{file, typeGroup, typeEntity, structSpec}, actualParents)
+
+	visited := 0
+
+	NewEntityWalker().Inspect(file, func(entity interface{}, parents []interface{}) bool {
+		visited++
+
+		_, ok := entity.(*Type)
+
+		return !ok
+	})
+
+	ctrl.AssertSame(3, visited)
+}
+
+func TestEntityWalker_Inspect_WithNilCallback(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewEntityWalker().Inspect, &Storage{}, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'callback' must be not nil"))
+}
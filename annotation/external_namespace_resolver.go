@@ -0,0 +1,445 @@
+package annotation
+
+import (
+	"bytes"
+	"go/ast"
+	"go/build"
+	"go/constant"
+	"go/importer"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Loads declarations of not scanned packages (standard library, module cache) by go/types importer.
+type ExternalNamespaceResolver struct {
+	importer      types.Importer
+	specBuilder   *TypesSpecBuilder
+	importRenamer ImportRenamer
+	logger        Logger
+}
+
+// Creates lookup of compiled export data for "gc" importer, e.g.:
+//
+//	importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup(""))
+//
+// Path of export data is found by "go list -export" in dir (current folder if it's empty), so packages of module
+// and its dependencies are compiled into build cache, if they are absent there.
+func NewExportDataLookup(dir string) importer.Lookup {
+	return func(path string) (io.ReadCloser, error) {
+		stderr := &bytes.Buffer{}
+		command := exec.Command("go", "list", "-export", "-f", "{{.Export}}", path)
+		command.Dir = dir
+		command.Stderr = stderr
+
+		output, err := command.Output()
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "Can't find export data of package '%s': %s", path, stderr.String())
+		}
+
+		exportPath := strings.TrimSpace(string(output))
+
+		if exportPath == "" {
+			return nil, errors.Errorf("Package '%s' has no export data", path)
+		}
+
+		return os.Open(exportPath)
+	}
+}
+
+// Creates new instance of ExternalNamespaceResolver.
+func NewExternalNamespaceResolver(
+	importer types.Importer,
+	specBuilder *TypesSpecBuilder,
+	importRenamer ImportRenamer,
+) *ExternalNamespaceResolver {
+	if importer == nil {
+		panic(errors.New("Variable 'importer' must be not nil"))
+	}
+
+	if specBuilder == nil {
+		panic(errors.New("Variable 'specBuilder' must be not nil"))
+	}
+
+	if importRenamer == nil {
+		panic(errors.New("Variable 'importRenamer' must be not nil"))
+	}
+
+	return &ExternalNamespaceResolver{
+		importer:      importer,
+		specBuilder:   specBuilder,
+		importRenamer: importRenamer,
+	}
+}
+
+// Sets logger for warnings about declarations, which could not be represented by models.
+func (r *ExternalNamespaceResolver) SetLogger(logger Logger) {
+	if logger == nil {
+		panic(errors.New("Variable 'logger' must be not nil"))
+	}
+
+	r.logger = logger
+}
+
+// Returns namespace by its name. If storage has no such namespace, it will be loaded by importer and added to storage
+// as read only namespace with one File model, which contains exported declarations without content.
+// Unexported fields of structs are skipped. Declarations with type parameters or unexported types in their
+// specifications could not be represented by models, they are skipped with warning.
+func (r *ExternalNamespaceResolver) Resolve(storage *Storage, namespace string) *Namespace {
+	if storage == nil {
+		panic(errors.New("Variable 'storage' must be not nil"))
+	}
+
+	if namespace == "" {
+		panic(errors.New("Variable 'namespace' must be not empty"))
+	}
+
+	if result := storage.FindNamespaceByName(namespace); result != nil {
+		return result
+	}
+
+	pkg, err := r.importer.Import(namespace)
+
+	if err != nil {
+		panic(err)
+	}
+
+	buildPackage, err := build.Import(namespace, "", build.FindOnly)
+
+	if err != nil {
+		panic(err)
+	}
+
+	result := &Namespace{
+		Name:       namespace,
+		Path:       buildPackage.Dir,
+		IsReadOnly: true,
+		Files:      []*File{r.buildFile(pkg)},
+	}
+
+	storage.AddNamespace(result)
+
+	return result
+}
+
+// Resolves all imports of not read only namespaces, which are absent in storage.
+func (r *ExternalNamespaceResolver) ResolveImports(storage *Storage) {
+	if storage == nil {
+		panic(errors.New("Variable 'storage' must be not nil"))
+	}
+
+	// Namespaces list is extended during iteration, but new namespaces are read only
+	for _, namespace := range storage.Namespaces {
+		if namespace.IsReadOnly {
+			continue
+		}
+
+		for _, file := range namespace.Files {
+			for _, importGroup := range file.ImportGroups {
+				for _, element := range importGroup.Imports {
+					if element.Namespace != "C" {
+						r.Resolve(storage, element.Namespace)
+					}
+				}
+			}
+		}
+	}
+}
+
+func (r *ExternalNamespaceResolver) buildFile(pkg *types.Package) *File {
+	result := &File{
+		Name:         pkg.Name() + ".go",
+		PackageName:  pkg.Name(),
+		ImportGroups: []*ImportGroup{},
+		ConstGroups:  []*ConstGroup{},
+		VarGroups:    []*VarGroup{},
+		TypeGroups:   []*TypeGroup{},
+		Funcs:        []*Func{},
+	}
+
+	importGroup := &ImportGroup{Imports: []*Import{}}
+	constGroup := &ConstGroup{Consts: []*Const{}}
+	varGroup := &VarGroup{Vars: []*Var{}}
+	typeGroup := &TypeGroup{Types: []*Type{}}
+	methods := []*Func{}
+
+	scope := pkg.Scope()
+
+	for _, name := range scope.Names() {
+		object := scope.Lookup(name)
+
+		if !object.Exported() {
+			continue
+		}
+
+		switch object := object.(type) {
+		case *types.Const:
+			constGroup.Consts = append(constGroup.Consts, r.buildConst(pkg, object, importGroup))
+		case *types.Var:
+			if spec := r.buildSpec(pkg, object.Type(), importGroup); spec != nil {
+				varGroup.Vars = append(varGroup.Vars, &Var{Name: name, Spec: spec})
+			} else {
+				r.warnSkipped(pkg, object)
+			}
+		case *types.TypeName:
+			if element := r.buildType(pkg, object, importGroup); element != nil {
+				typeGroup.Types = append(typeGroup.Types, element)
+				methods = append(methods, r.buildMethods(pkg, object, importGroup)...)
+			} else {
+				r.warnSkipped(pkg, object)
+			}
+		case *types.Func:
+			if spec, ok := r.buildSpec(pkg, object.Type(), importGroup).(*FuncSpec); ok {
+				result.Funcs = append(result.Funcs, &Func{Name: name, Spec: spec})
+			} else {
+				r.warnSkipped(pkg, object)
+			}
+		}
+	}
+
+	result.Funcs = append(result.Funcs, methods...)
+
+	if len(importGroup.Imports) > 0 {
+		result.ImportGroups = append(result.ImportGroups, importGroup)
+	}
+
+	if len(constGroup.Consts) > 0 {
+		result.ConstGroups = append(result.ConstGroups, constGroup)
+	}
+
+	if len(varGroup.Vars) > 0 {
+		result.VarGroups = append(result.VarGroups, varGroup)
+	}
+
+	if len(typeGroup.Types) > 0 {
+		result.TypeGroups = append(result.TypeGroups, typeGroup)
+	}
+
+	return result
+}
+
+func (r *ExternalNamespaceResolver) buildConst(
+	pkg *types.Package,
+	object *types.Const,
+	importGroup *ImportGroup,
+) *Const {
+	result := &Const{
+		Name: object.Name(),
+	}
+
+	if value := object.Val(); value.Kind() == constant.Float {
+		floatValue, _ := constant.Float64Val(value)
+		result.Value = strconv.FormatFloat(floatValue, 'g', -1, 64)
+	} else {
+		result.Value = value.ExactString()
+	}
+
+	if spec, ok := r.buildSpec(pkg, object.Type(), importGroup).(*SimpleSpec); ok {
+		result.Spec = spec
+	}
+
+	return result
+}
+
+func (r *ExternalNamespaceResolver) buildType(
+	pkg *types.Package,
+	object *types.TypeName,
+	importGroup *ImportGroup,
+) *Type {
+	typ := object.Type()
+
+	if typeListLen(typ, "TypeParams") > 0 {
+		return nil
+	}
+
+	// Model has no aliases, so alias is represented as type with the same specification
+	if !object.IsAlias() {
+		typ = typ.Underlying()
+	}
+
+	spec := r.buildSpec(pkg, typ, importGroup)
+
+	if spec == nil {
+		return nil
+	}
+
+	return &Type{Name: object.Name(), Spec: spec}
+}
+
+func (r *ExternalNamespaceResolver) buildMethods(
+	pkg *types.Package,
+	object *types.TypeName,
+	importGroup *ImportGroup,
+) []*Func {
+	result := []*Func{}
+	named, ok := object.Type().(*types.Named)
+
+	if !ok || object.IsAlias() {
+		return result
+	}
+
+	for i := 0; i < named.NumMethods(); i++ {
+		method := named.Method(i)
+		signature := method.Type().(*types.Signature)
+
+		if !method.Exported() {
+			continue
+		}
+
+		spec, ok := r.buildSpec(pkg, signature, importGroup).(*FuncSpec)
+
+		if !ok {
+			r.warnSkipped(pkg, method)
+
+			continue
+		}
+
+		_, isPointer := signature.Recv().Type().(*types.Pointer)
+
+		result = append(
+			result,
+			&Func{
+				Name: method.Name(),
+				Spec: spec,
+				Related: &Field{
+					Name: signature.Recv().Name(),
+					Spec: &SimpleSpec{TypeName: object.Name(), IsPointer: isPointer},
+				},
+			},
+		)
+	}
+
+	return result
+}
+
+// Returns nil if type could not be represented by spec, used imports are added to importGroup.
+// Imports of packages with the same names get aliases with numeric suffix, e.g. "template_2".
+func (r *ExternalNamespaceResolver) buildSpec(
+	pkg *types.Package,
+	typ types.Type,
+	importGroup *ImportGroup,
+) interface{} {
+	if !r.isSupported(typ) {
+		return nil
+	}
+
+	spec, imports := r.specBuilder.Build(pkg, typ)
+	packageNames := map[string]bool{}
+
+	if !r.exportSpec(spec, packageNames) {
+		return nil
+	}
+
+	// Aliases are replaced by temporary ones at first, so new alias could not be equal to not renamed one
+	for i, element := range imports {
+		r.importRenamer.Rename(spec, element.RealAlias(), "_import"+strconv.Itoa(i))
+	}
+
+	for i, element := range imports {
+		if !packageNames[element.RealAlias()] {
+			continue
+		}
+
+		alias := ""
+
+		for _, existElement := range importGroup.Imports {
+			if existElement.Namespace == element.Namespace {
+				alias = existElement.RealAlias()
+
+				break
+			}
+		}
+
+		if alias == "" {
+			alias = uniqueAlias(importGroup.Imports, element.RealAlias())
+			existElement := &Import{Namespace: element.Namespace}
+
+			if existElement.RealAlias() != alias {
+				existElement.Alias = alias
+			}
+
+			importGroup.Imports = append(importGroup.Imports, existElement)
+		}
+
+		r.importRenamer.Rename(spec, "_import"+strconv.Itoa(i), alias)
+	}
+
+	return spec
+}
+
+// Removes unexported fields of structs and collects package names, which are used by spec.
+// Returns false if spec references unexported named type.
+func (r *ExternalNamespaceResolver) exportSpec(spec interface{}, packageNames map[string]bool) bool {
+	switch spec := spec.(type) {
+	case *SimpleSpec:
+		packageNames[spec.PackageName] = true
+
+		return ast.IsExported(spec.TypeName) || (spec.PackageName == "" && types.Universe.Lookup(spec.TypeName) != nil)
+	case *PointerSpec:
+		return r.exportSpec(spec.Value, packageNames)
+	case *ArraySpec:
+		return r.exportSpec(spec.Value, packageNames)
+	case *MapSpec:
+		return r.exportSpec(spec.Key, packageNames) && r.exportSpec(spec.Value, packageNames)
+	case *FuncSpec:
+		return r.exportFields(spec.Params, packageNames) && r.exportFields(spec.Results, packageNames)
+	case *InterfaceSpec:
+		return r.exportFields(spec.Fields, packageNames)
+	case *StructSpec:
+		fields := []*Field{}
+
+		for _, field := range spec.Fields {
+			name := field.Name
+
+			// Name of embedded field is name of its type
+			if simpleSpec, ok := field.Spec.(*SimpleSpec); ok && field.IsEmbedded {
+				name = simpleSpec.TypeName
+			}
+
+			if ast.IsExported(name) {
+				fields = append(fields, field)
+			}
+		}
+
+		spec.Fields = fields
+
+		return r.exportFields(spec.Fields, packageNames)
+	default:
+		return true
+	}
+}
+
+func (r *ExternalNamespaceResolver) exportFields(fields []*Field, packageNames map[string]bool) bool {
+	for _, field := range fields {
+		if !r.exportSpec(field.Spec, packageNames) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Declarations with types, which are not represented by specs (e.g. channels), are skipped.
+func (r *ExternalNamespaceResolver) warnSkipped(pkg *types.Package, object types.Object) {
+	if r.logger == nil {
+		return
+	}
+
+	r.logger.Warning(
+		"Declaration is skipped, its type is not supported",
+		"namespace",
+		pkg.Path(),
+		"declaration",
+		object.String(),
+	)
+}
+
+func (r *ExternalNamespaceResolver) isSupported(typ types.Type) bool {
+	return r.specBuilder.build(nil, typ, &[]*Import{}) != nil
+}
@@ -0,0 +1,369 @@
+package annotation
+
+import (
+	"bytes"
+	"go/importer"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestNewExternalNamespaceResolver(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	typesImporter := importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup(""))
+	specBuilder := NewTypesSpecBuilder()
+	importRenamer := NewEntityImportRenamer()
+
+	actual := NewExternalNamespaceResolver(typesImporter, specBuilder, importRenamer)
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(typesImporter, actual.importer)
+	ctrl.AssertSame(specBuilder, actual.specBuilder)
+	ctrl.AssertSame(importRenamer, actual.importRenamer)
+}
+
+func TestNewExternalNamespaceResolver_WithNilImporter(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewExternalNamespaceResolver, nil, NewTypesSpecBuilder(), NewEntityImportRenamer()).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'importer' must be not nil"))
+}
+
+func TestNewExternalNamespaceResolver_WithNilSpecBuilder(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(
+			NewExternalNamespaceResolver,
+			importer.ForCompiler(token.NewFileSet(), "gc", nil),
+			nil,
+			NewEntityImportRenamer(),
+		).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'specBuilder' must be not nil"))
+}
+
+func TestNewExternalNamespaceResolver_WithNilImportRenamer(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(
+			NewExternalNamespaceResolver,
+			importer.ForCompiler(token.NewFileSet(), "gc", nil),
+			NewTypesSpecBuilder(),
+			nil,
+		).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'importRenamer' must be not nil"))
+}
+
+func TestExternalNamespaceResolver_Resolve(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	storage := &Storage{}
+	resolver := NewExternalNamespaceResolver(
+		importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup("")),
+		NewTypesSpecBuilder(),
+		NewEntityImportRenamer(),
+	)
+
+	actual := resolver.Resolve(storage, "time")
+
+	ctrl.AssertSame(storage.Namespaces[0], actual)
+	ctrl.AssertSame("time", actual.Name)
+	ctrl.AssertTrue(actual.IsReadOnly)
+	ctrl.AssertSame("time", actual.PackageName())
+	ctrl.AssertNil(NewEntityValidator().Validate(storage))
+
+	file := actual.FindFileByName("time.go")
+
+	ctrl.AssertNotNil(file)
+
+	var durationType *Type
+
+	for _, element := range file.TypeGroups[0].Types {
+		if element.Name == "Duration" {
+			durationType = element
+		}
+	}
+
+	ctrl.AssertEqual(&Type{Name: "Duration", Spec: &SimpleSpec{TypeName: "int64"}}, durationType)
+
+	var hoursConst *Const
+
+	for _, element := range file.ConstGroups[0].Consts {
+		if element.Name == "Hour" {
+			hoursConst = element
+		}
+	}
+
+	ctrl.AssertEqual(&Const{Name: "Hour", Value: "3600000000000", Spec: &SimpleSpec{TypeName: "Duration"}}, hoursConst)
+
+	var stringMethod *Func
+
+	for _, element := range file.Funcs {
+		if element.Name == "String" && element.Related.Spec.(*SimpleSpec).TypeName == "Duration" {
+			stringMethod = element
+		}
+	}
+
+	ctrl.AssertEqual(
+		&Func{
+			Name: "String",
+			Spec: &FuncSpec{
+				Params:  []*Field{},
+				Results: []*Field{{Spec: &SimpleSpec{TypeName: "string"}}},
+			},
+			Related: &Field{Name: "d", Spec: &SimpleSpec{TypeName: "Duration"}},
+		},
+		stringMethod,
+	)
+
+	ctrl.AssertSame(actual, resolver.Resolve(storage, "time"))
+	ctrl.AssertSame(1, len(storage.Namespaces))
+}
+
+func TestExternalNamespaceResolver_ResolveImports(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	scanned := &Namespace{
+		Name: "namespace",
+		Path: "/namespace",
+		Files: []*File{
+			{
+				Name:        "file.go",
+				PackageName: "namespace",
+				ImportGroups: []*ImportGroup{
+					{
+						Imports: []*Import{
+							{Namespace: "errors"},
+							{Namespace: "C"},
+						},
+					},
+				},
+			},
+		},
+	}
+	storage := &Storage{Namespaces: []*Namespace{scanned}}
+
+	resolver := NewExternalNamespaceResolver(
+		importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup("")),
+		NewTypesSpecBuilder(),
+		NewEntityImportRenamer(),
+	)
+
+	resolver.ResolveImports(storage)
+
+	ctrl.AssertSame(2, len(storage.Namespaces))
+	ctrl.AssertSame(scanned, storage.Namespaces[0])
+	ctrl.AssertSame("errors", storage.Namespaces[1].Name)
+	ctrl.AssertTrue(storage.Namespaces[1].IsReadOnly)
+}
+
+func TestExternalNamespaceResolver_Resolve_WithEmptyNamespace(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	resolver := NewExternalNamespaceResolver(
+		importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup("")),
+		NewTypesSpecBuilder(),
+		NewEntityImportRenamer(),
+	)
+
+	ctrl.Subtest("").
+		Call(resolver.Resolve, &Storage{}, "").
+		ExpectPanic(NewErrorMessageConstraint("Variable 'namespace' must be not empty"))
+}
+
+func TestExternalNamespaceResolver_Resolve_WithNotSupportedDeclarations(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	buffer := &bytes.Buffer{}
+	resolver := NewExternalNamespaceResolver(
+		importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup("")),
+		NewTypesSpecBuilder(),
+		NewEntityImportRenamer(),
+	)
+	resolver.SetLogger(NewTextLogger(buffer))
+
+	actual := resolver.Resolve(&Storage{}, "os/signal")
+
+	for _, element := range actual.Files[0].Funcs {
+		ctrl.AssertNotSame("Notify", element.Name)
+	}
+
+	ctrl.AssertTrue(
+		strings.Contains(
+			buffer.String(),
+			`level=warning message="Declaration is skipped, its type is not supported" namespace=os/signal `+
+				`declaration="func os/signal.Notify(c chan<- os.Signal, sig ...os.Signal)"`,
+		),
+	)
+}
+
+func TestExternalNamespaceResolver_buildFile_WithNotExportedDeclarations(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	pkg := checkTypesPackage(t, `package model
+
+import "sync"
+
+type Bool struct {
+	_    noCopy
+	v    uint32
+	Name string
+	sync.Mutex
+	*hidden
+}
+
+type Pointer[T any] struct {
+	v *T
+}
+
+type noCopy struct{}
+
+type hidden struct{}
+
+func Hidden() *hidden {
+	return nil
+}
+
+func OnceValue[T any](f func() T) func() T {
+	return f
+}
+
+var Instance Pointer[int]
+`)
+	buffer := &bytes.Buffer{}
+	resolver := NewExternalNamespaceResolver(
+		importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup("")),
+		NewTypesSpecBuilder(),
+		NewEntityImportRenamer(),
+	)
+	resolver.SetLogger(NewTextLogger(buffer))
+
+	expected := &File{
+		Name:         "model.go",
+		PackageName:  "model",
+		ImportGroups: []*ImportGroup{{Imports: []*Import{{Namespace: "sync"}}}},
+		ConstGroups:  []*ConstGroup{},
+		VarGroups:    []*VarGroup{},
+		TypeGroups: []*TypeGroup{
+			{
+				Types: []*Type{
+					{
+						Name: "Bool",
+						Spec: &StructSpec{
+							Fields: []*Field{
+								{Name: "Name", Spec: &SimpleSpec{TypeName: "string"}},
+								{Spec: &SimpleSpec{PackageName: "sync", TypeName: "Mutex"}, IsEmbedded: true},
+							},
+						},
+					},
+				},
+			},
+		},
+		Funcs: []*Func{},
+	}
+
+	actual := resolver.buildFile(pkg)
+
+	ctrl.AssertEqual(expected, actual)
+	ctrl.AssertSame(
+		`level=warning message="Declaration is skipped, its type is not supported" namespace=example.com/model `+
+			`declaration="func example.com/model.Hidden() *example.com/model.hidden"`+"\n"+
+			`level=warning message="Declaration is skipped, its type is not supported" namespace=example.com/model `+
+			`declaration="var example.com/model.Instance example.com/model.Pointer[int]"`+"\n"+
+			`level=warning message="Declaration is skipped, its type is not supported" namespace=example.com/model `+
+			`declaration="func example.com/model.OnceValue[T any](f func() T) func() T"`+"\n"+
+			`level=warning message="Declaration is skipped, its type is not supported" namespace=example.com/model `+
+			`declaration="type example.com/model.Pointer[T any] struct{v *T}"`+"\n",
+		buffer.String(),
+	)
+}
+
+func TestExternalNamespaceResolver_buildFile_WithSamePackageNames(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	pkg := checkTypesPackage(t, `package model
+
+import (
+	htmltemplate "html/template"
+	"text/template"
+)
+
+var HTML *htmltemplate.Template
+
+var Text *template.Template
+
+func Both(t *template.Template, h *htmltemplate.Template) {}
+`)
+	resolver := NewExternalNamespaceResolver(
+		importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup("")),
+		NewTypesSpecBuilder(),
+		NewEntityImportRenamer(),
+	)
+
+	actual := resolver.buildFile(pkg)
+
+	ctrl.AssertEqual(
+		[]*Import{{Namespace: "text/template"}, {Namespace: "html/template", Alias: "template_2"}},
+		actual.ImportGroups[0].Imports,
+	)
+	ctrl.AssertEqual(
+		[]*Var{
+			{Name: "HTML", Spec: &SimpleSpec{PackageName: "template_2", TypeName: "Template", IsPointer: true}},
+			{Name: "Text", Spec: &SimpleSpec{PackageName: "template", TypeName: "Template", IsPointer: true}},
+		},
+		actual.VarGroups[0].Vars,
+	)
+	ctrl.AssertEqual(
+		&FuncSpec{
+			Params: []*Field{
+				{Name: "t", Spec: &SimpleSpec{PackageName: "template", TypeName: "Template", IsPointer: true}},
+				{Name: "h", Spec: &SimpleSpec{PackageName: "template_2", TypeName: "Template", IsPointer: true}},
+			},
+			Results: []*Field{},
+		},
+		actual.Funcs[0].Spec,
+	)
+}
+
+func TestExternalNamespaceResolver_SetLogger_WithNil(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	resolver := NewExternalNamespaceResolver(
+		importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup("")),
+		NewTypesSpecBuilder(),
+		NewEntityImportRenamer(),
+	)
+
+	ctrl.Subtest("").
+		Call(resolver.SetLogger, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'logger' must be not nil"))
+}
+
+func TestExportDataLookup_WithUnknownPackage(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	actual, err := NewExportDataLookup("")("github.com/index0h/go-annotation/unknown")
+
+	ctrl.AssertNil(actual)
+	ctrl.AssertNotNil(err)
+	ctrl.AssertTrue(
+		strings.HasPrefix(err.Error(), "Can't find export data of package 'github.com/index0h/go-annotation/unknown'"),
+	)
+}
@@ -7,6 +7,8 @@ type Field struct {
 	Tag         string
 	Comment     string
 	Annotations []interface{}
-	// Allowed types: *SimpleSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec.
+	// Allowed types: *SimpleSpec, *PointerSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec.
 	Spec interface{}
+	// Embedded field of struct or embedded interface has no name and *SimpleSpec.
+	IsEmbedded bool
 }
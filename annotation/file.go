@@ -11,4 +11,6 @@ type File struct {
 	VarGroups    []*VarGroup
 	TypeGroups   []*TypeGroup
 	Funcs        []*Func
+	// Original source layout, it's filled by GoSourceParser if layout is kept.
+	Layout *FileLayout
 }
@@ -0,0 +1,438 @@
+package annotation
+
+import (
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Prefix, which makes source of one declaration parsable.
+const declarationSourcePrefix = "package p\n\n"
+
+// Matches whitespaces, which are ignored by comparison of declaration parts.
+var sourceSpacesRegexp = regexp.MustCompile(`\s+`)
+
+// Kinds of declarations in order of rendering.
+var declarationKinds = []string{"import", "const", "var", "type", "func"}
+
+// FileLayout keeps original source of parsed file, so EntityRenderer renders unchanged file byte-for-byte and
+// changed file with original comments, grouping and blank lines of unchanged declarations.
+type FileLayout struct {
+	// Source from start of file to the end of package clause, it includes build tags and free-floating comments.
+	Header string
+	// Original package name and comment, Header is rendered again if they are changed.
+	PackageName  string
+	Comment      string
+	Declarations []*DeclarationLayout
+	// Source after the last declaration.
+	Trailer string
+}
+
+// DeclarationLayout keeps original source of one top-level declaration.
+type DeclarationLayout struct {
+	// One of "import", "const", "var", "type" or "func".
+	Kind string
+	// Index of declaration in list of file declarations with the same kind, e.g. File.TypeGroups.
+	Index int
+	// Source between previous and current declarations: blank lines and free-floating comments.
+	Leading string
+	// Source of declaration with its doc comment.
+	Source string
+	// Declaration parsed from Source, declaration is rendered again only if it differs from Original.
+	Original interface{}
+}
+
+// Returns top-level declarations of file by kind.
+func fileDeclarations(file *File) map[string][]interface{} {
+	result := map[string][]interface{}{}
+
+	for _, element := range file.ImportGroups {
+		result["import"] = append(result["import"], element)
+	}
+
+	for _, element := range file.ConstGroups {
+		result["const"] = append(result["const"], element)
+	}
+
+	for _, element := range file.VarGroups {
+		result["var"] = append(result["var"], element)
+	}
+
+	for _, element := range file.TypeGroups {
+		result["type"] = append(result["type"], element)
+	}
+
+	for _, element := range file.Funcs {
+		result["func"] = append(result["func"], element)
+	}
+
+	return result
+}
+
+// Returns original declarations of layout by kind.
+func layoutDeclarations(layout *FileLayout) map[string][]interface{} {
+	result := map[string][]interface{}{}
+
+	for _, element := range layout.Declarations {
+		result[element.Kind] = append(result[element.Kind], element.Original)
+	}
+
+	return result
+}
+
+// Returns indexes of original declarations by indexes of current ones. Declarations are paired by names, groups
+// with changed names are paired in order of declaration.
+func pairDeclarations(kind string, original []interface{}, current []interface{}) map[int]int {
+	result := map[int]int{}
+	isPaired := map[int]bool{}
+
+	for i, element := range current {
+		key := strings.Join(describeDeclaration(element), ", ")
+
+		for j, originalElement := range original {
+			if !isPaired[j] && key == strings.Join(describeDeclaration(originalElement), ", ") {
+				result[i] = j
+				isPaired[j] = true
+
+				break
+			}
+		}
+	}
+
+	if kind == "func" {
+		return result
+	}
+
+	j := 0
+
+	for i := range current {
+		if _, ok := result[i]; ok {
+			continue
+		}
+
+		for j < len(original) && isPaired[j] {
+			j++
+		}
+
+		if j == len(original) {
+			break
+		}
+
+		result[i] = j
+		isPaired[j] = true
+	}
+
+	return result
+}
+
+// Returns names of declarations, e.g. "type Model" or "func Model.Validate".
+func describeDeclaration(entity interface{}) []string {
+	result := []string{}
+
+	switch entity := entity.(type) {
+	case *ImportGroup:
+		for _, element := range entity.Imports {
+			result = append(result, "import "+element.Namespace)
+		}
+	case *ConstGroup:
+		for _, element := range entity.Consts {
+			result = append(result, "const "+element.Name)
+		}
+	case *VarGroup:
+		for _, element := range entity.Vars {
+			result = append(result, "var "+element.Name)
+		}
+	case *TypeGroup:
+		for _, element := range entity.Types {
+			result = append(result, "type "+element.Name)
+		}
+	case *Func:
+		name := entity.Name
+
+		if entity.Related != nil {
+			if spec, ok := entity.Related.Spec.(*SimpleSpec); ok {
+				name = spec.TypeName + "." + name
+			}
+		}
+
+		result = append(result, "func "+name)
+	}
+
+	return result
+}
+
+// Part of content, which is replaced by new content.
+type sourceEdit struct {
+	begin   int
+	end     int
+	content string
+}
+
+// Replaces parts of content by edits, edits must not overlap.
+func applySourceEdits(content string, edits []*sourceEdit) string {
+	sort.SliceStable(edits, func(i, j int) bool {
+		return edits[i].begin < edits[j].begin
+	})
+
+	result := ""
+	offset := 0
+
+	for _, edit := range edits {
+		if edit.begin > offset {
+			result += content[offset:edit.begin]
+		}
+
+		result += edit.content
+
+		if edit.end > offset {
+			offset = edit.end
+		}
+	}
+
+	return result + content[offset:]
+}
+
+// Part of declaration source: spec of declaration or field of struct and interface.
+type sourcePart struct {
+	key string
+	// Bounds of part with its doc comment, end of line comment is lineEnd.
+	begin   int
+	end     int
+	lineEnd int
+	node    ast.Node
+}
+
+// Returns rendered declaration with original source of its unchanged specs and fields. Line comments of changed
+// specs and fields, blank lines and free-floating comments between them are kept too. Source must contain one
+// declaration with its doc comment, rendered declaration is returned as is, if declarations can't be matched.
+func keepDeclarationLayout(source string, rendered string) string {
+	source = declarationSourcePrefix + source
+	rendered = declarationSourcePrefix + rendered
+	fileSet := token.NewFileSet()
+	sourceFile, err := parser.ParseFile(fileSet, "", source, parser.ParseComments)
+
+	if err != nil || len(sourceFile.Decls) != 1 {
+		return strings.TrimPrefix(rendered, declarationSourcePrefix)
+	}
+
+	renderedFile, err := parser.ParseFile(fileSet, "", rendered, parser.ParseComments)
+
+	if err != nil || len(renderedFile.Decls) != 1 {
+		return strings.TrimPrefix(rendered, declarationSourcePrefix)
+	}
+
+	sourceDecl, isSourceGeneral := sourceFile.Decls[0].(*ast.GenDecl)
+	renderedDecl, isRenderedGeneral := renderedFile.Decls[0].(*ast.GenDecl)
+
+	// Comments inside of function body are rendered from Func.Content
+	if !isSourceGeneral || !isRenderedGeneral || sourceDecl.Tok != renderedDecl.Tok {
+		return strings.TrimPrefix(rendered, declarationSourcePrefix)
+	}
+
+	edits := keepPartsLayout(
+		fileSet,
+		source,
+		rendered,
+		specSourceParts(fileSet, sourceDecl.Specs),
+		specSourceParts(fileSet, renderedDecl.Specs),
+	)
+
+	result, err := format.Source([]byte(applySourceEdits(rendered, edits)))
+
+	if err != nil {
+		return strings.TrimPrefix(rendered, declarationSourcePrefix)
+	}
+
+	return strings.TrimRight(strings.TrimPrefix(string(result), declarationSourcePrefix), "\n")
+}
+
+// Returns edits of rendered source, which restore source of unchanged parts and layout between parts.
+func keepPartsLayout(
+	fileSet *token.FileSet,
+	source string,
+	rendered string,
+	sourceParts []*sourcePart,
+	renderedParts []*sourcePart,
+) []*sourceEdit {
+	result := []*sourceEdit{}
+	isPaired := map[int]bool{}
+	pairs := map[int]int{}
+
+	for i, renderedPart := range renderedParts {
+		for j, sourcePart := range sourceParts {
+			if !isPaired[j] && sourcePart.key == renderedPart.key {
+				pairs[i] = j
+				isPaired[j] = true
+
+				break
+			}
+		}
+	}
+
+	for i, renderedPart := range renderedParts {
+		j, ok := pairs[i]
+
+		if !ok {
+			continue
+		}
+
+		sourcePart := sourceParts[j]
+
+		// Blank lines and free-floating comments are kept between parts, which are kept in the same order
+		if previous, ok := pairs[i-1]; ok && previous == j-1 {
+			result = append(
+				result,
+				&sourceEdit{
+					begin:   renderedParts[i-1].lineEnd,
+					end:     renderedPart.begin,
+					content: source[sourceParts[j-1].lineEnd:sourcePart.begin],
+				},
+			)
+		}
+
+		if sourceSpacesRegexp.ReplaceAllString(source[sourcePart.begin:sourcePart.end], " ") ==
+			sourceSpacesRegexp.ReplaceAllString(rendered[renderedPart.begin:renderedPart.end], " ") {
+			result = append(
+				result,
+				&sourceEdit{
+					begin:   renderedPart.begin,
+					end:     renderedPart.lineEnd,
+					content: source[sourcePart.begin:sourcePart.lineEnd],
+				},
+			)
+
+			continue
+		}
+
+		sourceFields := fieldsOfSourcePart(sourcePart.node)
+		renderedFields := fieldsOfSourcePart(renderedPart.node)
+
+		if sourceFields != nil && renderedFields != nil {
+			result = append(
+				result,
+				keepPartsLayout(
+					fileSet,
+					source,
+					rendered,
+					fieldSourceParts(fileSet, source, sourceFields),
+					fieldSourceParts(fileSet, rendered, renderedFields),
+				)...,
+			)
+		}
+
+		if sourcePart.lineEnd > sourcePart.end && renderedPart.lineEnd == renderedPart.end {
+			result = append(
+				result,
+				&sourceEdit{
+					begin:   renderedPart.end,
+					end:     renderedPart.end,
+					content: source[sourcePart.end:sourcePart.lineEnd],
+				},
+			)
+		}
+	}
+
+	return result
+}
+
+// Returns fields of struct or interface, which is declared by spec or field.
+func fieldsOfSourcePart(node ast.Node) *ast.FieldList {
+	var expr ast.Expr
+
+	switch node := node.(type) {
+	case *ast.TypeSpec:
+		expr = node.Type
+	case *ast.Field:
+		expr = node.Type
+	}
+
+	switch expr := expr.(type) {
+	case *ast.StructType:
+		return expr.Fields
+	case *ast.InterfaceType:
+		return expr.Methods
+	}
+
+	return nil
+}
+
+func specSourceParts(fileSet *token.FileSet, specs []ast.Spec) []*sourcePart {
+	result := []*sourcePart{}
+
+	for _, spec := range specs {
+		var doc, comment *ast.CommentGroup
+
+		key := ""
+
+		switch spec := spec.(type) {
+		case *ast.ImportSpec:
+			doc, comment, key = spec.Doc, spec.Comment, spec.Path.Value
+		case *ast.ValueSpec:
+			doc, comment = spec.Doc, spec.Comment
+
+			for _, name := range spec.Names {
+				key += name.Name + ","
+			}
+		case *ast.TypeSpec:
+			doc, comment, key = spec.Doc, spec.Comment, spec.Name.Name
+		}
+
+		result = append(result, newSourcePart(fileSet, key, spec, doc, comment))
+	}
+
+	return result
+}
+
+func fieldSourceParts(fileSet *token.FileSet, content string, fields *ast.FieldList) []*sourcePart {
+	result := []*sourcePart{}
+
+	for _, field := range fields.List {
+		key := ""
+
+		for _, name := range field.Names {
+			key += name.Name + ","
+		}
+
+		// Embedded field is identified by its type
+		if key == "" {
+			begin := fileSet.Position(field.Type.Pos()).Offset
+			end := fileSet.Position(field.Type.End()).Offset
+			key = sourceSpacesRegexp.ReplaceAllString(content[begin:end], "")
+		}
+
+		result = append(result, newSourcePart(fileSet, key, field, field.Doc, field.Comment))
+	}
+
+	return result
+}
+
+func newSourcePart(
+	fileSet *token.FileSet,
+	key string,
+	node ast.Node,
+	doc *ast.CommentGroup,
+	comment *ast.CommentGroup,
+) *sourcePart {
+	result := &sourcePart{
+		key:   key,
+		begin: fileSet.Position(node.Pos()).Offset,
+		end:   fileSet.Position(node.End()).Offset,
+		node:  node,
+	}
+
+	if doc != nil {
+		result.begin = fileSet.Position(doc.Pos()).Offset
+	}
+
+	result.lineEnd = result.end
+
+	if comment != nil {
+		result.lineEnd = fileSet.Position(comment.End()).Offset
+	}
+
+	return result
+}
@@ -3,46 +3,257 @@ package annotation
 import (
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Cleaner panics, if generated file was modified manually.
+	ModifiedFilePolicyError = "error"
+	// Cleaner logs warning and removes generated file, which was modified manually.
+	ModifiedFilePolicyWarning = "warning"
 )
 
+// Matches standard marker of generated go files.
+var generatedMarkerRegexp = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
 type FileIsGeneratedAnnotation bool
 
+// Producer of generated file, it's written into file header.
+type FileGeneratedByAnnotation struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// Returns name of generator from FileGeneratedByAnnotation of file or "unknown".
+func fileOwnerName(file *File) string {
+	for _, rawAnnotation := range file.Annotations {
+		if annotation, ok := rawAnnotation.(FileGeneratedByAnnotation); ok {
+			return annotation.Name
+		}
+	}
+
+	return "unknown"
+}
+
 type GeneratedFileCleaner struct {
+	owners             map[string]bool
+	modifiedFilePolicy string
+	logger             Logger
+	sourceParser       SourceParser
 }
 
 func NewGeneratedFileCleaner() *GeneratedFileCleaner {
-	return &GeneratedFileCleaner{}
+	return &GeneratedFileCleaner{modifiedFilePolicy: ModifiedFilePolicyError}
+}
+
+// Sets behavior for generated files, which content doesn't match hash from Manifest.
+func (c *GeneratedFileCleaner) SetModifiedFilePolicy(policy string) {
+	if policy != ModifiedFilePolicyError && policy != ModifiedFilePolicyWarning {
+		panic(errors.Errorf("Variable 'policy' has unknown value: '%s'", policy))
+	}
+
+	c.modifiedFilePolicy = policy
+}
+
+// Sets parser, which is used to parse declarations of files after their regions are emptied.
+func (c *GeneratedFileCleaner) SetSourceParser(sourceParser SourceParser) {
+	if sourceParser == nil {
+		panic(errors.New("Variable 'sourceParser' must be not nil"))
+	}
+
+	c.sourceParser = sourceParser
+}
+
+// Sets logger for warnings about modified generated files.
+func (c *GeneratedFileCleaner) SetLogger(logger Logger) {
+	if logger == nil {
+		panic(errors.New("Variable 'logger' must be not nil"))
+	}
+
+	c.logger = logger
 }
 
-// Removes old generated files with content and FileIsGeneratedAnnotation annotation.
-func (*GeneratedFileCleaner) Clean(storage *Storage) {
+// Sets names of generators, which files could be removed. Nil names allows to remove any generated file.
+// Files without FileGeneratedByAnnotation are treated as owned by any generator.
+func (c *GeneratedFileCleaner) SetOwners(names []string) {
+	if names == nil {
+		c.owners = nil
+
+		return
+	}
+
+	c.owners = map[string]bool{}
+
+	for _, name := range names {
+		c.owners[name] = true
+	}
+}
+
+// Removes old generated files with content, which are owned by owners, and empties regions of owners in other files.
+// Regions are emptied only in storage, files are rewritten by storage writer.
+// Files, which were modified after generation according to Manifest of namespace, are handled by modified file policy.
+func (c *GeneratedFileCleaner) Clean(storage *Storage) {
+	isChanged := false
+
+	defer func() {
+		if isChanged {
+			storage.Reindex()
+		}
+	}()
+
+	cleanings := []*namespaceCleaning{}
+
+	// All manifests are checked before removal, so modified file doesn't stop cleaning in the middle
 	for _, namespace := range storage.Namespaces {
-		if namespace.IsIgnored {
+		if namespace.IsIgnored || namespace.IsReadOnly {
 			continue
 		}
 
-		resultFiles := make([]*File, 0, len(namespace.Files))
+		cleaning := &namespaceCleaning{namespace: namespace, manifest: ReadManifest(namespace.Path), files: []*File{}}
 
 		for _, file := range namespace.Files {
-			removeFile := false
+			if c.isGenerated(file, cleaning.manifest) {
+				if c.IsOwned(file) {
+					cleaning.files = append(cleaning.files, file)
+				}
+			} else if c.cleanRegions(namespace, file) {
+				isChanged = true
+			}
+		}
 
-			for _, rawAnnotation := range file.Annotations {
-				if annotation, ok := rawAnnotation.(FileIsGeneratedAnnotation); ok && bool(annotation) {
-					removeFile = true
+		if len(cleaning.files) == 0 {
+			continue
+		}
 
-					if err := os.Remove(filepath.Join(namespace.Path, file.Name)); err != nil {
-						panic(err)
-					}
+		for _, file := range cleaning.files {
+			if cleaning.manifest.IsModified(file.Name, file.Content) {
+				c.reportModified(filepath.Join(namespace.Path, file.Name))
+			}
+		}
 
-					break
-				}
+		cleanings = append(cleanings, cleaning)
+	}
+
+	for _, cleaning := range cleanings {
+		for _, file := range cleaning.files {
+			if err := os.Remove(filepath.Join(cleaning.namespace.Path, file.Name)); err != nil {
+				panic(err)
 			}
 
-			if !removeFile {
-				resultFiles = append(resultFiles, file)
+			cleaning.manifest.Remove(file.Name)
+			storage.RemoveFile(file)
+		}
+
+		cleaning.manifest.Write(cleaning.namespace.Path)
+	}
+}
+
+// Generated files of namespace, which must be removed, and manifest of namespace.
+type namespaceCleaning struct {
+	namespace *Namespace
+	manifest  *Manifest
+	files     []*File
+}
+
+// Checks that file has FileIsGeneratedAnnotation or standard generated marker with FileGeneratedByAnnotation.
+// Files of other tools have only standard marker, so they are not treated as generated. Clean also treats files with
+// only standard marker as generated, if they are listed in Manifest of namespace.
+func (c *GeneratedFileCleaner) IsGenerated(file *File) bool {
+	isOwned := false
+
+	for _, rawAnnotation := range file.Annotations {
+		switch annotation := rawAnnotation.(type) {
+		case FileIsGeneratedAnnotation:
+			if bool(annotation) {
+				return true
 			}
+		case FileGeneratedByAnnotation:
+			isOwned = true
+		}
+	}
+
+	return isOwned && hasGeneratedMarker(file.Content)
+}
+
+// Checks that file is generated or it has standard generated marker and it's written by generation to manifest.
+func (c *GeneratedFileCleaner) isGenerated(file *File, manifest *Manifest) bool {
+	return c.IsGenerated(file) || (manifest.Contains(file.Name) && hasGeneratedMarker(file.Content))
+}
+
+// Checks that header comments of content contain standard marker of generated go files.
+func hasGeneratedMarker(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+
+		if generatedMarkerRegexp.MatchString(line) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Checks that file could be removed or overwritten by owners. Merged file must be owned by all its generators.
+func (c *GeneratedFileCleaner) IsOwned(file *File) bool {
+	if c.owners == nil {
+		return true
+	}
+
+	for _, rawAnnotation := range file.Annotations {
+		if annotation, ok := rawAnnotation.(FileGeneratedByAnnotation); ok && !c.owners[annotation.Name] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Empties regions of owners in file content, returns true if content is changed.
+func (c *GeneratedFileCleaner) cleanRegions(namespace *Namespace, file *File) bool {
+	if !strings.Contains(file.Content, RegionBeginMarker) {
+		return false
+	}
+
+	content, err := replaceRegions(file.Content, func(name string) (string, bool) {
+		return "", c.owners == nil || c.owners[regionOwner(name)]
+	})
+
+	if err != nil {
+		panic(errors.Wrapf(err, "Can't clean regions of file '%s'", filepath.Join(namespace.Path, file.Name)))
+	}
+
+	if content == file.Content {
+		return false
+	}
+
+	if c.sourceParser != nil {
+		*file = *c.sourceParser.Parse(file.Name, content)
+	} else {
+		file.Content = content
+	}
+
+	return true
+}
+
+func (c *GeneratedFileCleaner) reportModified(path string) {
+	if c.modifiedFilePolicy == ModifiedFilePolicyWarning {
+		if c.logger != nil {
+			c.logger.Warning("Generated file was modified manually, it's removed", "file", path)
 		}
 
-		namespace.Files = resultFiles
+		return
 	}
+
+	panic(errors.Errorf("Generated file '%s' was modified manually", path))
 }
@@ -1,6 +1,7 @@
 package annotation
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
@@ -123,7 +124,7 @@ func TestGeneratedFileCleaner_Clean(t *testing.T) {
 
 	(&GeneratedFileCleaner{}).Clean(storage)
 
-	ctrl.AssertEqual(expected, storage)
+	ctrl.AssertEqual(expected.Namespaces, storage.Namespaces)
 
 	fs.AssertNotFileExists("namespace1/1.go")
 	fs.AssertFileExists("namespace1/2.go")
@@ -174,3 +175,347 @@ func TestGeneratedFileCleaner_Clean_WithFileNotExists(t *testing.T) {
 		Call((&GeneratedFileCleaner{}).Clean, storage).
 		ExpectPanic(ctrl.Type(&os.PathError{}))
 }
+
+func TestGeneratedFileCleaner_Clean_WithOwners(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fs := NewTmpFS(ctrl).
+		CreateFile("owned.go", 0666, "package namespace1").
+		CreateFile("foreign.go", 0666, "package namespace1").
+		CreateFile("merged.go", 0666, "package namespace1").
+		CreateFile("legacy.go", 0666, "package namespace1")
+
+	foreignFile := &File{
+		Name:        "foreign.go",
+		Content:     "package namespace1",
+		PackageName: "namespace1",
+		Annotations: []interface{}{
+			FileIsGeneratedAnnotation(true),
+			FileGeneratedByAnnotation{Name: "foreign"},
+		},
+	}
+	mergedFile := &File{
+		Name:        "merged.go",
+		Content:     "package namespace1",
+		PackageName: "namespace1",
+		Annotations: []interface{}{
+			FileIsGeneratedAnnotation(true),
+			FileGeneratedByAnnotation{Name: "owner"},
+			FileGeneratedByAnnotation{Name: "foreign"},
+		},
+	}
+
+	storage := &Storage{
+		Namespaces: []*Namespace{
+			{
+				Name: "namespace1",
+				Path: fs.RootPath(),
+				Files: []*File{
+					{
+						Name:        "owned.go",
+						Content:     "package namespace1",
+						PackageName: "namespace1",
+						Annotations: []interface{}{
+							FileIsGeneratedAnnotation(true),
+							FileGeneratedByAnnotation{Name: "owner", Version: "1.0"},
+						},
+					},
+					foreignFile,
+					mergedFile,
+					{
+						Name:        "legacy.go",
+						Content:     "package namespace1",
+						PackageName: "namespace1",
+						Annotations: []interface{}{
+							FileIsGeneratedAnnotation(true),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cleaner := NewGeneratedFileCleaner()
+	cleaner.SetOwners([]string{"owner"})
+	cleaner.Clean(storage)
+
+	ctrl.AssertEqual([]*File{foreignFile, mergedFile}, storage.Namespaces[0].Files)
+
+	fs.AssertNotFileExists("owned.go")
+	fs.AssertFileExists("foreign.go")
+	fs.AssertFileExists("merged.go")
+	fs.AssertNotFileExists("legacy.go")
+}
+
+func TestGeneratedFileCleaner_Clean_WithModifiedFile(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	manifest := NewManifest()
+	manifest.Set("modified.go", "package namespace1")
+	manifest.Set("stale.go", "package namespace1")
+
+	fs := NewTmpFS(ctrl).CreateFile("modified.go", 0666, "package namespace1\n\nfunc Edited() {}")
+
+	manifest.Write(fs.RootPath())
+
+	newStorage := func() *Storage {
+		return &Storage{
+			Namespaces: []*Namespace{
+				{
+					Name: "namespace1",
+					Path: fs.RootPath(),
+					Files: []*File{
+						{
+							Name:        "modified.go",
+							Content:     "package namespace1\n\nfunc Edited() {}",
+							PackageName: "namespace1",
+							Annotations: []interface{}{FileIsGeneratedAnnotation(true)},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	ctrl.Subtest("Error").
+		Call(NewGeneratedFileCleaner().Clean, newStorage()).
+		ExpectPanic(
+			NewErrorMessageConstraint(
+				"Generated file '%s' was modified manually",
+				filepath.Join(fs.RootPath(), "modified.go"),
+			),
+		)
+
+	fs.AssertFileExists("modified.go")
+
+	writer := &bytes.Buffer{}
+	cleaner := NewGeneratedFileCleaner()
+	cleaner.SetModifiedFilePolicy(ModifiedFilePolicyWarning)
+	cleaner.SetLogger(NewTextLogger(writer))
+
+	storage := newStorage()
+
+	cleaner.Clean(storage)
+
+	ctrl.AssertSame(0, len(storage.Namespaces[0].Files))
+	ctrl.AssertSame(
+		"level=warning message=\"Generated file was modified manually, it's removed\" file="+
+			filepath.Join(fs.RootPath(), "modified.go")+"\n",
+		writer.String(),
+	)
+	ctrl.AssertSame(1, len(ReadManifest(fs.RootPath()).Files))
+	ctrl.AssertSame(manifest.Files["stale.go"], ReadManifest(fs.RootPath()).Files["stale.go"])
+	fs.AssertNotFileExists("modified.go")
+}
+
+func TestGeneratedFileCleaner_Clean_WithModifiedFileInLastNamespace(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fs := NewTmpFS(ctrl).
+		CreateDir("namespace1", 0777).
+		CreateFile("namespace1/generated.go", 0666, "package namespace1").
+		CreateDir("namespace2", 0777).
+		CreateFile("namespace2/modified.go", 0666, "package namespace2\n\nfunc Edited() {}")
+
+	manifest1 := NewManifest()
+	manifest1.Set("generated.go", "package namespace1")
+	manifest1.Write(filepath.Join(fs.RootPath(), "namespace1"))
+
+	manifest2 := NewManifest()
+	manifest2.Set("modified.go", "package namespace2")
+	manifest2.Write(filepath.Join(fs.RootPath(), "namespace2"))
+
+	storage := &Storage{
+		Namespaces: []*Namespace{
+			{
+				Name: "namespace1",
+				Path: filepath.Join(fs.RootPath(), "namespace1"),
+				Files: []*File{
+					{
+						Name:        "generated.go",
+						Content:     "package namespace1",
+						PackageName: "namespace1",
+						Annotations: []interface{}{FileIsGeneratedAnnotation(true)},
+					},
+				},
+			},
+			{
+				Name: "namespace2",
+				Path: filepath.Join(fs.RootPath(), "namespace2"),
+				Files: []*File{
+					{
+						Name:        "modified.go",
+						Content:     "package namespace2\n\nfunc Edited() {}",
+						PackageName: "namespace2",
+						Annotations: []interface{}{FileIsGeneratedAnnotation(true)},
+					},
+				},
+			},
+		},
+	}
+
+	ctrl.Subtest("").
+		Call(NewGeneratedFileCleaner().Clean, storage).
+		ExpectPanic(
+			NewErrorMessageConstraint(
+				"Generated file '%s' was modified manually",
+				filepath.Join(fs.RootPath(), "namespace2", "modified.go"),
+			),
+		)
+
+	fs.AssertFileExists("namespace1/generated.go")
+	fs.AssertFileExists("namespace2/modified.go")
+	ctrl.AssertSame(1, len(storage.Namespaces[0].Files))
+	ctrl.AssertSame(1, len(ReadManifest(filepath.Join(fs.RootPath(), "namespace1")).Files))
+}
+func TestGeneratedFileCleaner_SetModifiedFilePolicy_WithUnknownPolicy(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewGeneratedFileCleaner().SetModifiedFilePolicy, "unknown").
+		ExpectPanic(NewErrorMessageConstraint("Variable 'policy' has unknown value: 'unknown'"))
+}
+
+func TestGeneratedFileCleaner_Clean_WithGeneratedMarker(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ownedContent := "// Code generated by custom. DO NOT EDIT.\n\npackage namespace1"
+	foreignContent := "// Code generated by \"stringer\"; DO NOT EDIT.\n\npackage namespace1"
+
+	fs := NewTmpFS(ctrl).
+		CreateFile("owned.go", 0666, ownedContent).
+		CreateFile("stringer.go", 0666, foreignContent)
+
+	foreignFile := &File{Name: "stringer.go", Content: foreignContent, PackageName: "namespace1"}
+
+	storage := &Storage{
+		Namespaces: []*Namespace{
+			{
+				Name: "namespace1",
+				Path: fs.RootPath(),
+				Files: []*File{
+					{
+						Name:        "owned.go",
+						Content:     ownedContent,
+						PackageName: "namespace1",
+						Annotations: []interface{}{FileGeneratedByAnnotation{Name: "custom"}},
+					},
+					foreignFile,
+				},
+			},
+		},
+	}
+
+	NewGeneratedFileCleaner().Clean(storage)
+
+	ctrl.AssertEqual([]*File{foreignFile}, storage.Namespaces[0].Files)
+
+	fs.AssertNotFileExists("owned.go")
+	fs.AssertFileExists("stringer.go")
+}
+
+func TestGeneratedFileCleaner_Clean_WithGeneratedMarkerInManifest(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	listedContent := "// Code generated by custom. DO NOT EDIT.\n\npackage namespace1"
+	foreignContent := "// Code generated by \"stringer\"; DO NOT EDIT.\n\npackage namespace1"
+
+	fs := NewTmpFS(ctrl).
+		CreateFile("listed.go", 0666, listedContent).
+		CreateFile("stringer.go", 0666, foreignContent)
+
+	manifest := NewManifest()
+	manifest.Set("listed.go", listedContent)
+	manifest.Write(fs.RootPath())
+
+	foreignFile := &File{Name: "stringer.go", Content: foreignContent, PackageName: "namespace1"}
+
+	storage := &Storage{
+		Namespaces: []*Namespace{
+			{
+				Name: "namespace1",
+				Path: fs.RootPath(),
+				Files: []*File{
+					{Name: "listed.go", Content: listedContent, PackageName: "namespace1"},
+					foreignFile,
+				},
+			},
+		},
+	}
+
+	cleaner := NewGeneratedFileCleaner()
+
+	ctrl.AssertFalse(cleaner.IsGenerated(storage.Namespaces[0].Files[0]))
+
+	cleaner.Clean(storage)
+
+	ctrl.AssertEqual([]*File{foreignFile}, storage.Namespaces[0].Files)
+	ctrl.AssertSame(0, len(ReadManifest(fs.RootPath()).Files))
+
+	fs.AssertNotFileExists("listed.go")
+	fs.AssertFileExists("stringer.go")
+}
+
+func TestGeneratedFileCleaner_Clean_WithRegions(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := "package model\n\n" +
+		"type Color int\n\n" +
+		"// go-annotation:begin stringer:Color\n" +
+		"func (c Color) String() string {\n\treturn \"\"\n}\n" +
+		"// go-annotation:end stringer:Color\n\n" +
+		"// go-annotation:begin foreign\n" +
+		"func Foreign() {}\n" +
+		"// go-annotation:end foreign\n"
+
+	expectedContent := "package model\n\n" +
+		"type Color int\n\n" +
+		"// go-annotation:begin stringer:Color\n" +
+		"// go-annotation:end stringer:Color\n\n" +
+		"// go-annotation:begin foreign\n" +
+		"func Foreign() {}\n" +
+		"// go-annotation:end foreign\n"
+
+	sourceParser := NewGoSourceParser(NewJSONAnnotationParser())
+	file := sourceParser.Parse("model.go", content)
+	storage := &Storage{Namespaces: []*Namespace{{Name: "model", Path: "/model", Files: []*File{file}}}}
+
+	cleaner := NewGeneratedFileCleaner()
+	cleaner.SetOwners([]string{"stringer"})
+	cleaner.SetSourceParser(sourceParser)
+	cleaner.Clean(storage)
+
+	ctrl.AssertSame(file, storage.Namespaces[0].Files[0])
+	ctrl.AssertSame(expectedContent, file.Content)
+	ctrl.AssertSame(1, len(file.Funcs))
+	ctrl.AssertSame("Foreign", file.Funcs[0].Name)
+	ctrl.AssertNil(storage.FindDeclaration("model.Color.String"))
+}
+
+func TestGeneratedFileCleaner_Clean_WithInvalidRegions(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	storage := &Storage{
+		Namespaces: []*Namespace{
+			{
+				Name:  "model",
+				Path:  "/model",
+				Files: []*File{{Name: "model.go", Content: "package model\n\n// go-annotation:begin stringer\n"}},
+			},
+		},
+	}
+
+	ctrl.Subtest("").
+		Call(NewGeneratedFileCleaner().Clean, storage).
+		ExpectPanic(
+			NewErrorMessageConstraint("Can't clean regions of file '/model/model.go': Region 'stringer' is not closed"),
+		)
+}
@@ -0,0 +1,283 @@
+package annotation
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// Conflict of files with the same name is reported as error.
+	FileConflictPolicyError = "error"
+	// Declarations of generated files with the same name are merged into the first created file.
+	FileConflictPolicyMerge = "merge"
+)
+
+// FileConflict describes two files with the same name in one namespace, Conflicting file is created later.
+type FileConflict struct {
+	Namespace   *Namespace
+	File        *File
+	Conflicting *File
+	// Description of declaration, which is created by both files, e.g. "func Model.String".
+	// Such files aren't merged.
+	Declaration string
+}
+
+// Returns path of conflicting files.
+func (m *FileConflict) Path() string {
+	return filepath.Join(m.Namespace.Path, m.Conflicting.Name)
+}
+
+// Returns message with path and names of generators, which created files.
+func (m *FileConflict) String() string {
+	if m.Declaration != "" {
+		return fmt.Sprintf(
+			"Declaration '%s' of file '%s' is created by generators '%s' and '%s'",
+			m.Declaration,
+			m.Path(),
+			fileOwnerName(m.File),
+			fileOwnerName(m.Conflicting),
+		)
+	}
+
+	if m.File.Content != "" {
+		return fmt.Sprintf(
+			"File '%s' is created by generator '%s', but it already exists",
+			m.Path(),
+			fileOwnerName(m.Conflicting),
+		)
+	}
+
+	return fmt.Sprintf(
+		"File '%s' is created by generators '%s' and '%s'",
+		m.Path(),
+		fileOwnerName(m.File),
+		fileOwnerName(m.Conflicting),
+	)
+}
+
+type GeneratedFileConflictResolver struct {
+	importRenamer ImportRenamer
+	policy        string
+}
+
+func NewGeneratedFileConflictResolver(importRenamer ImportRenamer) *GeneratedFileConflictResolver {
+	if importRenamer == nil {
+		panic(errors.New("Variable 'importRenamer' must be not nil"))
+	}
+
+	return &GeneratedFileConflictResolver{importRenamer: importRenamer, policy: FileConflictPolicyError}
+}
+
+func (r *GeneratedFileConflictResolver) Policy() string {
+	return r.policy
+}
+
+func (r *GeneratedFileConflictResolver) SetPolicy(policy string) {
+	if policy != FileConflictPolicyError && policy != FileConflictPolicyMerge {
+		panic(errors.Errorf("Variable 'policy' has unknown value: '%s'", policy))
+	}
+
+	r.policy = policy
+}
+
+// Searches files with the same name in each namespace. If policy is FileConflictPolicyMerge, new files are merged
+// and removed from storage, otherwise conflicts are returned. Conflicts with hand-written files and files with the
+// same declarations are always returned.
+func (r *GeneratedFileConflictResolver) Resolve(storage *Storage) []*FileConflict {
+	result := []*FileConflict{}
+	mergedFiles := []*File{}
+
+	// Generators could add files into namespaces directly
+	storage.Reindex()
+
+	for _, namespace := range storage.Namespaces {
+		if namespace.IsIgnored || namespace.IsReadOnly {
+			continue
+		}
+
+		byName := map[string]*File{}
+
+		for _, file := range namespace.Files {
+			existing, ok := byName[file.Name]
+
+			if !ok {
+				byName[file.Name] = file
+
+				continue
+			}
+
+			conflict := &FileConflict{Namespace: namespace, File: existing, Conflicting: file}
+
+			if r.policy == FileConflictPolicyMerge && existing.Content == "" && file.Content == "" {
+				conflict.Declaration = r.duplicateDeclaration(existing, file)
+
+				if conflict.Declaration == "" {
+					r.merge(existing, file)
+					mergedFiles = append(mergedFiles, file)
+
+					continue
+				}
+			}
+
+			result = append(result, conflict)
+		}
+	}
+
+	for _, file := range mergedFiles {
+		storage.RemoveFile(file)
+	}
+
+	if len(mergedFiles) > 0 {
+		storage.Reindex()
+	}
+
+	return result
+}
+
+// Returns description of the first declaration, which is created by both files, or empty string.
+func (r *GeneratedFileConflictResolver) duplicateDeclaration(target *File, file *File) string {
+	declarations := map[string]bool{}
+
+	for _, entity := range r.declarations(target) {
+		for _, declaration := range describeDeclaration(entity) {
+			declarations[declaration] = true
+		}
+	}
+
+	for _, entity := range r.declarations(file) {
+		for _, declaration := range describeDeclaration(entity) {
+			if declarations[declaration] {
+				return declaration
+			}
+		}
+	}
+
+	return ""
+}
+
+func (r *GeneratedFileConflictResolver) declarations(file *File) []interface{} {
+	result := []interface{}{}
+
+	for _, element := range file.ConstGroups {
+		result = append(result, element)
+	}
+
+	for _, element := range file.VarGroups {
+		result = append(result, element)
+	}
+
+	for _, element := range file.TypeGroups {
+		result = append(result, element)
+	}
+
+	for _, element := range file.Funcs {
+		result = append(result, element)
+	}
+
+	return result
+}
+
+// Appends declarations and owners of file into target. Imports are added only once, imports of file, which aliases
+// are used by target, are renamed.
+func (r *GeneratedFileConflictResolver) merge(target *File, file *File) {
+	imports := []*Import{}
+
+	for _, group := range target.ImportGroups {
+		imports = append(imports, group.Imports...)
+	}
+
+	aliases := map[*Import]string{}
+
+	// Aliases are replaced by temporary ones, so renaming of one import doesn't affect others
+	for _, group := range file.ImportGroups {
+		for _, element := range group.Imports {
+			if element.RealAlias() == "_" || element.RealAlias() == "." {
+				continue
+			}
+
+			aliases[element] = element.Alias
+			alias := "_import" + strconv.Itoa(len(aliases))
+
+			r.importRenamer.Rename(file, element.RealAlias(), alias)
+		}
+	}
+
+	for _, group := range file.ImportGroups {
+		newGroup := &ImportGroup{Comment: group.Comment}
+
+		for _, element := range group.Imports {
+			existing := r.findImport(imports, element)
+
+			if alias, ok := aliases[element]; ok {
+				newAlias := alias
+
+				if newAlias == "" {
+					newAlias = filepath.Base(element.Namespace)
+				}
+
+				if existing != nil {
+					newAlias = existing.RealAlias()
+				} else {
+					newAlias = uniqueAlias(imports, newAlias)
+				}
+
+				r.importRenamer.Rename(file, element.Alias, newAlias)
+
+				if alias == "" && newAlias == filepath.Base(element.Namespace) {
+					element.Alias = ""
+				}
+			}
+
+			if existing == nil {
+				imports = append(imports, element)
+				newGroup.Imports = append(newGroup.Imports, element)
+			}
+		}
+
+		if len(newGroup.Imports) > 0 {
+			target.ImportGroups = append(target.ImportGroups, newGroup)
+		}
+	}
+
+	for _, rawAnnotation := range file.Annotations {
+		if annotation, ok := rawAnnotation.(FileGeneratedByAnnotation); ok && !r.hasOwner(target, annotation.Name) {
+			target.Annotations = append(target.Annotations, annotation)
+		}
+	}
+
+	target.ConstGroups = append(target.ConstGroups, file.ConstGroups...)
+	target.VarGroups = append(target.VarGroups, file.VarGroups...)
+	target.TypeGroups = append(target.TypeGroups, file.TypeGroups...)
+	target.Funcs = append(target.Funcs, file.Funcs...)
+}
+
+// Returns import of the same namespace, blank and dot imports are matched by alias too.
+func (r *GeneratedFileConflictResolver) findImport(imports []*Import, element *Import) *Import {
+	for _, existing := range imports {
+		if existing.Namespace != element.Namespace {
+			continue
+		}
+
+		isSpecial := existing.Alias == "_" || existing.Alias == "."
+
+		if isSpecial == (element.Alias == "_" || element.Alias == ".") &&
+			(!isSpecial || existing.Alias == element.Alias) {
+			return existing
+		}
+	}
+
+	return nil
+}
+
+func (r *GeneratedFileConflictResolver) hasOwner(file *File, name string) bool {
+	for _, rawAnnotation := range file.Annotations {
+		if annotation, ok := rawAnnotation.(FileGeneratedByAnnotation); ok && annotation.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,187 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestNewGeneratedFileConflictResolver(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	actual := NewGeneratedFileConflictResolver(NewEntityImportRenamer())
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(FileConflictPolicyError, actual.Policy())
+}
+
+func TestNewGeneratedFileConflictResolver_WithNilImportRenamer(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewGeneratedFileConflictResolver, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'importRenamer' must be not nil"))
+}
+
+func TestGeneratedFileConflictResolver_SetPolicy(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	resolver := NewGeneratedFileConflictResolver(NewEntityImportRenamer())
+	resolver.SetPolicy(FileConflictPolicyMerge)
+
+	ctrl.AssertSame(FileConflictPolicyMerge, resolver.Policy())
+
+	ctrl.Subtest("").
+		Call(resolver.SetPolicy, "unknown").
+		ExpectPanic(NewErrorMessageConstraint("Variable 'policy' has unknown value: 'unknown'"))
+}
+
+func TestGeneratedFileConflictResolver_Resolve(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	manualFile := &File{Name: "manual.go", Content: "package model"}
+	generatedManualFile := &File{
+		Name:        "manual.go",
+		Annotations: []interface{}{FileGeneratedByAnnotation{Name: "mock"}},
+	}
+	firstFile := &File{
+		Name:        "model.go",
+		Annotations: []interface{}{FileGeneratedByAnnotation{Name: "first"}},
+	}
+	secondFile := &File{
+		Name:        "model.go",
+		Annotations: []interface{}{FileGeneratedByAnnotation{Name: "second"}},
+	}
+	namespace := &Namespace{
+		Name:  "model",
+		Path:  "/model",
+		Files: []*File{manualFile, firstFile, generatedManualFile, secondFile},
+	}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+
+	actual := NewGeneratedFileConflictResolver(NewEntityImportRenamer()).Resolve(storage)
+
+	ctrl.AssertSame(2, len(actual))
+	ctrl.AssertSame("File '/model/manual.go' is created by generator 'mock', but it already exists", actual[0].String())
+	ctrl.AssertSame(manualFile, actual[0].File)
+	ctrl.AssertSame(generatedManualFile, actual[0].Conflicting)
+	ctrl.AssertSame("File '/model/model.go' is created by generators 'first' and 'second'", actual[1].String())
+	ctrl.AssertSame(firstFile, actual[1].File)
+	ctrl.AssertSame(secondFile, actual[1].Conflicting)
+	ctrl.AssertSame(4, len(namespace.Files))
+}
+
+func TestGeneratedFileConflictResolver_Resolve_WithMergePolicy(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	firstFile := &File{
+		Name:        "model.go",
+		Annotations: []interface{}{FileGeneratedByAnnotation{Name: "first"}},
+		ImportGroups: []*ImportGroup{
+			{Imports: []*Import{{Namespace: "fmt"}, {Namespace: "example.com/first/model"}}},
+		},
+		TypeGroups: []*TypeGroup{
+			{Types: []*Type{{Name: "First", Spec: &SimpleSpec{PackageName: "model", TypeName: "First"}}}},
+		},
+	}
+	secondFile := &File{
+		Name:        "model.go",
+		Annotations: []interface{}{FileGeneratedByAnnotation{Name: "second"}},
+		ImportGroups: []*ImportGroup{
+			{Imports: []*Import{{Namespace: "fmt"}, {Namespace: "strings"}}},
+			{Imports: []*Import{{Alias: "format", Namespace: "fmt"}, {Namespace: "example.com/second/model"}}},
+		},
+		ConstGroups: []*ConstGroup{
+			{Consts: []*Const{{Name: "Second", Value: "1"}}},
+		},
+		VarGroups: []*VarGroup{
+			{Vars: []*Var{{Name: "second", Value: "format.Sprint(model.Second{})"}}},
+		},
+		TypeGroups: []*TypeGroup{
+			{Types: []*Type{{Name: "Second", Spec: &SimpleSpec{PackageName: "model", TypeName: "Second"}}}},
+		},
+		Funcs: []*Func{
+			{Name: "NewSecond", Spec: &FuncSpec{}, Content: "return fmt.Sprint(strings.Title(\"\"))"},
+		},
+	}
+	namespace := &Namespace{Name: "model", Path: "/model", Files: []*File{firstFile, secondFile}}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+
+	expected := &File{
+		Name: "model.go",
+		Annotations: []interface{}{
+			FileGeneratedByAnnotation{Name: "first"},
+			FileGeneratedByAnnotation{Name: "second"},
+		},
+		ImportGroups: []*ImportGroup{
+			{Imports: []*Import{{Namespace: "fmt"}, {Namespace: "example.com/first/model"}}},
+			{Imports: []*Import{{Namespace: "strings"}}},
+			{Imports: []*Import{{Alias: "model_2", Namespace: "example.com/second/model"}}},
+		},
+		ConstGroups: []*ConstGroup{
+			{Consts: []*Const{{Name: "Second", Value: "1"}}},
+		},
+		VarGroups: []*VarGroup{
+			{Vars: []*Var{{Name: "second", Value: "fmt.Sprint(model_2.Second{})"}}},
+		},
+		TypeGroups: []*TypeGroup{
+			{Types: []*Type{{Name: "First", Spec: &SimpleSpec{PackageName: "model", TypeName: "First"}}}},
+			{Types: []*Type{{Name: "Second", Spec: &SimpleSpec{PackageName: "model_2", TypeName: "Second"}}}},
+		},
+		Funcs: []*Func{
+			{Name: "NewSecond", Spec: &FuncSpec{}, Content: "return fmt.Sprint(strings.Title(\"\"))"},
+		},
+	}
+
+	resolver := NewGeneratedFileConflictResolver(NewEntityImportRenamer())
+	resolver.SetPolicy(FileConflictPolicyMerge)
+
+	actual := resolver.Resolve(storage)
+
+	ctrl.AssertSame(0, len(actual))
+	ctrl.AssertEqual([]*File{expected}, namespace.Files)
+	ctrl.AssertNotNil(storage.FindDeclaration("model.Second"))
+}
+
+func TestGeneratedFileConflictResolver_Resolve_WithMergePolicyAndDuplicateDeclaration(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	firstFile := &File{
+		Name:        "model.go",
+		Annotations: []interface{}{FileGeneratedByAnnotation{Name: "first"}},
+		Funcs: []*Func{
+			{Name: "String", Spec: &FuncSpec{}, Related: &Field{Name: "m", Spec: &SimpleSpec{TypeName: "Model"}}},
+		},
+	}
+	secondFile := &File{
+		Name:        "model.go",
+		Annotations: []interface{}{FileGeneratedByAnnotation{Name: "second"}},
+		Funcs: []*Func{
+			{Name: "String", Spec: &FuncSpec{}},
+			{Name: "String", Spec: &FuncSpec{}, Related: &Field{Name: "m", Spec: &SimpleSpec{TypeName: "Model"}}},
+		},
+	}
+	namespace := &Namespace{Name: "model", Path: "/model", Files: []*File{firstFile, secondFile}}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+
+	resolver := NewGeneratedFileConflictResolver(NewEntityImportRenamer())
+	resolver.SetPolicy(FileConflictPolicyMerge)
+
+	actual := resolver.Resolve(storage)
+
+	ctrl.AssertSame(1, len(actual))
+	ctrl.AssertSame(
+		"Declaration 'func Model.String' of file '/model/model.go' is created by generators 'first' and 'second'",
+		actual[0].String(),
+	)
+	ctrl.AssertSame(firstFile, actual[0].File)
+	ctrl.AssertSame(secondFile, actual[0].Conflicting)
+	ctrl.AssertSame(1, len(firstFile.Funcs))
+	ctrl.AssertEqual([]*File{firstFile, secondFile}, namespace.Files)
+}
@@ -1,22 +1,36 @@
 package annotation
 
 import (
+	"encoding/json"
 	"github.com/pkg/errors"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
+// Standard marker of generated go files, it's recognized by go vet, linters and code review tools.
+const GeneratedMarker = "// Code generated by github.com/index0h/go-annotation. DO NOT EDIT.\n"
+
 // Default prefix for file rendering.
 // Annotation FileIsGenerated is used internally to check, that file could be overwritten.
-const Header = "" +
-	"// Generated by github.com/index0h/go-annotation\n" +
-	"// DO NOT EDIT\n" +
+const Header = GeneratedMarker +
 	"// @FileIsGenerated(true)\n"
 
+// Callback, which could modify rendered content of generated file before it's written.
+type BeforeWriteFunc func(namespace *Namespace, file *File, content string) string
+
 type GeneratedFileWriter struct {
-	validator Validator
-	renderer  Renderer
+	validator          Validator
+	renderer           Renderer
+	beforeWrite        BeforeWriteFunc
+	regions            *Regions
+	editor             FileEditor
+	changeSet          *ChangeSet
+	owners             map[string]bool
+	headers            map[string]string
+	modifiedFilePolicy string
+	logger             Logger
 }
 
 func NewGeneratedFileWriter(validator Validator, renderer Renderer) *GeneratedFileWriter {
@@ -28,23 +42,90 @@ func NewGeneratedFileWriter(validator Validator, renderer Renderer) *GeneratedFi
 		panic(errors.New("Variable 'renderer' must be not nil"))
 	}
 
-	return &GeneratedFileWriter{validator: validator, renderer: renderer}
+	return &GeneratedFileWriter{validator: validator, renderer: renderer, modifiedFilePolicy: ModifiedFilePolicyError}
 }
 
-// Renders and writes File models without content.
+// Sets behavior for overwritten generated files, which content doesn't match hash from Manifest.
+func (w *GeneratedFileWriter) SetModifiedFilePolicy(policy string) {
+	if policy != ModifiedFilePolicyError && policy != ModifiedFilePolicyWarning {
+		panic(errors.Errorf("Variable 'policy' has unknown value: '%s'", policy))
+	}
+
+	w.modifiedFilePolicy = policy
+}
+
+// Sets logger for warnings about modified generated files.
+func (w *GeneratedFileWriter) SetLogger(logger Logger) {
+	if logger == nil {
+		panic(errors.New("Variable 'logger' must be not nil"))
+	}
+
+	w.logger = logger
+}
+
+// Sets callback, which is called for each rendered file before it's written.
+func (w *GeneratedFileWriter) SetBeforeWrite(beforeWrite BeforeWriteFunc) {
+	w.beforeWrite = beforeWrite
+}
+
+// Sets names of generators, which files could be overwritten. If names are nil, existing files are never overwritten.
+// Files without FileGeneratedByAnnotation are treated as owned by any generator.
+func (w *GeneratedFileWriter) SetOwners(names []string) {
+	if names == nil {
+		w.owners = nil
+
+		return
+	}
+
+	w.owners = map[string]bool{}
+
+	for _, name := range names {
+		w.owners[name] = true
+	}
+}
+
+// Sets headers by generator name, they are used instead of Header for files owned by these generators.
+// GeneratedMarker is prepended to header, which doesn't contain standard marker of generated go files.
+func (w *GeneratedFileWriter) SetHeaders(headers map[string]string) {
+	w.headers = headers
+}
+
+// Sets regions, which are filled into hand-written files.
+func (w *GeneratedFileWriter) SetRegions(regions *Regions) {
+	w.regions = regions
+}
+
+// Sets editor of hand-written files, their declarations aren't written if editor is nil.
+func (w *GeneratedFileWriter) SetEditor(editor FileEditor) {
+	w.editor = editor
+}
+
+// Sets change set, which is filled by written files.
+func (w *GeneratedFileWriter) SetChangeSet(changeSet *ChangeSet) {
+	w.changeSet = changeSet
+}
+
+// Renders and writes File models without content, hashes of written files are stored into Manifest of namespace.
+// Hand-written files are written only if their regions or declarations, when editor is set, are changed.
 func (w *GeneratedFileWriter) Write(storage *Storage) {
 	if err := w.validator.Validate(storage); err != nil {
 		panic(err)
 	}
 
 	for _, namespace := range storage.Namespaces {
-		if namespace.IsIgnored {
+		if namespace.IsIgnored || namespace.IsReadOnly {
 			continue
 		}
 
+		var manifest *Manifest
+
 		for _, file := range namespace.Files {
 			if file.Content == "" {
-				file.Content = Header + w.renderer.Render(file)
+				file.Content = w.header(file) + w.renderer.Render(file)
+
+				if w.beforeWrite != nil {
+					file.Content = w.beforeWrite(namespace, file, file.Content)
+				}
 
 				if err := os.MkdirAll(namespace.Path, os.ModePerm); err != nil {
 					panic(err)
@@ -52,14 +133,236 @@ func (w *GeneratedFileWriter) Write(storage *Storage) {
 
 				filePath := filepath.Join(namespace.Path, file.Name)
 
+				if manifest == nil {
+					manifest = ReadManifest(namespace.Path)
+				}
+
 				if _, err := os.Stat(filePath); !os.IsNotExist(err) {
-					panic(errors.Errorf("File '%s' already exists", filePath))
+					w.checkOverwrite(filePath, file, manifest)
 				}
 
 				if err := ioutil.WriteFile(filePath, []byte(file.Content), 0666); err != nil {
 					panic(err)
 				}
+
+				manifest.Set(file.Name, file.Content)
+				w.addChange(&FileChange{Kind: FileChangeKindCreate, Path: filePath})
+			} else {
+				w.writeHandWritten(namespace, file)
+			}
+		}
+
+		if manifest != nil {
+			manifest.Write(namespace.Path)
+		}
+	}
+}
+
+// Edits declarations of hand-written file, if editor is set, fills its regions and writes file, if its content is
+// changed. Other content is kept byte-for-byte.
+func (w *GeneratedFileWriter) writeHandWritten(namespace *Namespace, file *File) {
+	hasRegions := strings.Contains(file.Content, RegionBeginMarker)
+
+	if w.editor == nil && !hasRegions {
+		return
+	}
+
+	filePath := filepath.Join(namespace.Path, file.Name)
+	content := file.Content
+	declarations := []string{}
+
+	if w.editor != nil {
+		content, declarations = w.editor.Edit(file)
+	}
+
+	if hasRegions {
+		var err error
+
+		content, err = replaceRegions(content, func(name string) (string, bool) {
+			if w.regions == nil {
+				return "", false
+			}
+
+			if region := w.regions.Find(file, name); region != nil {
+				return region.Content, true
+			}
+
+			return "", false
+		})
+
+		if err != nil {
+			panic(errors.Wrapf(err, "Can't fill regions of file '%s'", filePath))
+		}
+	}
+
+	file.Content = content
+
+	if err := w.validator.Validate(file); err != nil {
+		panic(errors.Wrapf(err, "Can't write file '%s'", filePath))
+	}
+
+	current, err := ioutil.ReadFile(filePath)
+
+	if err != nil {
+		panic(err)
+	}
+
+	if string(current) == content {
+		return
+	}
+
+	if err := ioutil.WriteFile(filePath, []byte(content), 0666); err != nil {
+		panic(err)
+	}
+
+	if len(declarations) > 0 {
+		w.addChange(&FileChange{Kind: FileChangeKindEdit, Path: filePath, Declarations: declarations})
+	} else {
+		w.addChange(&FileChange{Kind: FileChangeKindRegions, Path: filePath})
+	}
+}
+
+func (w *GeneratedFileWriter) addChange(change *FileChange) {
+	if w.changeSet != nil {
+		w.changeSet.Add(change)
+	}
+}
+
+// Returns header of the first owner or Header with owners of file, if file has FileGeneratedByAnnotation.
+// Merged file has annotation of each generator, which created it.
+func (w *GeneratedFileWriter) header(file *File) string {
+	result := ""
+
+	for _, rawAnnotation := range file.Annotations {
+		if annotation, ok := rawAnnotation.(FileGeneratedByAnnotation); ok {
+			content, err := json.Marshal(annotation)
+
+			if err != nil {
+				panic(err)
+			}
+
+			if result == "" {
+				header, ok := w.headers[annotation.Name]
+
+				if !ok {
+					header = Header
+				}
+
+				if header != "" && !strings.HasSuffix(header, "\n") {
+					header += "\n"
+				}
+
+				if !w.hasGeneratedMarker(header) {
+					header = GeneratedMarker + header
+				}
+
+				result = header
 			}
+
+			result += "// @FileGeneratedBy(" + string(content) + ")\n"
+		}
+	}
+
+	if result == "" {
+		return Header
+	}
+
+	return result
+}
+
+// Checks that header contains standard marker of generated go files.
+func (w *GeneratedFileWriter) hasGeneratedMarker(header string) bool {
+	for _, line := range strings.Split(header, "\n") {
+		if generatedMarkerRegexp.MatchString(strings.TrimSpace(line)) {
+			return true
 		}
 	}
+
+	return false
+}
+
+// Panics if existing file is not generated or it's owned by any generator, which doesn't participate in run.
+// File with only standard generated marker is treated as generated, if it's listed in manifest.
+// Generated file, which content doesn't match hash from manifest, is reported by modified file policy.
+func (w *GeneratedFileWriter) checkOverwrite(filePath string, file *File, manifest *Manifest) {
+	if w.owners == nil {
+		w.panicExists(filePath, file)
+	}
+
+	content, err := ioutil.ReadFile(filePath)
+
+	if err != nil {
+		panic(err)
+	}
+
+	isGenerated := false
+	hasMarker := false
+	owners := []string{}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+
+		hasMarker = hasMarker || generatedMarkerRegexp.MatchString(line)
+		line = strings.TrimSpace(strings.TrimPrefix(line, "//"))
+
+		switch {
+		case line == "@FileIsGenerated(true)":
+			isGenerated = true
+		case strings.HasPrefix(line, "@FileGeneratedBy(") && strings.HasSuffix(line, ")"):
+			annotation := FileGeneratedByAnnotation{}
+			rawAnnotation := strings.TrimSuffix(strings.TrimPrefix(line, "@FileGeneratedBy("), ")")
+
+			if err := json.Unmarshal([]byte(rawAnnotation), &annotation); err == nil {
+				owners = append(owners, annotation.Name)
+			}
+		}
+	}
+
+	if !isGenerated && (!hasMarker || (len(owners) == 0 && !manifest.Contains(file.Name))) {
+		w.panicExists(filePath, file)
+	}
+
+	for _, owner := range owners {
+		if !w.owners[owner] {
+			panic(
+				errors.Errorf(
+					"File '%s' is owned by generator '%s', which doesn't participate in generation",
+					filePath,
+					owner,
+				),
+			)
+		}
+	}
+
+	if manifest.IsModified(file.Name, string(content)) {
+		w.reportModified(filePath)
+	}
+}
+
+func (w *GeneratedFileWriter) reportModified(path string) {
+	if w.modifiedFilePolicy == ModifiedFilePolicyWarning {
+		if w.logger != nil {
+			w.logger.Warning("Generated file was modified manually, it's overwritten", "file", path)
+		}
+
+		return
+	}
+
+	panic(errors.Errorf("Generated file '%s' was modified manually", path))
+}
+
+func (w *GeneratedFileWriter) panicExists(filePath string, file *File) {
+	if owner := fileOwnerName(file); owner != "unknown" {
+		panic(errors.Errorf("File '%s' is created by generator '%s', but it already exists", filePath, owner))
+	}
+
+	panic(errors.Errorf("File '%s' already exists", filePath))
 }
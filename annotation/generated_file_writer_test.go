@@ -1,6 +1,7 @@
 package annotation
 
 import (
+	"bytes"
 	"github.com/pkg/errors"
 	"os"
 	"path/filepath"
@@ -126,6 +127,532 @@ func TestGeneratedFileWriter_Write(t *testing.T) {
 	fs.AssertFileContent("root/folder1/folder2/folder3/second_file.go", storage.Namespaces[1].Files[0].Content)
 }
 
+func TestGeneratedFileWriter_Write_WithBeforeWrite(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fs := NewTmpFS(ctrl).CreateDir("root", 0777)
+
+	file := &File{Name: "file.go", PackageName: "namespace"}
+	namespace := &Namespace{Name: "namespace", Path: filepath.Join(fs.RootPath(), "root"), Files: []*File{file}}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+
+	validator := NewValidatorMock(ctrl)
+	renderer := NewRendererMock(ctrl)
+
+	generatedFileWriter := NewGeneratedFileWriter(validator, renderer)
+	generatedFileWriter.SetBeforeWrite(func(actualNamespace *Namespace, actualFile *File, content string) string {
+		ctrl.AssertSame(namespace, actualNamespace)
+		ctrl.AssertSame(file, actualFile)
+		ctrl.AssertSame(Header+"// content", content)
+
+		return "// License\n" + content
+	})
+
+	validator.
+		EXPECT().
+		Validate(storage).
+		Return(nil)
+
+	renderer.
+		EXPECT().
+		Render(file).
+		Return("// content")
+
+	generatedFileWriter.Write(storage)
+
+	ctrl.AssertSame("// License\n"+Header+"// content", file.Content)
+	fs.AssertFileContent("root/file.go", file.Content)
+}
+
+func TestGeneratedFileWriter_Write_WithOwners(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fs := NewTmpFS(ctrl).
+		CreateDir("root", 0777).
+		CreateFile("root/file.go", 0666, Header+"// @FileGeneratedBy({\"name\":\"owner\"})\npackage namespace")
+
+	file := &File{
+		Name:        "file.go",
+		PackageName: "namespace",
+		Annotations: []interface{}{FileGeneratedByAnnotation{Name: "owner", Version: "1.0"}},
+	}
+	namespace := &Namespace{Name: "namespace", Path: filepath.Join(fs.RootPath(), "root"), Files: []*File{file}}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+
+	validator := NewValidatorMock(ctrl)
+	renderer := NewRendererMock(ctrl)
+
+	validator.
+		EXPECT().
+		Validate(storage).
+		Return(nil)
+
+	renderer.
+		EXPECT().
+		Render(file).
+		Return("// content")
+
+	generatedFileWriter := NewGeneratedFileWriter(validator, renderer)
+	generatedFileWriter.SetOwners([]string{"owner"})
+	generatedFileWriter.Write(storage)
+
+	ctrl.AssertSame(Header+"// @FileGeneratedBy({\"name\":\"owner\",\"version\":\"1.0\"})\n// content", file.Content)
+	fs.AssertFileContent("root/file.go", file.Content)
+	ctrl.AssertFalse(ReadManifest(namespace.Path).IsModified("file.go", file.Content))
+	ctrl.AssertTrue(ReadManifest(namespace.Path).IsModified("file.go", "package namespace"))
+}
+
+func TestGeneratedFileWriter_Write_WithModifiedFile(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := Header + "// @FileGeneratedBy({\"name\":\"owner\"})\npackage namespace"
+
+	fs := NewTmpFS(ctrl).
+		CreateDir("root", 0777).
+		CreateFile("root/file.go", 0666, content+"\n\n// manual change")
+
+	manifest := NewManifest()
+	manifest.Set("file.go", content)
+	manifest.Write(filepath.Join(fs.RootPath(), "root"))
+
+	file := &File{
+		Name:        "file.go",
+		PackageName: "namespace",
+		Annotations: []interface{}{FileGeneratedByAnnotation{Name: "owner"}},
+	}
+	namespace := &Namespace{Name: "namespace", Path: filepath.Join(fs.RootPath(), "root"), Files: []*File{file}}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+
+	validator := NewValidatorMock(ctrl)
+	renderer := NewRendererMock(ctrl)
+
+	for i := 0; i < 2; i++ {
+		validator.
+			EXPECT().
+			Validate(storage).
+			Return(nil)
+
+		renderer.
+			EXPECT().
+			Render(file).
+			Return("// content")
+	}
+
+	generatedFileWriter := NewGeneratedFileWriter(validator, renderer)
+	generatedFileWriter.SetOwners([]string{"owner"})
+
+	ctrl.Subtest("").
+		Call(generatedFileWriter.Write, storage).
+		ExpectPanic(
+			NewErrorMessageConstraint(
+				"Generated file '%s' was modified manually",
+				filepath.Join(namespace.Path, "file.go"),
+			),
+		)
+
+	fs.AssertFileContent("root/file.go", content+"\n\n// manual change")
+
+	writer := &bytes.Buffer{}
+	file.Content = ""
+
+	generatedFileWriter.SetModifiedFilePolicy(ModifiedFilePolicyWarning)
+	generatedFileWriter.SetLogger(NewTextLogger(writer))
+	generatedFileWriter.Write(storage)
+
+	ctrl.AssertSame(
+		"level=warning message=\"Generated file was modified manually, it's overwritten\" file="+
+			filepath.Join(namespace.Path, "file.go")+"\n",
+		writer.String(),
+	)
+	fs.AssertFileContent("root/file.go", file.Content)
+	ctrl.AssertFalse(ReadManifest(namespace.Path).IsModified("file.go", file.Content))
+}
+
+func TestGeneratedFileWriter_SetModifiedFilePolicy_WithUnknownPolicy(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	generatedFileWriter := NewGeneratedFileWriter(NewValidatorMock(ctrl), NewRendererMock(ctrl))
+
+	ctrl.Subtest("").
+		Call(generatedFileWriter.SetModifiedFilePolicy, "unknown").
+		ExpectPanic(NewErrorMessageConstraint("Variable 'policy' has unknown value: 'unknown'"))
+}
+
+func TestGeneratedFileWriter_Write_WithGeneratedMarkerInManifest(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := "// Code generated by custom. DO NOT EDIT.\n\npackage namespace"
+
+	fs := NewTmpFS(ctrl).
+		CreateDir("root", 0777).
+		CreateFile("root/file.go", 0666, content)
+
+	manifest := NewManifest()
+	manifest.Set("file.go", content)
+	manifest.Write(filepath.Join(fs.RootPath(), "root"))
+
+	file := &File{Name: "file.go", PackageName: "namespace"}
+	namespace := &Namespace{Name: "namespace", Path: filepath.Join(fs.RootPath(), "root"), Files: []*File{file}}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+
+	validator := NewValidatorMock(ctrl)
+	renderer := NewRendererMock(ctrl)
+
+	validator.
+		EXPECT().
+		Validate(storage).
+		Return(nil)
+
+	renderer.
+		EXPECT().
+		Render(file).
+		Return("// content")
+
+	generatedFileWriter := NewGeneratedFileWriter(validator, renderer)
+	generatedFileWriter.SetOwners([]string{"owner"})
+	generatedFileWriter.Write(storage)
+
+	ctrl.AssertSame(Header+"// content", file.Content)
+	fs.AssertFileContent("root/file.go", file.Content)
+}
+
+func TestGeneratedFileWriter_Write_WithHeaders(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fs := NewTmpFS(ctrl).
+		CreateDir("root", 0777).
+		CreateFile(
+			"root/custom.go",
+			0666,
+			"// Code generated by custom. DO NOT EDIT.\n\n"+
+				"// @FileGeneratedBy({\"name\":\"custom\"})\n"+
+				"package namespace",
+		)
+
+	customFile := &File{
+		Name:        "custom.go",
+		PackageName: "namespace",
+		Annotations: []interface{}{FileGeneratedByAnnotation{Name: "custom"}},
+	}
+	defaultFile := &File{
+		Name:        "default.go",
+		PackageName: "namespace",
+		Annotations: []interface{}{FileGeneratedByAnnotation{Name: "default"}},
+	}
+	namespace := &Namespace{
+		Name:  "namespace",
+		Path:  filepath.Join(fs.RootPath(), "root"),
+		Files: []*File{customFile, defaultFile},
+	}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+
+	validator := NewValidatorMock(ctrl)
+	renderer := NewRendererMock(ctrl)
+
+	validator.
+		EXPECT().
+		Validate(storage).
+		Return(nil)
+
+	renderer.
+		EXPECT().
+		Render(customFile).
+		Return("// content")
+
+	renderer.
+		EXPECT().
+		Render(defaultFile).
+		Return("// content")
+
+	generatedFileWriter := NewGeneratedFileWriter(validator, renderer)
+	generatedFileWriter.SetOwners([]string{"custom", "default"})
+	generatedFileWriter.SetHeaders(map[string]string{"custom": "// Code generated by custom. DO NOT EDIT."})
+	generatedFileWriter.Write(storage)
+
+	ctrl.AssertSame(
+		"// Code generated by custom. DO NOT EDIT.\n// @FileGeneratedBy({\"name\":\"custom\"})\n// content",
+		customFile.Content,
+	)
+	ctrl.AssertSame(
+		"// Code generated by github.com/index0h/go-annotation. DO NOT EDIT.\n"+
+			"// @FileIsGenerated(true)\n"+
+			"// @FileGeneratedBy({\"name\":\"default\"})\n"+
+			"// content",
+		defaultFile.Content,
+	)
+	fs.AssertFileContent("root/custom.go", customFile.Content)
+	fs.AssertFileContent("root/default.go", defaultFile.Content)
+}
+
+func TestGeneratedFileWriter_Write_WithSeveralOwners(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fs := NewTmpFS(ctrl).
+		CreateDir("root", 0777).
+		CreateFile(
+			"root/file.go",
+			0666,
+			Header+
+				"// @FileGeneratedBy({\"name\":\"first\"})\n"+
+				"// @FileGeneratedBy({\"name\":\"second\"})\n"+
+				"package namespace",
+		)
+
+	file := &File{
+		Name:        "file.go",
+		PackageName: "namespace",
+		Annotations: []interface{}{
+			FileGeneratedByAnnotation{Name: "first"},
+			FileGeneratedByAnnotation{Name: "second"},
+		},
+	}
+	namespace := &Namespace{Name: "namespace", Path: filepath.Join(fs.RootPath(), "root"), Files: []*File{file}}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+
+	validator := NewValidatorMock(ctrl)
+	renderer := NewRendererMock(ctrl)
+
+	for i := 0; i < 2; i++ {
+		validator.
+			EXPECT().
+			Validate(storage).
+			Return(nil)
+
+		renderer.
+			EXPECT().
+			Render(file).
+			Return("// content")
+	}
+
+	generatedFileWriter := NewGeneratedFileWriter(validator, renderer)
+	generatedFileWriter.SetOwners([]string{"first"})
+
+	ctrl.Subtest("").
+		Call(generatedFileWriter.Write, storage).
+		ExpectPanic(
+			NewErrorMessageConstraint(
+				"File '%s' is owned by generator 'second', which doesn't participate in generation",
+				filepath.Join(namespace.Path, "file.go"),
+			),
+		)
+
+	file.Content = ""
+
+	generatedFileWriter.SetOwners([]string{"first", "second"})
+	generatedFileWriter.Write(storage)
+
+	ctrl.AssertSame(
+		Header+"// @FileGeneratedBy({\"name\":\"first\"})\n// @FileGeneratedBy({\"name\":\"second\"})\n// content",
+		file.Content,
+	)
+	fs.AssertFileContent("root/file.go", file.Content)
+}
+
+func TestGeneratedFileWriter_Write_WithHeaderWithoutMarker(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fs := NewTmpFS(ctrl).CreateDir("root", 0777)
+
+	file := &File{
+		Name:        "custom.go",
+		PackageName: "namespace",
+		Annotations: []interface{}{FileGeneratedByAnnotation{Name: "custom"}},
+	}
+	storage := &Storage{
+		Namespaces: []*Namespace{{Name: "namespace", Path: filepath.Join(fs.RootPath(), "root"), Files: []*File{file}}},
+	}
+
+	validator := NewValidatorMock(ctrl)
+	renderer := NewRendererMock(ctrl)
+
+	validator.
+		EXPECT().
+		Validate(storage).
+		Return(nil)
+
+	renderer.
+		EXPECT().
+		Render(file).
+		Return("// content")
+
+	generatedFileWriter := NewGeneratedFileWriter(validator, renderer)
+	generatedFileWriter.SetOwners([]string{"custom"})
+	generatedFileWriter.SetHeaders(map[string]string{"custom": "// License header"})
+	generatedFileWriter.Write(storage)
+
+	ctrl.AssertSame(
+		"// Code generated by github.com/index0h/go-annotation. DO NOT EDIT.\n"+
+			"// License header\n"+
+			"// @FileGeneratedBy({\"name\":\"custom\"})\n"+
+			"// content",
+		file.Content,
+	)
+	fs.AssertFileContent("root/custom.go", file.Content)
+}
+
+func TestGeneratedFileWriter_Write_WithRegions(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := "package namespace\n\n" +
+		"func init() {\n" +
+		"\t// go-annotation:begin registry\n" +
+		"\t// go-annotation:end registry\n" +
+		"}\n"
+	unchangedContent := "package namespace\n\n" +
+		"// go-annotation:begin registry\n" +
+		"// go-annotation:end registry\n"
+
+	fs := NewTmpFS(ctrl).
+		CreateDir("root", 0777).
+		CreateFile("root/file.go", 0666, content).
+		CreateFile("root/unchanged.go", 0666, unchangedContent)
+
+	file := &File{Name: "file.go", PackageName: "namespace", Content: content}
+	unchangedFile := &File{Name: "unchanged.go", PackageName: "namespace", Content: unchangedContent}
+	namespace := &Namespace{
+		Name:  "namespace",
+		Path:  filepath.Join(fs.RootPath(), "root"),
+		Files: []*File{file, unchangedFile},
+	}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+
+	regions := NewRegions()
+	regions.Set(file, &Region{Name: "registry", Content: "\tRegister()"})
+
+	expected := "package namespace\n\n" +
+		"func init() {\n" +
+		"\t// go-annotation:begin registry\n" +
+		"\tRegister()\n" +
+		"\t// go-annotation:end registry\n" +
+		"}\n"
+
+	generatedFileWriter := NewGeneratedFileWriter(NewEntityValidator(), NewRendererMock(ctrl))
+	generatedFileWriter.SetRegions(regions)
+	generatedFileWriter.Write(storage)
+
+	ctrl.AssertSame(expected, file.Content)
+	fs.AssertFileContent("root/file.go", expected)
+	fs.AssertFileContent("root/unchanged.go", unchangedContent)
+}
+
+func TestGeneratedFileWriter_Write_WithEditor(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := "package namespace\n\n// Model is kept.\ntype Model struct{}\n"
+	fs := NewTmpFS(ctrl).
+		CreateDir("root", 0777).
+		CreateFile("root/model.go", 0666, content)
+
+	sourceParser := NewGoSourceParser(NewJSONAnnotationParser())
+	file := sourceParser.Parse("model.go", content)
+	file.Funcs = append(file.Funcs, &Func{Name: "Validate", Spec: &FuncSpec{}, Content: "return"})
+	namespace := &Namespace{Name: "namespace", Path: filepath.Join(fs.RootPath(), "root"), Files: []*File{file}}
+	storage := &Storage{Namespaces: []*Namespace{namespace}}
+	changeSet := NewChangeSet()
+
+	expected := "package namespace\n\n// Model is kept.\ntype Model struct{}\n\nfunc Validate() {\n\treturn\n}\n"
+
+	generatedFileWriter := NewGeneratedFileWriter(NewEntityValidator(), NewEntityRenderer())
+	generatedFileWriter.SetEditor(NewSourceFileEditor(sourceParser, NewEntityRenderer()))
+	generatedFileWriter.SetChangeSet(changeSet)
+	generatedFileWriter.Write(storage)
+
+	ctrl.AssertSame(expected, file.Content)
+	fs.AssertFileContent("root/model.go", expected)
+	ctrl.AssertEqual(
+		[]*FileChange{
+			{
+				Kind:         FileChangeKindEdit,
+				Path:         filepath.Join(fs.RootPath(), "root", "model.go"),
+				Declarations: []string{"func Validate"},
+			},
+		},
+		changeSet.List(),
+	)
+}
+
+func TestGeneratedFileWriter_Write_WithNotOwnedFile(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fs := NewTmpFS(ctrl).
+		CreateDir("root", 0777).
+		CreateFile("root/foreign.go", 0666, Header+"// @FileGeneratedBy({\"name\":\"foreign\"})\npackage namespace").
+		CreateFile("root/manual.go", 0666, "package namespace").
+		CreateFile("root/stringer.go", 0666, "// Code generated by \"stringer\"; DO NOT EDIT.\n\npackage namespace")
+
+	type testCase struct {
+		name     string
+		isOwned  bool
+		expected string
+	}
+
+	testCases := []testCase{
+		{
+			name:     "foreign.go",
+			expected: "File '%s' is owned by generator 'foreign', which doesn't participate in generation",
+		},
+		{
+			name:     "manual.go",
+			expected: "File '%s' already exists",
+		},
+		{
+			name:     "stringer.go",
+			expected: "File '%s' already exists",
+		},
+		{
+			name:     "manual.go",
+			isOwned:  true,
+			expected: "File '%s' is created by generator 'owner', but it already exists",
+		},
+	}
+
+	for _, testCase := range testCases {
+		file := &File{Name: testCase.name, PackageName: "namespace"}
+
+		if testCase.isOwned {
+			file.Annotations = []interface{}{FileGeneratedByAnnotation{Name: "owner"}}
+		}
+		storage := &Storage{
+			Namespaces: []*Namespace{
+				{Name: "namespace", Path: filepath.Join(fs.RootPath(), "root"), Files: []*File{file}},
+			},
+		}
+
+		validator := NewValidatorMock(ctrl)
+		renderer := NewRendererMock(ctrl)
+
+		validator.
+			EXPECT().
+			Validate(storage).
+			Return(nil)
+
+		renderer.
+			EXPECT().
+			Render(file).
+			Return("// content")
+
+		generatedFileWriter := NewGeneratedFileWriter(validator, renderer)
+		generatedFileWriter.SetOwners([]string{"owner"})
+
+		ctrl.Subtest(testCase.name).
+			Call(generatedFileWriter.Write, storage).
+			ExpectPanic(
+				NewErrorMessageConstraint(testCase.expected, filepath.Join(fs.RootPath(), "root", testCase.name)),
+			)
+	}
+}
+
 func TestGeneratedFileWriter_Write_WithInvalidStorage(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
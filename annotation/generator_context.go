@@ -0,0 +1,351 @@
+package annotation
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// GeneratorContext is passed to ContextGenerator, it contains options, logger and diagnostics of one generator.
+type GeneratorContext struct {
+	application *Application
+	name        string
+	version     string
+	options     map[string]interface{}
+	logger      Logger
+	// Files, which were created by generator, in order of creation.
+	files []*createdFile
+	// Created files are not added to storage, Application adds them after all concurrent generators are run.
+	isDeferred bool
+}
+
+// File, which was created by generator, and its namespace.
+type createdFile struct {
+	namespace *Namespace
+	file      *File
+}
+
+// Creates new instance of GeneratorContext.
+func NewGeneratorContext(
+	application *Application,
+	name string,
+	options map[string]interface{},
+	logger Logger,
+) *GeneratorContext {
+	if application == nil {
+		panic(errors.New("Variable 'application' must be not nil"))
+	}
+
+	if name == "" {
+		panic(errors.New("Variable 'name' must be not empty"))
+	}
+
+	if options == nil {
+		panic(errors.New("Variable 'options' must be not nil"))
+	}
+
+	if logger == nil {
+		panic(errors.New("Variable 'logger' must be not nil"))
+	}
+
+	return &GeneratorContext{
+		application: application,
+		name:        name,
+		options:     options,
+		logger:      logger,
+	}
+}
+
+func (c *GeneratorContext) Application() *Application {
+	return c.application
+}
+
+// Returns name of generator.
+func (c *GeneratorContext) Name() string {
+	return c.name
+}
+
+// Returns options of generator.
+func (c *GeneratorContext) Options() map[string]interface{} {
+	return c.options
+}
+
+// Returns option of generator by key or defaultValue if it's absent.
+func (c *GeneratorContext) Option(key string, defaultValue interface{}) interface{} {
+	if value, ok := c.options[key]; ok {
+		return value
+	}
+
+	return defaultValue
+}
+
+// Returns logger, which adds name of generator to each message.
+func (c *GeneratorContext) Logger() Logger {
+	return c.logger
+}
+
+// Reports error, entity could be nil. Generation fails after all generators are run.
+func (c *GeneratorContext) Error(entity interface{}, format string, args ...interface{}) {
+	c.report(DiagnosticSeverityError, entity, fmt.Sprintf(format, args...))
+}
+
+// Reports warning, entity could be nil.
+func (c *GeneratorContext) Warning(entity interface{}, format string, args ...interface{}) {
+	c.report(DiagnosticSeverityWarning, entity, fmt.Sprintf(format, args...))
+}
+
+// Creates new file in namespace and adds it to storage. Content of file will be rendered by storage writer, name and
+// version of generator are written into its header. If generated file with the same name exists and conflict policy
+// is FileConflictPolicyMerge, existing file is returned. Other conflicts are reported as errors and new file, which
+// is not added to storage, is returned, so generator could continue. If generator is run concurrently, file is added
+// to storage after all concurrent generators are run, so it could not be found in namespace until then.
+func (c *GeneratorContext) CreateFile(namespace *Namespace, name string) *File {
+	if namespace == nil {
+		panic(errors.New("Variable 'namespace' must be not nil"))
+	}
+
+	if name == "" {
+		panic(errors.New("Variable 'name' must be not empty"))
+	}
+
+	if existing := c.findFile(namespace, name); existing != nil {
+		return c.resolveConflict(namespace, existing, name)
+	}
+
+	result := &File{
+		Name:        name,
+		PackageName: namespace.PackageName(),
+		Annotations: []interface{}{FileGeneratedByAnnotation{Name: c.name, Version: c.version}},
+	}
+
+	if !c.isDeferred {
+		c.application.Storage().AddFile(namespace, result)
+	}
+
+	c.files = append(c.files, &createdFile{namespace: namespace, file: result})
+	c.logger.Debug("File is created", "namespace", namespace.Name, "file", name)
+
+	return result
+}
+
+// Sets content of region inside hand-written file. Region name must start with name of generator, content is
+// written by storage writer, other content of file is kept.
+func (c *GeneratorContext) FillRegion(file *File, name string, content string) {
+	if file == nil {
+		panic(errors.New("Variable 'file' must be not nil"))
+	}
+
+	if regionOwner(name) != c.name {
+		panic(errors.Errorf("Region '%s' doesn't belong to generator '%s'", name, c.name))
+	}
+
+	regions, err := findRegions(file.Content)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for _, region := range regions {
+		if region.name == name {
+			c.application.Regions().Set(file, &Region{Name: name, Content: content})
+			c.logger.Debug("Region is filled", "file", file.Name, "region", name)
+
+			return
+		}
+	}
+
+	panic(errors.Errorf("File '%s' has no region '%s'", file.Name, name))
+}
+
+// Returns file of namespace by name, deferred files of generator are searched too.
+func (c *GeneratorContext) findFile(namespace *Namespace, name string) *File {
+	for _, created := range c.files {
+		if created.namespace == namespace && created.file.Name == name {
+			return created.file
+		}
+	}
+
+	return namespace.FindFileByName(name)
+}
+
+// Returns namespace of file, deferred files of generator are searched too.
+func (c *GeneratorContext) findNamespace(file *File) *Namespace {
+	for _, created := range c.files {
+		if created.file == file {
+			return created.namespace
+		}
+	}
+
+	return c.application.Storage().FindNamespaceByFile(file)
+}
+
+// Returns existing generated file, if conflict policy allows merge, otherwise reports error and returns new file,
+// which is not added to storage.
+func (c *GeneratorContext) resolveConflict(namespace *Namespace, existing *File, name string) *File {
+	if existing.Content == "" && c.application.FileConflictResolver().Policy() == FileConflictPolicyMerge {
+		c.logger.Debug("File is merged", "namespace", namespace.Name, "file", name, "owner", fileOwnerName(existing))
+
+		return existing
+	}
+
+	conflict := &FileConflict{
+		Namespace: namespace,
+		File:      existing,
+		Conflicting: &File{
+			Name:        name,
+			PackageName: namespace.PackageName(),
+			Annotations: []interface{}{FileGeneratedByAnnotation{Name: c.name, Version: c.version}},
+		},
+	}
+
+	c.report(DiagnosticSeverityError, existing, conflict.String())
+
+	return conflict.Conflicting
+}
+
+func (c *GeneratorContext) report(severity string, entity interface{}, message string) {
+	diagnostic := &Diagnostic{Severity: severity, Generator: c.name, Message: message, Entity: entity}
+
+	switch entity := entity.(type) {
+	case nil:
+	case *Namespace:
+		diagnostic.Namespace = entity
+	case *File:
+		diagnostic.File = entity
+		diagnostic.Namespace = c.findNamespace(entity)
+	default:
+		diagnostic.File = c.application.EntityContext().File(entity)
+		diagnostic.Namespace = c.application.EntityContext().Namespace(entity)
+	}
+
+	switch {
+	case diagnostic.Namespace != nil && diagnostic.File != nil:
+		diagnostic.Position.Filename = filepath.Join(diagnostic.Namespace.Path, diagnostic.File.Name)
+	case diagnostic.Namespace != nil:
+		diagnostic.Position.Filename = diagnostic.Namespace.Path
+	case diagnostic.File != nil:
+		diagnostic.Position.Filename = diagnostic.File.Name
+	}
+
+	if diagnostic.File != nil && entity != nil {
+		diagnostic.Position.Line, diagnostic.Position.Column = c.declarationPosition(diagnostic.File, entity)
+	}
+
+	c.application.Diagnostics().Add(diagnostic)
+}
+
+// Returns line and column of top-level declaration, which contains entity, in content of file, or zeros if they are
+// unknown, e.g. file is new or entity is not declared in it.
+func (c *GeneratorContext) declarationPosition(file *File, entity interface{}) (int, int) {
+	if file.Content == "" {
+		return 0, 0
+	}
+
+	declaration := entity
+
+	for _, parent := range c.application.EntityContext().Parents(entity) {
+		switch parent.(type) {
+		case *Type, *Func, *Const, *Var:
+			declaration = parent
+		}
+	}
+
+	fileSet := token.NewFileSet()
+	astFile, err := parser.ParseFile(fileSet, file.Name, file.Content, 0)
+
+	if err != nil {
+		return 0, 0
+	}
+
+	var position token.Pos
+
+	for _, decl := range astFile.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if element, ok := declaration.(*Func); ok && decl.Name.Name == element.Name &&
+				c.receiverTypeName(decl) == c.relatedTypeName(element) {
+				position = decl.Pos()
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if c.isDeclaredBySpec(declaration, decl.Tok, spec) {
+					position = spec.Pos()
+				}
+			}
+		}
+
+		if position.IsValid() {
+			result := fileSet.Position(position)
+
+			return result.Line, result.Column
+		}
+	}
+
+	return 0, 0
+}
+
+func (c *GeneratorContext) isDeclaredBySpec(declaration interface{}, tok token.Token, spec ast.Spec) bool {
+	switch spec := spec.(type) {
+	case *ast.TypeSpec:
+		element, ok := declaration.(*Type)
+
+		return ok && spec.Name.Name == element.Name
+	case *ast.ValueSpec:
+		name := ""
+
+		switch element := declaration.(type) {
+		case *Const:
+			if tok == token.CONST {
+				name = element.Name
+			}
+		case *Var:
+			if tok == token.VAR {
+				name = element.Name
+			}
+		}
+
+		for _, ident := range spec.Names {
+			if name != "" && ident.Name == name {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Returns name of receiver type of method or empty string for func.
+func (c *GeneratorContext) receiverTypeName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) == 0 {
+		return ""
+	}
+
+	expr := decl.Recv.List[0].Type
+
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+
+	return ""
+}
+
+// Returns name of related type of method or empty string for func.
+func (c *GeneratorContext) relatedTypeName(element *Func) string {
+	if element.Related == nil {
+		return ""
+	}
+
+	if spec, ok := element.Related.Spec.(*SimpleSpec); ok {
+		return spec.TypeName
+	}
+
+	return ""
+}
@@ -0,0 +1,266 @@
+package annotation
+
+import (
+	"bytes"
+	"go/token"
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestNewGeneratorContext(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	application := &Application{}
+	options := map[string]interface{}{"key": "value"}
+	logger := NewTextLogger(&bytes.Buffer{})
+
+	actual := NewGeneratorContext(application, "mock", options, logger)
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(application, actual.Application())
+	ctrl.AssertSame("mock", actual.Name())
+	ctrl.AssertEqual(options, actual.Options())
+	ctrl.AssertSame(logger, actual.Logger())
+	ctrl.AssertSame("value", actual.Option("key", "default"))
+	ctrl.AssertSame("default", actual.Option("unknown", "default"))
+}
+
+func TestNewGeneratorContext_WithInvalidArguments(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	options := map[string]interface{}{}
+	logger := NewTextLogger(&bytes.Buffer{})
+
+	ctrl.Subtest("Application").
+		Call(NewGeneratorContext, nil, "mock", options, logger).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'application' must be not nil"))
+	ctrl.Subtest("Name").
+		Call(NewGeneratorContext, &Application{}, "", options, logger).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'name' must be not empty"))
+	ctrl.Subtest("Options").
+		Call(NewGeneratorContext, &Application{}, "mock", nil, logger).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'options' must be not nil"))
+	ctrl.Subtest("Logger").
+		Call(NewGeneratorContext, &Application{}, "mock", options, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'logger' must be not nil"))
+}
+
+func TestGeneratorContext_Report(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	field := &Field{Name: "ID", Spec: &SimpleSpec{TypeName: "int"}}
+	file := &File{
+		Name:        "model.go",
+		PackageName: "model",
+		TypeGroups:  []*TypeGroup{{Types: []*Type{{Name: "Model", Spec: &StructSpec{Fields: []*Field{field}}}}}},
+	}
+	namespace := &Namespace{Name: "model", Path: "/model", Files: []*File{file}}
+	application := &Application{storage: &Storage{Namespaces: []*Namespace{namespace}}}
+
+	application.ContextIndexer().Index(application.Storage(), application.EntityContext())
+
+	context := NewGeneratorContext(application, "mock", map[string]interface{}{}, NewTextLogger(&bytes.Buffer{}))
+
+	context.Error(field, "Field '%s' is invalid", field.Name)
+	context.Warning(file, "warning")
+	context.Warning(namespace, "warning")
+	context.Warning(nil, "warning")
+
+	ctrl.AssertEqual(
+		[]*Diagnostic{
+			{
+				Severity:  DiagnosticSeverityError,
+				Generator: "mock",
+				Message:   "Field 'ID' is invalid",
+				Entity:    field,
+				Namespace: namespace,
+				File:      file,
+				Position:  token.Position{Filename: "/model/model.go"},
+			},
+			{
+				Severity:  DiagnosticSeverityWarning,
+				Generator: "mock",
+				Message:   "warning",
+				Entity:    file,
+				Namespace: namespace,
+				File:      file,
+				Position:  token.Position{Filename: "/model/model.go"},
+			},
+			{
+				Severity:  DiagnosticSeverityWarning,
+				Generator: "mock",
+				Message:   "warning",
+				Entity:    namespace,
+				Namespace: namespace,
+				Position:  token.Position{Filename: "/model"},
+			},
+			{Severity: DiagnosticSeverityWarning, Generator: "mock", Message: "warning"},
+		},
+		application.Diagnostics().List(),
+	)
+}
+
+func TestGeneratorContext_Report_WithDeclarationPosition(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	file := NewGoSourceParser(NewJSONAnnotationParser()).Parse(
+		"model.go",
+		"package model\n\n"+
+			"const (\n\tFirst = 1\n\tSecond = 2\n)\n\n"+
+			"var Default = Model{}\n\n"+
+			"type Model struct {\n\tID int\n}\n\n"+
+			"func ID() {}\n\n"+
+			"func (m *Model) ID() int { return m.ID }\n",
+	)
+	namespace := &Namespace{Name: "model", Path: "/model", Files: []*File{file}}
+	application := &Application{storage: &Storage{Namespaces: []*Namespace{namespace}}}
+
+	application.ContextIndexer().Index(application.Storage(), application.EntityContext())
+
+	context := NewGeneratorContext(application, "mock", map[string]interface{}{}, NewTextLogger(&bytes.Buffer{}))
+	field := file.TypeGroups[0].Types[0].Spec.(*StructSpec).Fields[0]
+
+	context.Error(file.ConstGroups[0].Consts[1], "const")
+	context.Error(file.VarGroups[0].Vars[0], "var")
+	context.Error(field, "field")
+	context.Error(file.Funcs[0], "func")
+	context.Error(file.Funcs[1], "method")
+	context.Error(file, "file")
+
+	actual := []string{}
+
+	for _, diagnostic := range application.Diagnostics().List() {
+		actual = append(actual, diagnostic.String())
+	}
+
+	ctrl.AssertEqual(
+		[]string{
+			"/model/model.go:5:2: error: const (generator: mock)",
+			"/model/model.go:8:5: error: var (generator: mock)",
+			"/model/model.go:10:6: error: field (generator: mock)",
+			"/model/model.go:14:1: error: func (generator: mock)",
+			"/model/model.go:16:1: error: method (generator: mock)",
+			"/model/model.go: error: file (generator: mock)",
+		},
+		actual,
+	)
+}
+
+func TestGeneratorContext_CreateFile(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	namespace := &Namespace{Name: "example.com/model", Path: "/model"}
+	application := &Application{storage: &Storage{Namespaces: []*Namespace{namespace}}}
+	writer := &bytes.Buffer{}
+	logger := NewTextLogger(writer)
+	logger.SetLevel(LogLevelDebug)
+	context := NewGeneratorContext(application, "mock", map[string]interface{}{}, logger)
+	context.version = "1.0"
+
+	expected := &File{
+		Name:        "mock.go",
+		PackageName: "model",
+		Annotations: []interface{}{FileGeneratedByAnnotation{Name: "mock", Version: "1.0"}},
+	}
+
+	actual := context.CreateFile(namespace, "mock.go")
+
+	ctrl.AssertEqual(expected, actual)
+	ctrl.AssertEqual([]*File{actual}, namespace.Files)
+	ctrl.AssertSame(
+		"level=debug message=\"File is created\" namespace=example.com/model file=mock.go\n",
+		writer.String(),
+	)
+
+	ctrl.Subtest("Namespace").
+		Call(context.CreateFile, nil, "mock.go").
+		ExpectPanic(NewErrorMessageConstraint("Variable 'namespace' must be not nil"))
+	ctrl.Subtest("Name").
+		Call(context.CreateFile, namespace, "").
+		ExpectPanic(NewErrorMessageConstraint("Variable 'name' must be not empty"))
+}
+
+func TestGeneratorContext_CreateFile_WithConflict(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	manualFile := &File{Name: "manual.go", PackageName: "model", Content: "package model"}
+	generatedFile := &File{Name: "mock.go", Annotations: []interface{}{FileGeneratedByAnnotation{Name: "first"}}}
+	namespace := &Namespace{Name: "example.com/model", Path: "/model", Files: []*File{manualFile, generatedFile}}
+	resolver := NewGeneratedFileConflictResolver(NewEntityImportRenamer())
+	application := &Application{
+		storage:              &Storage{Namespaces: []*Namespace{namespace}},
+		fileConflictResolver: resolver,
+	}
+	context := NewGeneratorContext(application, "second", map[string]interface{}{}, NewTextLogger(&bytes.Buffer{}))
+	conflicting := &File{
+		Name:        "manual.go",
+		PackageName: "model",
+		Annotations: []interface{}{FileGeneratedByAnnotation{Name: "second"}},
+	}
+
+	ctrl.AssertEqual(conflicting, context.CreateFile(namespace, "manual.go"))
+
+	conflicting.Name = "mock.go"
+
+	ctrl.AssertEqual(conflicting, context.CreateFile(namespace, "mock.go"))
+
+	resolver.SetPolicy(FileConflictPolicyMerge)
+
+	ctrl.AssertSame(generatedFile, context.CreateFile(namespace, "mock.go"))
+
+	conflicting.Name = "manual.go"
+
+	ctrl.AssertEqual(conflicting, context.CreateFile(namespace, "manual.go"))
+	ctrl.AssertEqual([]*File{manualFile, generatedFile}, namespace.Files)
+	ctrl.AssertEqual(
+		[]string{
+			"/model/manual.go: error: File '/model/manual.go' is created by generator 'second', " +
+				"but it already exists (generator: second)",
+			"/model/mock.go: error: File '/model/mock.go' is created by generators 'first' and 'second' " +
+				"(generator: second)",
+			"/model/manual.go: error: File '/model/manual.go' is created by generator 'second', " +
+				"but it already exists (generator: second)",
+		},
+		[]string{
+			application.Diagnostics().List()[0].String(),
+			application.Diagnostics().List()[1].String(),
+			application.Diagnostics().List()[2].String(),
+		},
+	)
+}
+
+func TestGeneratorContext_FillRegion(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	file := &File{
+		Name:    "model.go",
+		Content: "package model\n\n// go-annotation:begin stringer:Color\n// go-annotation:end stringer:Color\n",
+	}
+	application := &Application{}
+	context := NewGeneratorContext(application, "stringer", map[string]interface{}{}, NewTextLogger(&bytes.Buffer{}))
+
+	context.FillRegion(file, "stringer:Color", "func (c Color) String() string { return \"\" }")
+
+	ctrl.AssertEqual(
+		&Region{Name: "stringer:Color", Content: "func (c Color) String() string { return \"\" }"},
+		application.Regions().Find(file, "stringer:Color"),
+	)
+
+	ctrl.Subtest("File").
+		Call(context.FillRegion, nil, "stringer", "").
+		ExpectPanic(NewErrorMessageConstraint("Variable 'file' must be not nil"))
+	ctrl.Subtest("Owner").
+		Call(context.FillRegion, file, "mock:Color", "").
+		ExpectPanic(NewErrorMessageConstraint("Region 'mock:Color' doesn't belong to generator 'stringer'"))
+	ctrl.Subtest("Unknown").
+		Call(context.FillRegion, file, "stringer:Size", "").
+		ExpectPanic(NewErrorMessageConstraint("File 'model.go' has no region 'stringer:Size'"))
+}
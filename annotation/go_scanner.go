@@ -1,6 +1,7 @@
 package annotation
 
 import (
+	"go/build"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -13,6 +14,7 @@ import (
 type GoScanner struct {
 	sourceParser     SourceParser
 	annotationParser AnnotationParser
+	buildTags        []string
 }
 
 func NewGoScanner(sourceParser SourceParser, annotationParser AnnotationParser) *GoScanner {
@@ -30,12 +32,18 @@ func NewGoScanner(sourceParser SourceParser, annotationParser AnnotationParser)
 	}
 }
 
+// Sets build tags, if they are not nil, only files, which match build tags, GOOS and GOARCH, are scanned.
+func (s *GoScanner) SetBuildTags(buildTags []string) {
+	s.buildTags = buildTags
+}
+
 // Scans all golang sources recursively inside of rootPath argument.
 // If rootNamespace is empty rootPath will be ignored, and Namespace models will be created only for children folders.
 // Argument may contain part of path, or absolute path to folder, which must be ignored.
+// Scan could be repeated, files of already scanned namespaces are not parsed again, only new files are added.
 func (s *GoScanner) Scan(storage *Storage, rootNamespace string, rootPath string, ignores ...string) {
 	for _, folder := range s.findAllFolders(rootPath) {
-		pathSuffix := strings.TrimLeft(folder, rootPath)
+		pathSuffix := strings.TrimPrefix(strings.TrimPrefix(folder, rootPath), string(filepath.Separator))
 
 		if pathSuffix == "" && rootNamespace == "" {
 			continue
@@ -54,9 +62,22 @@ func (s *GoScanner) Scan(storage *Storage, rootNamespace string, rootPath string
 			}
 		}
 
-		namespace.Files = s.scanFiles(namespace.Path)
+		files := s.scanFiles(namespace.Path)
+
+		// Namespace could be scanned before, in this case only new files are added to it.
+		if existing := storage.FindNamespaceByPath(namespace.Path); existing != nil && existing.Name == namespace.Name {
+			for _, file := range files {
+				if existing.FindFileByName(file.Name) == nil {
+					storage.AddFile(existing, file)
+				}
+			}
+
+			continue
+		}
+
+		namespace.Files = files
 
-		storage.Namespaces = append(storage.Namespaces, namespace)
+		storage.AddNamespace(namespace)
 	}
 }
 
@@ -77,6 +98,10 @@ func (s *GoScanner) scanFiles(path string) []*File {
 			continue
 		}
 
+		if s.buildTags != nil && !s.matchBuildTags(path) {
+			continue
+		}
+
 		content, err := ioutil.ReadFile(path)
 
 		if err != nil {
@@ -89,6 +114,19 @@ func (s *GoScanner) scanFiles(path string) []*File {
 	return result
 }
 
+func (s *GoScanner) matchBuildTags(path string) bool {
+	context := build.Default
+	context.BuildTags = s.buildTags
+
+	isMatched, err := context.MatchFile(filepath.Dir(path), filepath.Base(path))
+
+	if err != nil {
+		panic(err)
+	}
+
+	return isMatched
+}
+
 func (s *GoScanner) findAllFolders(path string) []string {
 	result := []string{}
 
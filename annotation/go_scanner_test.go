@@ -177,7 +177,7 @@ func TestGoScanner_Scan(t *testing.T) {
 
 	scanner.Scan(storage, "", fs.RootPath(), "ignored")
 
-	ctrl.AssertEqual(expected, storage)
+	ctrl.AssertEqual(expected.Namespaces, storage.Namespaces)
 	ctrl.AssertSame(file11, storage.Namespaces[0].Files[0])
 	ctrl.AssertSame(file21, storage.Namespaces[1].Files[0])
 	ctrl.AssertSame(file31, storage.Namespaces[2].Files[0])
@@ -237,7 +237,7 @@ func TestGoScanner_Scan_WithoutFiles(t *testing.T) {
 
 	scanner.Scan(storage, "", fs.RootPath(), "ignored")
 
-	ctrl.AssertEqual(expected, storage)
+	ctrl.AssertEqual(expected.Namespaces, storage.Namespaces)
 }
 
 func TestGoScanner_Scan_WithRootNamespace(t *testing.T) {
@@ -379,7 +379,7 @@ func TestGoScanner_Scan_WithRootNamespace(t *testing.T) {
 
 	scanner.Scan(storage, rootNamespace, fs.RootPath(), "ignored")
 
-	ctrl.AssertEqual(expected, storage)
+	ctrl.AssertEqual(expected.Namespaces, storage.Namespaces)
 	ctrl.AssertSame(file11, storage.Namespaces[1].Files[0])
 	ctrl.AssertSame(file41, storage.Namespaces[4].Files[0])
 }
@@ -503,3 +503,81 @@ func TestStorage_scanFiles_WithReadFileError(t *testing.T) {
 		Call(scanner.scanFiles, fs.RootPath()).
 		ExpectPanic(ctrl.Type(&os.PathError{}))
 }
+
+func TestGoScanner_Scan_WithBuildTags(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fs := NewTmpFS(ctrl).
+		CreateDir("model", 0777).
+		CreateFile("model/a.go", 0666, "package model\n").
+		CreateFile("model/b.go", 0666, "//go:build integration\n\npackage model\n").
+		CreateFile("model/c_plan9.go", 0666, "package model\n")
+
+	scanner := NewGoScanner(NewGoSourceParser(NewJSONAnnotationParser()), NewJSONAnnotationParser())
+
+	storage := &Storage{}
+	scanner.SetBuildTags([]string{})
+	scanner.Scan(storage, "", fs.RootPath())
+
+	ctrl.AssertSame(1, len(storage.Namespaces[0].Files))
+	ctrl.AssertSame("a.go", storage.Namespaces[0].Files[0].Name)
+
+	storage = &Storage{}
+	scanner.SetBuildTags([]string{"integration"})
+	scanner.Scan(storage, "", fs.RootPath())
+
+	ctrl.AssertSame(2, len(storage.Namespaces[0].Files))
+	ctrl.AssertSame("b.go", storage.Namespaces[0].Files[1].Name)
+
+	storage = &Storage{}
+	scanner.SetBuildTags(nil)
+	scanner.Scan(storage, "", fs.RootPath())
+
+	ctrl.AssertSame(3, len(storage.Namespaces[0].Files))
+}
+
+func TestGoScanner_Scan_WithRepeatedScan(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fs := NewTmpFS(ctrl).
+		CreateDir("model", 0777).
+		CreateFile("model/a.go", 0666, "package model\n")
+
+	scanner := NewGoScanner(NewGoSourceParser(NewJSONAnnotationParser()), NewJSONAnnotationParser())
+
+	storage := &Storage{}
+	scanner.Scan(storage, "", fs.RootPath())
+
+	namespace := storage.Namespaces[0]
+	file := namespace.Files[0]
+
+	fs.CreateFile("model/b.go", 0666, "package model\n\ntype Model struct{}\n")
+	scanner.Scan(storage, "", fs.RootPath())
+
+	ctrl.AssertSame(1, len(storage.Namespaces))
+	ctrl.AssertSame(namespace, storage.Namespaces[0])
+	ctrl.AssertSame(2, len(namespace.Files))
+	ctrl.AssertSame(file, namespace.Files[0])
+	ctrl.AssertSame("b.go", namespace.Files[1].Name)
+	ctrl.AssertNotNil(storage.FindDeclaration("model.Model"))
+}
+
+func TestGoScanner_Scan_WithFolderNameOfRootPathChars(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fs := NewTmpFS(ctrl).
+		CreateDir("src", 0777).
+		CreateDir("src/crs", 0777).
+		CreateFile("src/crs/a.go", 0666, "package crs\n")
+
+	scanner := NewGoScanner(NewGoSourceParser(NewJSONAnnotationParser()), NewJSONAnnotationParser())
+
+	storage := &Storage{}
+	scanner.Scan(storage, "", filepath.Join(fs.RootPath(), "src"))
+
+	ctrl.AssertSame(1, len(storage.Namespaces))
+	ctrl.AssertSame("crs", storage.Namespaces[0].Name)
+}
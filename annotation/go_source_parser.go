@@ -19,6 +19,7 @@ type ellipsis struct {
 // Parses golang sources and build File models.
 type GoSourceParser struct {
 	annotationParser AnnotationParser
+	isLayoutKept     bool
 }
 
 // Creates new instance of GoSourceParser.
@@ -32,6 +33,11 @@ func NewGoSourceParser(annotationParser AnnotationParser) *GoSourceParser {
 	}
 }
 
+// Enables filling of File.Layout, so parsed file could be rendered again without loss of formatting.
+func (p *GoSourceParser) SetLayoutKept(isLayoutKept bool) {
+	p.isLayoutKept = isLayoutKept
+}
+
 // Generates File model by golang source.
 func (p *GoSourceParser) Parse(fileName string, content string) *File {
 	fileSet := token.NewFileSet()
@@ -41,9 +47,21 @@ func (p *GoSourceParser) Parse(fileName string, content string) *File {
 		panic(err)
 	}
 
+	result := p.parseFile(astFile, fileSet)
+	result.Name = fileName
+	result.Content = content
+
+	if p.isLayoutKept {
+		result.Layout = p.parseLayout(astFile, fileSet, result)
+		result.Layout.PackageName = result.PackageName
+		result.Layout.Comment = result.Comment
+	}
+
+	return result
+}
+
+func (p *GoSourceParser) parseFile(astFile *ast.File, fileSet *token.FileSet) *File {
 	result := &File{
-		Name:         fileName,
-		Content:      content,
 		PackageName:  astFile.Name.Name,
 		Comment:      strings.TrimSpace(astFile.Doc.Text()),
 		ImportGroups: []*ImportGroup{},
@@ -79,6 +97,66 @@ func (p *GoSourceParser) Parse(fileName string, content string) *File {
 	return result
 }
 
+// Fills layout of parsed file. Declarations of file are cloned, so Original isn't changed with declarations of File.
+func (p *GoSourceParser) parseLayout(astFile *ast.File, fileSet *token.FileSet, file *File) *FileLayout {
+	content := file.Content
+	offset := fileSet.Position(astFile.Name.End()).Offset
+	result := &FileLayout{Header: content[:offset], Declarations: []*DeclarationLayout{}}
+	declarations := fileDeclarations(file)
+	cloner := NewEntityCloner()
+	indexes := map[string]int{}
+
+	for _, node := range astFile.Decls {
+		var doc *ast.CommentGroup
+
+		kind := "func"
+
+		switch decl := node.(type) {
+		case *ast.GenDecl:
+			doc = decl.Doc
+			kind = decl.Tok.String()
+		case *ast.FuncDecl:
+			doc = decl.Doc
+		default:
+			continue
+		}
+
+		begin := fileSet.Position(node.Pos()).Offset
+
+		if doc != nil {
+			begin = fileSet.Position(doc.Pos()).Offset
+		}
+
+		end := fileSet.Position(node.End()).Offset
+		line := fileSet.Position(node.End()).Line
+
+		// Line comment of single declaration belongs to it
+		for _, comment := range astFile.Comments {
+			if comment.Pos() >= node.End() && fileSet.Position(comment.Pos()).Line == line {
+				end = fileSet.Position(comment.End()).Offset
+			}
+		}
+
+		result.Declarations = append(
+			result.Declarations,
+			&DeclarationLayout{
+				Kind:     kind,
+				Index:    indexes[kind],
+				Leading:  content[offset:begin],
+				Source:   content[begin:end],
+				Original: cloner.Clone(declarations[kind][indexes[kind]]),
+			},
+		)
+
+		indexes[kind]++
+		offset = end
+	}
+
+	result.Trailer = content[offset:]
+
+	return result
+}
+
 func (p *GoSourceParser) parseImportGroup(decl *ast.GenDecl) *ImportGroup {
 	result := &ImportGroup{
 		Comment: strings.TrimSpace(decl.Doc.Text()),
@@ -296,11 +374,7 @@ func (p *GoSourceParser) parseFunc(decl *ast.FuncDecl, astFile *ast.File, fileSe
 		Spec:    p.parseFuncSpec(decl.Type, astFile, fileSet),
 	}
 
-	buffer := bytes.Buffer{}
-	// FileSet is not changed after parse
-	_ = printer.Fprint(&buffer, fileSet, decl.Body.List)
-
-	result.Content = buffer.String()
+	result.Content = p.parseFuncContent(decl, astFile, fileSet)
 
 	if result.Comment != "" {
 		result.Annotations = p.annotationParser.Parse(result.Comment)
@@ -319,6 +393,50 @@ func (p *GoSourceParser) parseFunc(decl *ast.FuncDecl, astFile *ast.File, fileSe
 	return result
 }
 
+// Body is printed with its comments in gofmt style, statements are unindented except lines of multiline raw strings.
+func (p *GoSourceParser) parseFuncContent(decl *ast.FuncDecl, astFile *ast.File, fileSet *token.FileSet) string {
+	comments := []*ast.CommentGroup{}
+
+	for _, comment := range astFile.Comments {
+		if comment.Pos() > decl.Body.Lbrace && comment.End() <= decl.Body.Rbrace {
+			comments = append(comments, comment)
+		}
+	}
+
+	buffer := bytes.Buffer{}
+	// FileSet is not changed after parse
+	config := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	_ = config.Fprint(&buffer, fileSet, &printer.CommentedNode{Node: decl.Body, Comments: comments})
+
+	lines := strings.Split(buffer.String(), "\n")
+	isRaw := map[int]bool{}
+	blockFileSet := token.NewFileSet()
+
+	// Printed block is parsed again to find lines of raw strings, declaration of block starts at the second line
+	if blockFile, err := parser.ParseFile(blockFileSet, "", "package p\nfunc _() "+buffer.String(), 0); err == nil {
+		ast.Inspect(blockFile, func(node ast.Node) bool {
+			if literal, ok := node.(*ast.BasicLit); ok && literal.Kind == token.STRING {
+				end := blockFileSet.Position(literal.End()).Line
+
+				for line := blockFileSet.Position(literal.Pos()).Line + 1; line <= end; line++ {
+					isRaw[line-2] = true
+				}
+			}
+
+			return true
+		})
+	}
+
+	for i := 1; i < len(lines)-1; i++ {
+		if !isRaw[i] {
+			lines[i] = strings.TrimPrefix(lines[i], "\t")
+		}
+	}
+
+	// Lines of removed comments could be kept as blank lines
+	return strings.Trim(strings.Join(lines[1:len(lines)-1], "\n"), "\n")
+}
+
 func (p *GoSourceParser) parseSpec(expression ast.Expr, astFile *ast.File, fileSet *token.FileSet) interface{} {
 	if expression == nil {
 		return nil
@@ -361,11 +479,17 @@ func (p *GoSourceParser) parseSelectorExprSpec(node *ast.SelectorExpr) *SimpleSp
 	}
 }
 
-func (p *GoSourceParser) parseStarExprSpec(node *ast.StarExpr, astFile *ast.File, fileSet *token.FileSet) *SimpleSpec {
-	result := p.parseSpec(node.X, astFile, fileSet).(*SimpleSpec)
-	result.IsPointer = true
+// Pointer to named type is parsed as *SimpleSpec, pointer to other types as *PointerSpec.
+func (p *GoSourceParser) parseStarExprSpec(node *ast.StarExpr, astFile *ast.File, fileSet *token.FileSet) interface{} {
+	result := p.parseSpec(node.X, astFile, fileSet)
 
-	return result
+	if spec, ok := result.(*SimpleSpec); ok && !spec.IsPointer {
+		spec.IsPointer = true
+
+		return spec
+	}
+
+	return &PointerSpec{Value: result}
 }
 
 func (p *GoSourceParser) parseArraySpec(node *ast.ArrayType, astFile *ast.File, fileSet *token.FileSet) *ArraySpec {
@@ -413,8 +537,8 @@ func (p *GoSourceParser) parseFieldsList(node *ast.FieldList, astFile *ast.File,
 		return result
 	}
 
+	// Comments after the previous field, including its line comment, could be comments of the current one
 	beforeCommentPosition := node.Pos()
-	afterCommentPosition := node.Pos()
 
 	for _, astField := range node.List {
 		tag := ""
@@ -428,25 +552,30 @@ func (p *GoSourceParser) parseFieldsList(node *ast.FieldList, astFile *ast.File,
 		comment := strings.TrimSpace(astField.Doc.Text())
 
 		if comment == "" {
-			if len(astField.Names) == 0 {
-				afterCommentPosition = astField.Type.Pos()
-			} else {
+			afterCommentPosition := astField.Type.Pos()
+
+			if len(astField.Names) > 0 {
 				afterCommentPosition = astField.Names[0].Pos()
 			}
 
+			line := fileSet.Position(afterCommentPosition).Line
+
+			// Free-floating comments, which are separated by blank line, don't belong to field
 			for _, commentGroup := range astFile.Comments {
 				position := commentGroup.Pos()
 
-				if position >= beforeCommentPosition && position <= afterCommentPosition {
+				if position >= beforeCommentPosition &&
+					position <= afterCommentPosition &&
+					fileSet.Position(commentGroup.End()).Line >= line-1 {
 					comment = strings.TrimSpace(commentGroup.Text())
 				}
 			}
+		}
 
-			if len(astField.Names) == 0 {
-				beforeCommentPosition = astField.Type.End()
-			} else {
-				beforeCommentPosition = astField.Names[0].End()
-			}
+		beforeCommentPosition = astField.End()
+
+		if astField.Comment != nil {
+			beforeCommentPosition = astField.Comment.End()
 		}
 
 		if len(astField.Names) == 0 {
@@ -485,6 +614,10 @@ func (p *GoSourceParser) parseFieldsList(node *ast.FieldList, astFile *ast.File,
 func (p *GoSourceParser) parseStructSpec(node *ast.StructType, astFile *ast.File, fileSet *token.FileSet) *StructSpec {
 	fields := p.parseFieldsList(node.Fields, astFile, fileSet)
 
+	for _, field := range fields {
+		field.IsEmbedded = field.Name == ""
+	}
+
 	return &StructSpec{
 		Fields: fields,
 	}
@@ -497,6 +630,10 @@ func (p *GoSourceParser) parseInterfaceSpec(
 ) *InterfaceSpec {
 	methods := p.parseFieldsList(node.Methods, astFile, fileSet)
 
+	for _, method := range methods {
+		method.IsEmbedded = method.Name == ""
+	}
+
 	return &InterfaceSpec{
 		Fields: methods,
 	}
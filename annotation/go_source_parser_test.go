@@ -3919,6 +3919,69 @@ type typeName *valueSpecPackageName.valueSpec
 	ctrl.AssertEqual(expected, actual.TypeGroups[0].Types[0].Spec)
 }
 
+func TestSourceParser_Parse_WithPointerSpec(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fileName := "fileName"
+	fileContent := `package filePackageName
+
+type typeName **[]valueSpec
+`
+	expected := &PointerSpec{
+		Value: &PointerSpec{
+			Value: &ArraySpec{
+				Value: &SimpleSpec{
+					TypeName: "valueSpec",
+				},
+			},
+		},
+	}
+
+	annotationParser := NewAnnotationParserMock(ctrl)
+
+	parser := &GoSourceParser{
+		annotationParser: annotationParser,
+	}
+
+	actual := parser.Parse(fileName, fileContent)
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertNotEmpty(actual.TypeGroups)
+	ctrl.AssertNotNil(actual.TypeGroups[0])
+	ctrl.AssertNotEmpty(actual.TypeGroups[0].Types)
+	ctrl.AssertNotNil(actual.TypeGroups[0].Types[0])
+	ctrl.AssertNotNil(actual.TypeGroups[0].Types[0].Spec)
+	ctrl.AssertEqual(expected, actual.TypeGroups[0].Types[0].Spec)
+}
+
+func TestSourceParser_Parse_WithPointerSpecOfPointer(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fileName := "fileName"
+	fileContent := `package filePackageName
+
+type typeName **valueSpec
+`
+	expected := &PointerSpec{
+		Value: &SimpleSpec{
+			TypeName:  "valueSpec",
+			IsPointer: true,
+		},
+	}
+
+	annotationParser := NewAnnotationParserMock(ctrl)
+
+	parser := &GoSourceParser{
+		annotationParser: annotationParser,
+	}
+
+	actual := parser.Parse(fileName, fileContent)
+
+	ctrl.AssertEqual(expected, actual.TypeGroups[0].Types[0].Spec)
+}
+
 func TestSourceParser_Parse_WithArraySpec(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
@@ -4108,6 +4171,49 @@ type typeName struct {
 	)
 }
 
+func TestSourceParser_Parse_WithStructSpecAndLineComments(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fileName := "fileName"
+	fileContent := `package filePackageName
+
+type typeName struct {
+	// first
+	first int // line of first
+
+	second, third int // line of second and third
+
+	// free-floating
+
+	fourth int
+}
+`
+	expected := &StructSpec{
+		Fields: []*Field{
+			{Name: "first", Comment: "first", Spec: &SimpleSpec{TypeName: "int"}},
+			{Name: "second", Spec: &SimpleSpec{TypeName: "int"}},
+			{Name: "third", Spec: &SimpleSpec{TypeName: "int"}},
+			{Name: "fourth", Spec: &SimpleSpec{TypeName: "int"}},
+		},
+	}
+
+	annotationParser := NewAnnotationParserMock(ctrl)
+
+	annotationParser.
+		EXPECT().
+		Parse("first").
+		Return(nil)
+
+	parser := &GoSourceParser{
+		annotationParser: annotationParser,
+	}
+
+	actual := parser.Parse(fileName, fileContent)
+
+	ctrl.AssertEqual(expected, actual.TypeGroups[0].Types[0].Spec)
+}
+
 func TestSourceParser_Parse_WithStructSpec(t *testing.T) {
 	ctrl := unit.NewController(t)
 	defer ctrl.Finish()
@@ -4125,6 +4231,7 @@ type typeName struct {
 				Spec: &SimpleSpec{
 					TypeName: "fieldType",
 				},
+				IsEmbedded: true,
 			},
 		},
 	}
@@ -4330,6 +4437,7 @@ type typeName interface {
 				Spec: &SimpleSpec{
 					TypeName: "fieldName",
 				},
+				IsEmbedded: true,
 			},
 		},
 	}
@@ -4377,6 +4485,7 @@ type typeName interface {
 				Spec: &SimpleSpec{
 					TypeName: "fieldName",
 				},
+				IsEmbedded: true,
 			},
 		},
 	}
@@ -4946,3 +5055,78 @@ func TestSourceParser_parseSpec_WithUnknownExpressionType(t *testing.T) {
 			NewErrorMessageConstraint("Variable 'expression' has not allowed type: *ast.BadExpr"),
 		)
 }
+
+func TestGoSourceParser_SetLayoutKept(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	parser := &GoSourceParser{}
+
+	parser.SetLayoutKept(true)
+
+	ctrl.AssertTrue(parser.isLayoutKept)
+}
+
+func TestSourceParser_Parse_WithLayout(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fileName := "fileName"
+	fileContent := "// +build tag\n\n" +
+		"package filePackageName\n\n" +
+		"import \"fmt\"\n\n" +
+		"// free-floating comment\n\n" +
+		"// Model comment\n" +
+		"type Model struct{}\n\n" +
+		"func Print() { fmt.Print() }\n"
+	expected := &FileLayout{
+		Header:      "// +build tag\n\npackage filePackageName",
+		PackageName: "filePackageName",
+		Declarations: []*DeclarationLayout{
+			{
+				Kind:    "import",
+				Leading: "\n\n",
+				Source:  "import \"fmt\"",
+				Original: &ImportGroup{
+					Imports: []*Import{{Namespace: "fmt"}},
+				},
+			},
+			{
+				Kind:    "type",
+				Leading: "\n\n// free-floating comment\n\n",
+				Source:  "// Model comment\ntype Model struct{}",
+				Original: &TypeGroup{
+					Comment:     "Model comment",
+					Annotations: []interface{}{},
+					Types: []*Type{
+						{
+							Name: "Model",
+							Spec: &StructSpec{Fields: []*Field{}},
+						},
+					},
+				},
+			},
+			{
+				Kind:    "func",
+				Leading: "\n\n",
+				Source:  "func Print() { fmt.Print() }",
+				Original: &Func{
+					Name:    "Print",
+					Spec:    &FuncSpec{Params: []*Field{}, Results: []*Field{}},
+					Content: "fmt.Print()",
+				},
+			},
+		},
+		Trailer: "\n",
+	}
+
+	parser := &GoSourceParser{
+		annotationParser: NewJSONAnnotationParser(),
+		isLayoutKept:     true,
+	}
+
+	actual := parser.Parse(fileName, fileContent)
+
+	ctrl.AssertEqual(expected, actual.Layout)
+	ctrl.AssertNotSame(actual.TypeGroups[0], actual.Layout.Declarations[1].Original)
+}
@@ -0,0 +1,338 @@
+package annotation
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Scans golang sources by another scanner and type checks each scanned namespace by go/types.
+// Resolved type information is stored in TypesInfo, File models are the same as created by inner scanner.
+// Inner scanner must create File models by GoSourceParser, because entities are linked to AST in the same order.
+type GoTypesScanner struct {
+	scanner     Scanner
+	typesInfo   *TypesInfo
+	importer    types.Importer
+	specBuilder *TypesSpecBuilder
+}
+
+// Creates new instance of GoTypesScanner.
+func NewGoTypesScanner(
+	scanner Scanner,
+	typesInfo *TypesInfo,
+	importer types.Importer,
+	specBuilder *TypesSpecBuilder,
+) *GoTypesScanner {
+	if scanner == nil {
+		panic(errors.New("Variable 'scanner' must be not nil"))
+	}
+
+	if typesInfo == nil {
+		panic(errors.New("Variable 'typesInfo' must be not nil"))
+	}
+
+	if importer == nil {
+		panic(errors.New("Variable 'importer' must be not nil"))
+	}
+
+	if specBuilder == nil {
+		panic(errors.New("Variable 'specBuilder' must be not nil"))
+	}
+
+	return &GoTypesScanner{
+		scanner:     scanner,
+		typesInfo:   typesInfo,
+		importer:    importer,
+		specBuilder: specBuilder,
+	}
+}
+
+// Scans sources by inner scanner and type checks not ignored namespaces, which got new files, including new namespaces.
+// Namespaces are checked in order of their imports, so imported namespace is checked once and its objects are shared.
+// Type errors are not fatal, they are collected in TypesInfo.Errors.
+func (s *GoTypesScanner) Scan(storage *Storage, rootNamespace string, rootPath string, ignores ...string) {
+	if storage == nil {
+		panic(errors.New("Variable 'storage' must be not nil"))
+	}
+
+	scannedFiles := map[*File]bool{}
+
+	for _, namespace := range storage.Namespaces {
+		for _, file := range namespace.Files {
+			scannedFiles[file] = true
+		}
+	}
+
+	s.scanner.Scan(storage, rootNamespace, rootPath, ignores...)
+
+	importer := &typesScannerImporter{
+		scanner:  s,
+		fileSet:  token.NewFileSet(),
+		packages: map[string]*typesScannerPackage{},
+		order:    []string{},
+	}
+
+	for _, namespace := range storage.Namespaces {
+		if namespace.IsIgnored || namespace.IsReadOnly || !s.hasNewFiles(namespace, scannedFiles) {
+			continue
+		}
+
+		// All files of changed namespace are checked again, because they are one package
+		importer.addNamespace(namespace)
+	}
+
+	for _, path := range importer.order {
+		importer.check(path)
+	}
+}
+
+func (s *GoTypesScanner) hasNewFiles(namespace *Namespace, scannedFiles map[*File]bool) bool {
+	for _, file := range namespace.Files {
+		if !scannedFiles[file] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Files of one package in one namespace, which are not checked yet.
+type typesScannerPackage struct {
+	astFiles   []*ast.File
+	files      []*File
+	isChecking bool
+}
+
+// Importer, which type checks scanned namespaces before their importers and uses inner importer for others.
+type typesScannerImporter struct {
+	scanner  *GoTypesScanner
+	fileSet  *token.FileSet
+	packages map[string]*typesScannerPackage
+	order    []string
+}
+
+func (i *typesScannerImporter) Import(path string) (*types.Package, error) {
+	return i.ImportFrom(path, "", 0)
+}
+
+func (i *typesScannerImporter) ImportFrom(path string, dir string, mode types.ImportMode) (*types.Package, error) {
+	if pkg, ok := i.packages[path]; ok && pkg.isChecking {
+		return nil, errors.Errorf("Import cycle via package '%s'", path)
+	}
+
+	if _, ok := i.packages[path]; ok {
+		i.check(path)
+	}
+
+	if pkg := i.scanner.typesInfo.Packages[path]; pkg != nil {
+		return pkg, nil
+	}
+
+	if importerFrom, ok := i.scanner.importer.(types.ImporterFrom); ok {
+		return importerFrom.ImportFrom(path, dir, mode)
+	}
+
+	return i.scanner.importer.Import(path)
+}
+
+// Files with different package names (e.g. external tests) are checked as different packages.
+func (i *typesScannerImporter) addNamespace(namespace *Namespace) {
+	for _, file := range namespace.Files {
+		astFile, err := parser.ParseFile(
+			i.fileSet,
+			filepath.Join(namespace.Path, file.Name),
+			file.Content,
+			parser.ParseComments,
+		)
+
+		if err != nil {
+			panic(err)
+		}
+
+		path := namespace.Name
+
+		if strings.HasSuffix(file.PackageName, "_test") {
+			path += "_test"
+		}
+
+		if _, ok := i.packages[path]; !ok {
+			i.packages[path] = &typesScannerPackage{}
+			i.order = append(i.order, path)
+		}
+
+		i.packages[path].astFiles = append(i.packages[path].astFiles, astFile)
+		i.packages[path].files = append(i.packages[path].files, file)
+	}
+}
+
+// Type checks package, if it's not checked yet.
+func (i *typesScannerImporter) check(path string) {
+	pkg, ok := i.packages[path]
+
+	if !ok {
+		return
+	}
+
+	pkg.isChecking = true
+
+	info := &types.Info{
+		Defs: map[*ast.Ident]types.Object{},
+	}
+	config := &types.Config{
+		Importer: i,
+		Error: func(err error) {
+			i.scanner.typesInfo.Errors = append(i.scanner.typesInfo.Errors, err)
+		},
+	}
+
+	// Errors are collected by config.Error
+	checkedPackage, _ := config.Check(path, i.fileSet, pkg.astFiles, info)
+
+	delete(i.packages, path)
+
+	i.scanner.typesInfo.Packages[path] = checkedPackage
+
+	for j, astFile := range pkg.astFiles {
+		i.scanner.linkFile(checkedPackage, info, pkg.files[j], astFile)
+	}
+}
+
+func (s *GoTypesScanner) linkFile(pkg *types.Package, info *types.Info, file *File, astFile *ast.File) {
+	constGroupIndex, varGroupIndex, typeGroupIndex, funcIndex := 0, 0, 0, 0
+
+	for _, node := range astFile.Decls {
+		switch decl := node.(type) {
+		case *ast.GenDecl:
+			switch decl.Tok {
+			case token.CONST:
+				s.linkValues(pkg, info, decl, func(i int) interface{} {
+					return file.ConstGroups[constGroupIndex].Consts[i]
+				})
+				constGroupIndex++
+			case token.VAR:
+				s.linkValues(pkg, info, decl, func(i int) interface{} {
+					return file.VarGroups[varGroupIndex].Vars[i]
+				})
+				varGroupIndex++
+			case token.TYPE:
+				s.linkTypes(pkg, info, decl, file.TypeGroups[typeGroupIndex])
+				typeGroupIndex++
+			}
+		case *ast.FuncDecl:
+			s.linkObject(pkg, file.Funcs[funcIndex], info.Defs[decl.Name])
+			funcIndex++
+		}
+	}
+}
+
+func (s *GoTypesScanner) linkValues(
+	pkg *types.Package,
+	info *types.Info,
+	decl *ast.GenDecl,
+	entity func(i int) interface{},
+) {
+	i := 0
+
+	for _, spec := range decl.Specs {
+		// This row is expected to be called with value spec
+		valueSpec, _ := spec.(*ast.ValueSpec)
+
+		for _, name := range valueSpec.Names {
+			s.linkObject(pkg, entity(i), info.Defs[name])
+			i++
+		}
+	}
+}
+
+func (s *GoTypesScanner) linkTypes(pkg *types.Package, info *types.Info, decl *ast.GenDecl, typeGroup *TypeGroup) {
+	for i, spec := range decl.Specs {
+		// This row is expected to be called with type spec
+		typeSpec, _ := spec.(*ast.TypeSpec)
+		entity := typeGroup.Types[i]
+
+		s.linkObject(pkg, entity, info.Defs[typeSpec.Name])
+
+		if structType, ok := typeSpec.Type.(*ast.StructType); ok {
+			s.linkFields(pkg, info, structType, entity.Spec.(*StructSpec))
+		}
+	}
+}
+
+func (s *GoTypesScanner) linkFields(pkg *types.Package, info *types.Info, node *ast.StructType, spec *StructSpec) {
+	i := 0
+
+	for _, astField := range node.Fields.List {
+		names := astField.Names
+
+		if len(names) == 0 {
+			names = []*ast.Ident{s.embeddedIdent(astField.Type)}
+		}
+
+		for _, name := range names {
+			field := spec.Fields[i]
+			i++
+
+			if name != nil {
+				s.linkObject(pkg, field, info.Defs[name])
+			}
+
+			if structType, ok := astField.Type.(*ast.StructType); ok {
+				s.linkFields(pkg, info, structType, field.Spec.(*StructSpec))
+			}
+		}
+	}
+}
+
+func (s *GoTypesScanner) embeddedIdent(node ast.Expr) *ast.Ident {
+	switch node := node.(type) {
+	case *ast.Ident:
+		return node
+	case *ast.SelectorExpr:
+		return node.Sel
+	case *ast.StarExpr:
+		return s.embeddedIdent(node.X)
+	default:
+		return nil
+	}
+}
+
+func (s *GoTypesScanner) linkObject(pkg *types.Package, entity interface{}, object types.Object) {
+	if object == nil {
+		return
+	}
+
+	result := &EntityTypeInfo{
+		Object:         object,
+		Type:           object.Type(),
+		UnderlyingSpec: s.specBuilder.build(pkg, object.Type().Underlying(), &[]*Import{}),
+	}
+
+	switch object := object.(type) {
+	case *types.Const:
+		result.ConstValue = object.Val().ExactString()
+	case *types.TypeName:
+		result.Methods = s.buildMethods(pkg, types.NewMethodSet(object.Type()))
+		result.PointerMethods = s.buildMethods(pkg, types.NewMethodSet(types.NewPointer(object.Type())))
+	}
+
+	s.typesInfo.Set(entity, result)
+}
+
+func (s *GoTypesScanner) buildMethods(pkg *types.Package, methodSet *types.MethodSet) []*Field {
+	result := make([]*Field, 0, methodSet.Len())
+
+	for i := 0; i < methodSet.Len(); i++ {
+		method := methodSet.At(i).Obj()
+
+		if spec, ok := s.specBuilder.build(pkg, method.Type(), &[]*Import{}).(*FuncSpec); ok {
+			result = append(result, &Field{Name: method.Name(), Spec: spec})
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,309 @@
+package annotation
+
+import (
+	"go/importer"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestNewGoTypesScanner(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	scanner := NewScannerMock(ctrl)
+	typesInfo := NewTypesInfo()
+	typesImporter := importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup(""))
+	specBuilder := NewTypesSpecBuilder()
+
+	actual := NewGoTypesScanner(scanner, typesInfo, typesImporter, specBuilder)
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(scanner, actual.scanner)
+	ctrl.AssertSame(typesInfo, actual.typesInfo)
+	ctrl.AssertSame(typesImporter, actual.importer)
+	ctrl.AssertSame(specBuilder, actual.specBuilder)
+}
+
+func TestNewGoTypesScanner_WithNilScanner(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(
+			NewGoTypesScanner,
+			nil,
+			NewTypesInfo(),
+			importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup("")),
+			NewTypesSpecBuilder(),
+		).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'scanner' must be not nil"))
+}
+
+func TestNewGoTypesScanner_WithNilTypesInfo(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(
+			NewGoTypesScanner,
+			NewScannerMock(ctrl),
+			nil,
+			importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup("")),
+			NewTypesSpecBuilder(),
+		).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'typesInfo' must be not nil"))
+}
+
+func TestNewGoTypesScanner_WithNilImporter(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewGoTypesScanner, NewScannerMock(ctrl), NewTypesInfo(), nil, NewTypesSpecBuilder()).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'importer' must be not nil"))
+}
+
+func TestNewGoTypesScanner_WithNilSpecBuilder(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(
+			NewGoTypesScanner,
+			NewScannerMock(ctrl),
+			NewTypesInfo(),
+			importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup("")),
+			nil,
+		).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'specBuilder' must be not nil"))
+}
+
+func TestGoTypesScanner_Scan(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := `package model
+
+import "time"
+
+const Timeout = 2 * time.Second
+
+var Default = &Model{}
+
+// Model comment
+type Model struct {
+	ID int
+	time.Time
+}
+
+func (m *Model) Error() string {
+	return ""
+}
+`
+	sourceParser := NewGoSourceParser(NewJSONAnnotationParser())
+	file := sourceParser.Parse("model.go", content)
+	storage := &Storage{
+		Namespaces: []*Namespace{
+			{Name: "exists", Path: "/exists"},
+		},
+	}
+	scanner := NewScannerMock(ctrl)
+	typesInfo := NewTypesInfo()
+
+	scanner.EXPECT().Scan(storage, "example.com/model", "/path").Callback(
+		func(storage *Storage, rootNamespace string, rootPath string, ignores ...string) {
+			storage.Namespaces = append(
+				storage.Namespaces,
+				&Namespace{Name: "example.com/model", Path: "/path", Files: []*File{file}},
+				&Namespace{Name: "example.com/model/ignored", Path: "/path/ignored", IsIgnored: true},
+			)
+		},
+	)
+
+	NewGoTypesScanner(
+		scanner,
+		typesInfo,
+		importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup("")),
+		NewTypesSpecBuilder(),
+	).Scan(storage, "example.com/model", "/path")
+
+	ctrl.AssertEqual([]error{}, typesInfo.Errors)
+	ctrl.AssertSame(1, len(typesInfo.Packages))
+	ctrl.AssertSame("model", typesInfo.Packages["example.com/model"].Name())
+
+	constInfo := typesInfo.Find(file.ConstGroups[0].Consts[0])
+
+	ctrl.AssertSame("2000000000", constInfo.ConstValue)
+	ctrl.AssertEqual(&SimpleSpec{TypeName: "int64"}, constInfo.UnderlyingSpec)
+	ctrl.AssertSame("time.Duration", constInfo.Type.String())
+
+	varInfo := typesInfo.Find(file.VarGroups[0].Vars[0])
+
+	ctrl.AssertEqual(&SimpleSpec{TypeName: "Model", IsPointer: true}, varInfo.UnderlyingSpec)
+
+	modelType := file.TypeGroups[0].Types[0]
+	typeInfo := typesInfo.Find(modelType)
+
+	ctrl.AssertSame("Model", typeInfo.Object.Name())
+	ctrl.AssertSame(len(modelType.Spec.(*StructSpec).Fields), len(typeInfo.UnderlyingSpec.(*StructSpec).Fields))
+	ctrl.AssertEqual(
+		&SimpleSpec{TypeName: "int"},
+		typesInfo.Find(modelType.Spec.(*StructSpec).Fields[0]).UnderlyingSpec,
+	)
+	ctrl.AssertSame("Time", typesInfo.Find(modelType.Spec.(*StructSpec).Fields[1]).Object.Name())
+
+	errorInterface := types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+	ctrl.AssertTrue(typesInfo.Implements(modelType, errorInterface))
+	ctrl.AssertFalse(typesInfo.Implements(file.ConstGroups[0].Consts[0], errorInterface))
+
+	var hasError bool
+
+	for _, method := range typeInfo.PointerMethods {
+		if method.Name == "Error" {
+			hasError = true
+		}
+	}
+
+	ctrl.AssertTrue(hasError)
+
+	for _, method := range typeInfo.Methods {
+		ctrl.AssertNotSame("Error", method.Name)
+	}
+
+	ctrl.AssertSame("Error", typesInfo.Find(file.Funcs[0]).Object.Name())
+}
+
+func TestGoTypesScanner_Scan_WithTypeError(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	file := NewGoSourceParser(NewJSONAnnotationParser()).Parse("model.go", "package model\n\nvar X Unknown\n")
+	storage := &Storage{}
+	scanner := NewScannerMock(ctrl)
+	typesInfo := NewTypesInfo()
+
+	scanner.EXPECT().Scan(storage, "model", "/path").Callback(
+		func(storage *Storage, rootNamespace string, rootPath string, ignores ...string) {
+			storage.Namespaces = append(
+				storage.Namespaces,
+				&Namespace{Name: "model", Path: "/path", Files: []*File{file}},
+			)
+		},
+	)
+
+	NewGoTypesScanner(
+		scanner,
+		typesInfo,
+		importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup("")),
+		NewTypesSpecBuilder(),
+	).Scan(storage, "model", "/path")
+
+	ctrl.AssertSame(1, len(typesInfo.Errors))
+	ctrl.AssertNotNil(typesInfo.Packages["model"])
+}
+
+func TestGoTypesScanner_Scan_WithNewFileOfScannedNamespace(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	sourceParser := NewGoSourceParser(NewJSONAnnotationParser())
+	scannedFile := sourceParser.Parse("a.go", "package a\n\ntype Model struct{}\n")
+	newFile := sourceParser.Parse("b.go", "package a\n\nvar X Model\n")
+	otherFile := sourceParser.Parse("c.go", "package c\n\nvar Y int\n")
+	namespace := &Namespace{Name: "example.com/a", Path: "/path/a", Files: []*File{scannedFile}}
+	otherNamespace := &Namespace{Name: "example.com/c", Path: "/path/c", Files: []*File{otherFile}}
+	storage := &Storage{Namespaces: []*Namespace{namespace, otherNamespace}}
+	scanner := NewScannerMock(ctrl)
+	typesInfo := NewTypesInfo()
+
+	scanner.EXPECT().Scan(storage, "example.com", "/path").Callback(
+		func(storage *Storage, rootNamespace string, rootPath string, ignores ...string) {
+			namespace.Files = append(namespace.Files, newFile)
+		},
+	)
+
+	NewGoTypesScanner(
+		scanner,
+		typesInfo,
+		importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup("")),
+		NewTypesSpecBuilder(),
+	).Scan(storage, "example.com", "/path")
+
+	modelObject := typesInfo.Find(scannedFile.TypeGroups[0].Types[0]).Object
+
+	ctrl.AssertEqual([]error{}, typesInfo.Errors)
+	ctrl.AssertSame(modelObject, typesInfo.Find(newFile.VarGroups[0].Vars[0]).Type.(*types.Named).Obj())
+	ctrl.AssertNil(typesInfo.Packages["example.com/c"])
+}
+
+func TestGoTypesScanner_Scan_WithImportedNamespace(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	sourceParser := NewGoSourceParser(NewJSONAnnotationParser())
+	importerFile := sourceParser.Parse("b.go", "package b\n\nimport \"example.com/a\"\n\nvar X a.Model\n")
+	importedFile := sourceParser.Parse("a.go", "package a\n\ntype Model struct{}\n")
+	storage := &Storage{}
+	scanner := NewScannerMock(ctrl)
+	typesInfo := NewTypesInfo()
+
+	scanner.EXPECT().Scan(storage, "example.com", "/path").Callback(
+		func(storage *Storage, rootNamespace string, rootPath string, ignores ...string) {
+			storage.Namespaces = append(
+				storage.Namespaces,
+				&Namespace{Name: "example.com/b", Path: "/path/b", Files: []*File{importerFile}},
+				&Namespace{Name: "example.com/a", Path: "/path/a", Files: []*File{importedFile}},
+			)
+		},
+	)
+
+	NewGoTypesScanner(
+		scanner,
+		typesInfo,
+		importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup("")),
+		NewTypesSpecBuilder(),
+	).Scan(storage, "example.com", "/path")
+
+	modelObject := typesInfo.Find(importedFile.TypeGroups[0].Types[0]).Object
+
+	ctrl.AssertEqual([]error{}, typesInfo.Errors)
+	ctrl.AssertSame(typesInfo.Packages["example.com/a"], modelObject.Pkg())
+	ctrl.AssertSame(modelObject, typesInfo.Find(importerFile.VarGroups[0].Vars[0]).Type.(*types.Named).Obj())
+}
+
+func TestGoTypesScanner_Scan_WithImportCycle(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	sourceParser := NewGoSourceParser(NewJSONAnnotationParser())
+	aFile := sourceParser.Parse("a.go", "package a\n\nimport \"b\"\n\nvar X b.Model\n\ntype Model struct{}\n")
+	bFile := sourceParser.Parse("b.go", "package b\n\nimport \"a\"\n\nvar X a.Model\n\ntype Model struct{}\n")
+	storage := &Storage{}
+	scanner := NewScannerMock(ctrl)
+	typesInfo := NewTypesInfo()
+
+	scanner.EXPECT().Scan(storage, "", "/path").Callback(
+		func(storage *Storage, rootNamespace string, rootPath string, ignores ...string) {
+			storage.Namespaces = append(
+				storage.Namespaces,
+				&Namespace{Name: "a", Path: "/path/a", Files: []*File{aFile}},
+				&Namespace{Name: "b", Path: "/path/b", Files: []*File{bFile}},
+			)
+		},
+	)
+
+	NewGoTypesScanner(
+		scanner,
+		typesInfo,
+		importer.ForCompiler(token.NewFileSet(), "gc", NewExportDataLookup("")),
+		NewTypesSpecBuilder(),
+	).Scan(storage, "", "/path")
+
+	ctrl.AssertNotEmpty(typesInfo.Errors)
+	ctrl.AssertNotNil(typesInfo.Packages["a"])
+	ctrl.AssertNotNil(typesInfo.Packages["b"])
+}
@@ -0,0 +1,10 @@
+package annotation
+
+// Implementation represents type, which implements interface.
+type Implementation struct {
+	Namespace *Namespace
+	File      *File
+	Type      *Type
+	// Only pointer to type implements interface.
+	IsPointer bool
+}
@@ -2,6 +2,7 @@ package annotation
 
 import (
 	"path/filepath"
+	"strconv"
 )
 
 // Import represents import declaration.
@@ -20,3 +21,20 @@ func (m *Import) RealAlias() string {
 
 	return filepath.Base(m.Namespace)
 }
+
+// Returns package name, if it's not used by imports, otherwise package name with the first free numeric suffix.
+func uniqueAlias(imports []*Import, packageName string) string {
+	isUsed := map[string]bool{}
+
+	for _, element := range imports {
+		isUsed[element.RealAlias()] = true
+	}
+
+	result := packageName
+
+	for i := 2; isUsed[result]; i++ {
+		result = packageName + "_" + strconv.Itoa(i)
+	}
+
+	return result
+}
@@ -1,5 +1,10 @@
 package annotation
 
+import (
+	"go/ast"
+	"go/token"
+)
+
 type Cloner interface {
 	Clone(interface{}) interface{}
 }
@@ -38,6 +43,86 @@ type Generator interface {
 	Generate(application *Application)
 }
 
+// Generator, which receives run context instead of application.
+type ContextGenerator interface {
+	Generator
+	GenerateWithContext(context *GeneratorContext)
+}
+
+// Generator with hook, which is called after scan, e.g. to validate annotations.
+type AfterScanGenerator interface {
+	Generator
+	AfterScan(application *Application)
+}
+
+// Generator with hook, which is called after cleaning before the first generator is run.
+type BeforeGenerateGenerator interface {
+	Generator
+	BeforeGenerate(application *Application)
+}
+
+// Generator with hook, which is called for each rendered file before it's written, returned content is written.
+type BeforeWriteGenerator interface {
+	Generator
+	BeforeWrite(application *Application, namespace *Namespace, file *File, content string) string
+}
+
+// Generator with hook, which is called after all files are written.
+type AfterWriteGenerator interface {
+	Generator
+	AfterWrite(application *Application)
+}
+
+// Generator, which options from config are decoded into value returned by ConfigOptions, it must be pointer.
+type ConfigurableGenerator interface {
+	Generator
+	ConfigOptions() interface{}
+}
+
+// Generator with unique name, so other generators could depend on it.
+type NamedGenerator interface {
+	Generator
+	Name() string
+}
+
+// Generator with version, it's written into header of generated files together with name.
+type VersionedGenerator interface {
+	Generator
+	Version() string
+}
+
+// Generator with own header of generated files, it's used instead of default Header. Header should contain standard
+// "// Code generated ... DO NOT EDIT." marker, so generated files are recognized by go tools and cleaner, otherwise
+// GeneratedMarker is prepended to it.
+type HeaderGenerator interface {
+	Generator
+	Header() string
+}
+
+// Generator, which must be run after generators with names from Dependencies.
+type DependentGenerator interface {
+	Generator
+	Dependencies() []string
+}
+
+// Generator, which only reads storage or only writes into its own files, so it could be run concurrently with other
+// concurrent generators.
+type ConcurrentGenerator interface {
+	Generator
+	IsConcurrent() bool
+}
+
+// Edits declarations of hand-written file in its content, returns new content and names of changed declarations.
+type FileEditor interface {
+	Edit(file *File) (string, []string)
+}
+
+// Resolves files with the same name in one namespace, which are created by different generators.
+type FileConflictResolver interface {
+	Policy() string
+	Resolve(storage *Storage) []*FileConflict
+}
+
 type ImportFetcher interface {
 	Fetch(file *File, entity interface{}) []*Import
 }
@@ -57,3 +142,64 @@ type Equaler interface {
 type ContainsChecker interface {
 	Contains(collection interface{}, entity interface{}) bool
 }
+
+type NamespaceResolver interface {
+	Resolve(storage *Storage, namespace string) *Namespace
+	ResolveImports(storage *Storage)
+}
+
+type ASTConverter interface {
+	ToAST(fileSet *token.FileSet, entity interface{}) ast.Node
+	FromAST(fileSet *token.FileSet, node ast.Node) interface{}
+}
+
+type MethodSetFetcher interface {
+	Fetch(storage *Storage, reference *TypeReference, isPointer bool) []*Method
+	FetchFields(storage *Storage, reference *TypeReference) []*PromotedField
+}
+
+type ImplementationFinder interface {
+	Find(storage *Storage, file *File, spec interface{}) []*Implementation
+}
+
+type Visitor interface {
+	Enter(entity interface{}, parents []interface{}) bool
+	Leave(entity interface{}, parents []interface{})
+}
+
+type Walker interface {
+	Walk(entity interface{}, visitor Visitor)
+	Inspect(entity interface{}, callback func(entity interface{}, parents []interface{}) bool)
+}
+
+type ContextIndexer interface {
+	Index(storage *Storage, context *EntityContext)
+	Update(entity interface{}, context *EntityContext)
+}
+
+type Transformer interface {
+	Apply(entity interface{}, pre ApplyFunc, post ApplyFunc) interface{}
+}
+
+type AnnotationFinder interface {
+	Find(entity interface{}, annotationType interface{}) []interface{}
+	FindFirst(entity interface{}, annotationType interface{}) interface{}
+}
+
+type Selector interface {
+	Select(storage *Storage, selector string) ([]*SelectedEntity, error)
+}
+
+// Structured logger, fields are pairs of key and value.
+type Logger interface {
+	Debug(message string, fields ...interface{})
+	Info(message string, fields ...interface{})
+	Warning(message string, fields ...interface{})
+	Error(message string, fields ...interface{})
+	With(fields ...interface{}) Logger
+}
+
+type ConfigLoader interface {
+	Find(folder string) string
+	Load(path string) *Config
+}
@@ -12,6 +12,7 @@ var jsonAnnotationRegexp = regexp.MustCompile(`(?mU)^@([\p{L}_][\p{L}\d_]*)\(((.
 
 var protectedAnnotations = map[string]interface{}{
 	"FileIsGenerated": FileIsGeneratedAnnotation(false),
+	"FileGeneratedBy": FileGeneratedByAnnotation{},
 }
 
 // Parsers comment and creates list of annotations.
@@ -13,6 +13,7 @@ func TestNewJSONAnnotationParser(t *testing.T) {
 
 	annotations := map[string]interface{}{
 		"FileIsGenerated": FileIsGeneratedAnnotation(false),
+		"FileGeneratedBy": FileGeneratedByAnnotation{},
 	}
 
 	actual := NewJSONAnnotationParser()
@@ -33,12 +34,14 @@ func TestJSONAnnotationParser_SetAnnotation(t *testing.T) {
 	parser := &JSONAnnotationParser{
 		annotations: map[string]interface{}{
 			"FileIsGenerated": FileIsGeneratedAnnotation(false),
+			"FileGeneratedBy": FileGeneratedByAnnotation{},
 		},
 	}
 
 	expectedParser := &JSONAnnotationParser{
 		annotations: map[string]interface{}{
 			"FileIsGenerated": FileIsGeneratedAnnotation(false),
+			"FileGeneratedBy": FileGeneratedByAnnotation{},
 			name:              annotation,
 		},
 	}
@@ -64,6 +67,7 @@ func TestJSONAnnotationParser_SetAnnotation_WithUpdate(t *testing.T) {
 	parser := &JSONAnnotationParser{
 		annotations: map[string]interface{}{
 			"FileIsGenerated": FileIsGeneratedAnnotation(false),
+			"FileGeneratedBy": FileGeneratedByAnnotation{},
 			name:              annotation1,
 		},
 	}
@@ -71,6 +75,7 @@ func TestJSONAnnotationParser_SetAnnotation_WithUpdate(t *testing.T) {
 	expectedParser := &JSONAnnotationParser{
 		annotations: map[string]interface{}{
 			"FileIsGenerated": FileIsGeneratedAnnotation(false),
+			"FileGeneratedBy": FileGeneratedByAnnotation{},
 			name:              annotation2,
 		},
 	}
@@ -93,6 +98,7 @@ func TestJSONAnnotationParser_SetAnnotation_WithEmptyName(t *testing.T) {
 	parser := &JSONAnnotationParser{
 		annotations: map[string]interface{}{
 			"FileIsGenerated": FileIsGeneratedAnnotation(false),
+			"FileGeneratedBy": FileGeneratedByAnnotation{},
 		},
 	}
 
@@ -113,6 +119,7 @@ func TestJSONAnnotationParser_SetAnnotation_WithProtectedAnnotationName(t *testi
 	parser := &JSONAnnotationParser{
 		annotations: map[string]interface{}{
 			"FileIsGenerated": FileIsGeneratedAnnotation(false),
+			"FileGeneratedBy": FileGeneratedByAnnotation{},
 		},
 	}
 
@@ -0,0 +1,94 @@
+package annotation
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// Finds and loads project config in JSON format.
+type JSONConfigLoader struct {
+}
+
+// Creates new instance of JSONConfigLoader.
+func NewJSONConfigLoader() *JSONConfigLoader {
+	return &JSONConfigLoader{}
+}
+
+// Returns path of ConfigFileName in folder or in the nearest of its parents, or empty string if it's not found.
+func (l *JSONConfigLoader) Find(folder string) string {
+	if folder == "" {
+		panic(errors.New("Variable 'folder' must be not empty"))
+	}
+
+	folder, err := filepath.Abs(folder)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for {
+		path := filepath.Join(folder, ConfigFileName)
+
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+
+		parent := filepath.Dir(folder)
+
+		if parent == folder {
+			return ""
+		}
+
+		folder = parent
+	}
+}
+
+// Loads config from file, relative paths of roots are converted to absolute.
+func (l *JSONConfigLoader) Load(path string) *Config {
+	if path == "" {
+		panic(errors.New("Variable 'path' must be not empty"))
+	}
+
+	path, err := filepath.Abs(path)
+
+	if err != nil {
+		panic(err)
+	}
+
+	content, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		panic(err)
+	}
+
+	result := &Config{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(result); err != nil {
+		panic(errors.Wrapf(err, "Can't parse config file '%s'", path))
+	}
+
+	if len(result.Roots) == 0 {
+		panic(errors.Errorf("Config file '%s' must have roots", path))
+	}
+
+	for i, root := range result.Roots {
+		if root == nil || root.Path == "" {
+			panic(errors.Errorf("Config file '%s' has root with empty path, index: %d", path, i))
+		}
+
+		if !filepath.IsAbs(root.Path) {
+			root.Path = filepath.Join(filepath.Dir(path), root.Path)
+		}
+	}
+
+	result.Path = path
+
+	return result
+}
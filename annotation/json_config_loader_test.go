@@ -0,0 +1,110 @@
+package annotation
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestJSONConfigLoader_Find(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fs := NewTmpFS(ctrl).
+		CreateDir("project", 0777).
+		CreateDir("project/internal", 0777).
+		CreateDir("project/internal/model", 0777).
+		CreateFile("project/"+ConfigFileName, 0666, "{}").
+		CreateDir("project/internal/"+ConfigFileName, 0777)
+
+	loader := NewJSONConfigLoader()
+
+	ctrl.AssertSame(
+		filepath.Join(fs.RootPath(), "project", ConfigFileName),
+		loader.Find(filepath.Join(fs.RootPath(), "project", "internal", "model")),
+	)
+	ctrl.AssertSame(
+		filepath.Join(fs.RootPath(), "project", ConfigFileName),
+		loader.Find(filepath.Join(fs.RootPath(), "project")),
+	)
+}
+
+func TestJSONConfigLoader_Find_WithEmptyFolder(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewJSONConfigLoader().Find, "").
+		ExpectPanic(NewErrorMessageConstraint("Variable 'folder' must be not empty"))
+}
+
+func TestJSONConfigLoader_Load(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := `{
+  "roots": [{"namespace": "example.com/project", "path": "."}, {"path": "/absolute"}],
+  "ignores": ["vendor"],
+  "buildTags": ["integration"],
+  "generators": ["mock"],
+  "options": {"mock": {"suffix": "Mock"}}
+}`
+	fs := NewTmpFS(ctrl).
+		CreateDir("project", 0777).
+		CreateFile("project/"+ConfigFileName, 0666, content)
+	path := filepath.Join(fs.RootPath(), "project", ConfigFileName)
+
+	expected := &Config{
+		Roots: []*ConfigRoot{
+			{Namespace: "example.com/project", Path: filepath.Join(fs.RootPath(), "project")},
+			{Path: "/absolute"},
+		},
+		Ignores:    []string{"vendor"},
+		BuildTags:  []string{"integration"},
+		Generators: []string{"mock"},
+		Options:    map[string]json.RawMessage{"mock": json.RawMessage(`{"suffix": "Mock"}`)},
+		Path:       path,
+	}
+
+	ctrl.AssertEqual(expected, NewJSONConfigLoader().Load(path))
+}
+
+func TestJSONConfigLoader_Load_WithInvalidConfig(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fs := NewTmpFS(ctrl).
+		CreateFile("unknown.json", 0666, `{"roots": [{"path": "."}], "unknown": true}`).
+		CreateFile("without_roots.json", 0666, `{}`).
+		CreateFile("empty_path.json", 0666, `{"roots": [{"namespace": "model"}]}`)
+	loader := NewJSONConfigLoader()
+
+	ctrl.Subtest("Unknown").
+		Call(loader.Load, filepath.Join(fs.RootPath(), "unknown.json")).
+		ExpectPanic(
+			NewErrorMessageConstraint(
+				"Can't parse config file '" + filepath.Join(fs.RootPath(), "unknown.json") +
+					"': json: unknown field \"unknown\"",
+			),
+		)
+	ctrl.Subtest("WithoutRoots").
+		Call(loader.Load, filepath.Join(fs.RootPath(), "without_roots.json")).
+		ExpectPanic(
+			NewErrorMessageConstraint(
+				"Config file '" + filepath.Join(fs.RootPath(), "without_roots.json") + "' must have roots",
+			),
+		)
+	ctrl.Subtest("EmptyPath").
+		Call(loader.Load, filepath.Join(fs.RootPath(), "empty_path.json")).
+		ExpectPanic(
+			NewErrorMessageConstraint(
+				"Config file '" + filepath.Join(fs.RootPath(), "empty_path.json") +
+					"' has root with empty path, index: 0",
+			),
+		)
+	ctrl.Subtest("EmptyArgument").
+		Call(loader.Load, "").
+		ExpectPanic(NewErrorMessageConstraint("Variable 'path' must be not empty"))
+}
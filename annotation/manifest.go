@@ -0,0 +1,102 @@
+package annotation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// Name of manifest file, it's written into each namespace with generated files.
+const ManifestFileName = ".go-annotation.manifest.json"
+
+// Manifest contains hashes of generated files of one namespace, so manual changes of them could be detected.
+type Manifest struct {
+	// Hashes of content by file name.
+	Files map[string]string `json:"files"`
+}
+
+func NewManifest() *Manifest {
+	return &Manifest{Files: map[string]string{}}
+}
+
+// Reads manifest from folder, returns empty manifest if it's absent.
+func ReadManifest(folder string) *Manifest {
+	content, err := ioutil.ReadFile(filepath.Join(folder, ManifestFileName))
+
+	if os.IsNotExist(err) {
+		return NewManifest()
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	result := NewManifest()
+
+	if err := json.Unmarshal(content, result); err != nil {
+		panic(errors.Wrapf(err, "Can't parse manifest file '%s'", filepath.Join(folder, ManifestFileName)))
+	}
+
+	if result.Files == nil {
+		result.Files = map[string]string{}
+	}
+
+	return result
+}
+
+// Writes manifest into folder, manifest file is removed if manifest is empty.
+func (m *Manifest) Write(folder string) {
+	path := filepath.Join(folder, ManifestFileName)
+
+	if len(m.Files) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			panic(err)
+		}
+
+		return
+	}
+
+	content, err := json.MarshalIndent(m, "", "  ")
+
+	if err != nil {
+		panic(err)
+	}
+
+	if err := ioutil.WriteFile(path, append(content, '\n'), 0666); err != nil {
+		panic(err)
+	}
+}
+
+// Sets hash of file content.
+func (m *Manifest) Set(name string, content string) {
+	m.Files[name] = m.hash(content)
+}
+
+// Checks that manifest contains hash of file, so file was written by generation.
+func (m *Manifest) Contains(name string) bool {
+	_, ok := m.Files[name]
+
+	return ok
+}
+
+func (m *Manifest) Remove(name string) {
+	delete(m.Files, name)
+}
+
+// Checks that file content differs from recorded one. Files, which are absent in manifest, are not modified.
+func (m *Manifest) IsModified(name string, content string) bool {
+	hash, ok := m.Files[name]
+
+	return ok && hash != m.hash(content)
+}
+
+func (m *Manifest) hash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
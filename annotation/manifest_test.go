@@ -0,0 +1,81 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestNewManifest(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.AssertEqual(&Manifest{Files: map[string]string{}}, NewManifest())
+}
+
+func TestReadManifest(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fs := NewTmpFS(ctrl).
+		CreateDir("empty", 0777).
+		CreateDir("full", 0777).
+		CreateFile("full/"+ManifestFileName, 0666, `{"files": {"file.go": "sha256:hash"}}`)
+
+	ctrl.AssertEqual(NewManifest(), ReadManifest(fs.RootPath()+"/empty"))
+	ctrl.AssertEqual(&Manifest{Files: map[string]string{"file.go": "sha256:hash"}}, ReadManifest(fs.RootPath()+"/full"))
+}
+
+func TestReadManifest_WithInvalidManifest(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fs := NewTmpFS(ctrl).CreateFile(ManifestFileName, 0666, "[]")
+
+	ctrl.Subtest("").
+		Call(ReadManifest, fs.RootPath()).
+		ExpectPanic(
+			NewErrorMessageConstraint(
+				"Can't parse manifest file '%s/%s': json: cannot unmarshal array into Go value of type "+
+					"annotation.Manifest",
+				fs.RootPath(),
+				ManifestFileName,
+			),
+		)
+}
+
+func TestManifest_Write(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	fs := NewTmpFS(ctrl)
+
+	manifest := NewManifest()
+	manifest.Set("file.go", "package model\n")
+	manifest.Write(fs.RootPath())
+
+	fs.AssertFileContent(
+		ManifestFileName,
+		"{\n  \"files\": {\n"+
+			"    \"file.go\": \"sha256:61d28c8e16b0f1913aed9c8c47c3a1e2a728ec1f9f1318ffa90ba0ecbac13e29\"\n"+
+			"  }\n}\n",
+	)
+	ctrl.AssertEqual(manifest, ReadManifest(fs.RootPath()))
+
+	manifest.Remove("file.go")
+	manifest.Write(fs.RootPath())
+
+	fs.AssertNotFileExists(ManifestFileName)
+}
+
+func TestManifest_IsModified(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	manifest := NewManifest()
+	manifest.Set("file.go", "package model\n")
+
+	ctrl.AssertFalse(manifest.IsModified("file.go", "package model\n"))
+	ctrl.AssertTrue(manifest.IsModified("file.go", "package model\n\nfunc Edited() {}\n"))
+	ctrl.AssertFalse(manifest.IsModified("unknown.go", "package model\n"))
+}
@@ -2,8 +2,8 @@ package annotation
 
 // MapSpec represents specification of map type.
 type MapSpec struct {
-	// Allowed types: *SimpleSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec.
+	// Allowed types: *SimpleSpec, *PointerSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec.
 	Key interface{}
-	// Allowed types: *SimpleSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec.
+	// Allowed types: *SimpleSpec, *PointerSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec.
 	Value interface{}
 }
@@ -0,0 +1,25 @@
+package annotation
+
+// Method represents element of method set of type.
+type Method struct {
+	Name string
+	Spec *FuncSpec
+	// Declaration of method, nil for method of interface.
+	Func *Func
+	// Namespace and File, where method is declared, they are context to resolve packages of Spec.
+	Namespace *Namespace
+	File      *File
+	// Embedded fields, through which method is promoted, empty for method of type itself.
+	Path []*Field
+}
+
+// Checks, that method is declared with pointer receiver.
+func (m *Method) IsPointerReceiver() bool {
+	if m.Func == nil || m.Func.Related == nil {
+		return false
+	}
+
+	spec, ok := m.Func.Related.Spec.(*SimpleSpec)
+
+	return ok && spec.IsPointer
+}
@@ -0,0 +1,22 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestMethod_IsPointerReceiver(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.AssertFalse((&Method{}).IsPointerReceiver())
+	ctrl.AssertFalse((&Method{Func: &Func{}}).IsPointerReceiver())
+	ctrl.AssertFalse(
+		(&Method{Func: &Func{Related: &Field{Spec: &SimpleSpec{TypeName: "typeName"}}}}).IsPointerReceiver(),
+	)
+	ctrl.AssertTrue(
+		(&Method{Func: &Func{Related: &Field{Spec: &SimpleSpec{TypeName: "typeName", IsPointer: true}}}}).
+			IsPointerReceiver(),
+	)
+}
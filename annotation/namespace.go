@@ -12,7 +12,9 @@ type Namespace struct {
 	// Ignored namespace must have no files.
 	// It could be useful to search declarations, imported with "." alias.
 	IsIgnored bool
-	Files     []*File
+	// Read only namespace describes declarations of not scanned package, its files are never written or cleaned.
+	IsReadOnly bool
+	Files      []*File
 }
 
 // Returns package name from first file, ok base of Name field.
@@ -38,3 +40,59 @@ func (m *Namespace) FindFileByName(name string) *File {
 
 	return nil
 }
+
+// Returns type and file, where it's declared, by type name.
+func (m *Namespace) FindTypeByName(name string) (*File, *Type) {
+	if name == "" {
+		panic(errors.New("Variable 'name' must be not empty"))
+	}
+
+	for _, file := range m.Files {
+		for _, typeGroup := range file.TypeGroups {
+			for _, element := range typeGroup.Types {
+				if name == element.Name {
+					return file, element
+				}
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// Returns methods with value and pointer receivers of type, declared in all files of namespace.
+func (m *Namespace) FindMethodsByTypeName(name string) []*Method {
+	if name == "" {
+		panic(errors.New("Variable 'name' must be not empty"))
+	}
+
+	result := []*Method{}
+
+	for _, file := range m.Files {
+		for _, element := range file.Funcs {
+			if element.Related == nil {
+				continue
+			}
+
+			if related, ok := element.Related.Spec.(*SimpleSpec); !ok || related.TypeName != name {
+				continue
+			}
+
+			method := &Method{
+				Name:      element.Name,
+				Spec:      element.Spec,
+				Func:      element,
+				Namespace: m,
+				File:      file,
+			}
+
+			if method.Spec == nil {
+				method.Spec = &FuncSpec{}
+			}
+
+			result = append(result, method)
+		}
+	}
+
+	return result
+}
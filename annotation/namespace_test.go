@@ -104,3 +104,132 @@ func TestNamespace_FindFileByName_WithEmptyName(t *testing.T) {
 		Call(model.FindFileByName, name).
 		ExpectPanic(NewErrorMessageConstraint("Variable 'name' must be not empty"))
 }
+
+func TestNamespace_FindTypeByName(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	expectedType := &Type{
+		Name: "typeName",
+		Spec: &SimpleSpec{TypeName: "int"},
+	}
+	expectedFile := &File{
+		Name:        "file2Name",
+		PackageName: "filePackageName",
+		TypeGroups: []*TypeGroup{
+			{Types: []*Type{{Name: "anotherName", Spec: &SimpleSpec{TypeName: "int"}}, expectedType}},
+		},
+	}
+
+	model := &Namespace{
+		Name: "namespace/packageName",
+		Path: "/namespace/path",
+		Files: []*File{
+			{
+				Name:        "file1Name",
+				PackageName: "filePackageName",
+			},
+			expectedFile,
+		},
+	}
+
+	actualFile, actualType := model.FindTypeByName("typeName")
+
+	ctrl.AssertSame(expectedFile, actualFile)
+	ctrl.AssertSame(expectedType, actualType)
+}
+
+func TestNamespace_FindTypeByName_WithNotFound(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	model := &Namespace{
+		Name: "namespace/packageName",
+		Path: "/namespace/path",
+		Files: []*File{
+			{
+				Name:        "fileName",
+				PackageName: "filePackageName",
+			},
+		},
+	}
+
+	actualFile, actualType := model.FindTypeByName("notFound")
+
+	ctrl.AssertNil(actualFile)
+	ctrl.AssertNil(actualType)
+}
+
+func TestNamespace_FindTypeByName_WithEmptyName(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	model := &Namespace{
+		Name: "namespace/packageName",
+		Path: "/namespace/path",
+	}
+
+	ctrl.Subtest("").
+		Call(model.FindTypeByName, "").
+		ExpectPanic(NewErrorMessageConstraint("Variable 'name' must be not empty"))
+}
+
+func TestNamespace_FindMethodsByTypeName(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	valueFunc := &Func{
+		Name:    "Value",
+		Spec:    &FuncSpec{},
+		Related: &Field{Name: "m", Spec: &SimpleSpec{TypeName: "typeName"}},
+	}
+	pointerFunc := &Func{
+		Name:    "Pointer",
+		Related: &Field{Name: "m", Spec: &SimpleSpec{TypeName: "typeName", IsPointer: true}},
+	}
+	file1 := &File{
+		Name:        "file1Name",
+		PackageName: "filePackageName",
+		Funcs: []*Func{
+			{Name: "function", Spec: &FuncSpec{}},
+			valueFunc,
+			{Name: "Another", Related: &Field{Name: "a", Spec: &SimpleSpec{TypeName: "anotherName"}}},
+		},
+	}
+	file2 := &File{
+		Name:        "file2Name",
+		PackageName: "filePackageName",
+		Funcs:       []*Func{pointerFunc},
+	}
+
+	model := &Namespace{
+		Name:  "namespace/packageName",
+		Path:  "/namespace/path",
+		Files: []*File{file1, file2},
+	}
+
+	expected := []*Method{
+		{Name: "Value", Spec: valueFunc.Spec, Func: valueFunc, Namespace: model, File: file1},
+		{Name: "Pointer", Spec: &FuncSpec{}, Func: pointerFunc, Namespace: model, File: file2},
+	}
+
+	actual := model.FindMethodsByTypeName("typeName")
+
+	ctrl.AssertEqual(expected, actual)
+	ctrl.AssertSame(valueFunc.Spec, actual[0].Spec)
+	ctrl.AssertEqual([]*Method{}, model.FindMethodsByTypeName("notFound"))
+}
+
+func TestNamespace_FindMethodsByTypeName_WithEmptyName(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	model := &Namespace{
+		Name: "namespace/packageName",
+		Path: "/namespace/path",
+	}
+
+	ctrl.Subtest("").
+		Call(model.FindMethodsByTypeName, "").
+		ExpectPanic(NewErrorMessageConstraint("Variable 'name' must be not empty"))
+}
@@ -0,0 +1,8 @@
+package annotation
+
+// PointerSpec represents specification of pointer to unnamed type, e.g. *[]int.
+// Pointer to named type is represented by SimpleSpec with IsPointer flag.
+type PointerSpec struct {
+	// Allowed types: *SimpleSpec, *PointerSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec.
+	Value interface{}
+}
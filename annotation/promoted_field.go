@@ -0,0 +1,11 @@
+package annotation
+
+// PromotedField represents field, which could be selected from type directly or through embedded fields.
+type PromotedField struct {
+	Field *Field
+	// Namespace and File, where field is declared, they are context to resolve packages of Field.Spec.
+	Namespace *Namespace
+	File      *File
+	// Embedded fields, through which field is promoted, empty for field of type itself.
+	Path []*Field
+}
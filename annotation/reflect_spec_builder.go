@@ -0,0 +1,193 @@
+package annotation
+
+import (
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Builds spec models by reflect.Type.
+type ReflectSpecBuilder struct {
+}
+
+// Creates new instance of ReflectSpecBuilder.
+func NewReflectSpecBuilder() *ReflectSpecBuilder {
+	return &ReflectSpecBuilder{}
+}
+
+// Builds spec, which references reflectType. Named types are represented by *SimpleSpec, imports of their packages
+// are returned in order of first usage. Alias of import is set only if package name differs from base of its path,
+// packages with the same names get aliases with numeric suffix, e.g. "v1_2".
+func (b *ReflectSpecBuilder) Build(reflectType reflect.Type) (spec interface{}, imports []*Import) {
+	if reflectType == nil {
+		panic(errors.New("Variable 'reflectType' must be not nil"))
+	}
+
+	imports = []*Import{}
+
+	return b.build(reflectType, &imports), imports
+}
+
+// Builds spec of underlying type of reflectType, e.g. *StructSpec for named struct.
+// Useful to create Type model, which describes reflectType declaration.
+func (b *ReflectSpecBuilder) BuildUnderlying(reflectType reflect.Type) (spec interface{}, imports []*Import) {
+	if reflectType == nil {
+		panic(errors.New("Variable 'reflectType' must be not nil"))
+	}
+
+	imports = []*Import{}
+
+	return b.buildUnnamed(reflectType, &imports), imports
+}
+
+func (b *ReflectSpecBuilder) build(reflectType reflect.Type, imports *[]*Import) interface{} {
+	if reflectType.Name() != "" {
+		return b.buildNamed(reflectType, imports)
+	}
+
+	return b.buildUnnamed(reflectType, imports)
+}
+
+func (b *ReflectSpecBuilder) buildNamed(reflectType reflect.Type, imports *[]*Import) *SimpleSpec {
+	result := &SimpleSpec{
+		TypeName: reflectType.Name(),
+	}
+
+	if reflectType.PkgPath() == "" {
+		return result
+	}
+
+	for _, element := range *imports {
+		if element.Namespace == reflectType.PkgPath() {
+			result.PackageName = element.RealAlias()
+
+			return result
+		}
+	}
+
+	// String of named type has format "packageName.TypeName"
+	result.PackageName = uniqueAlias(*imports, strings.SplitN(reflectType.String(), ".", 2)[0])
+
+	element := &Import{
+		Namespace: reflectType.PkgPath(),
+	}
+
+	if filepath.Base(element.Namespace) != result.PackageName {
+		element.Alias = result.PackageName
+	}
+
+	*imports = append(*imports, element)
+
+	return result
+}
+
+func (b *ReflectSpecBuilder) buildUnnamed(reflectType reflect.Type, imports *[]*Import) interface{} {
+	switch reflectType.Kind() {
+	case reflect.Ptr:
+		return b.buildPtr(reflectType, imports)
+	case reflect.Slice:
+		return &ArraySpec{
+			Value: b.build(reflectType.Elem(), imports),
+		}
+	case reflect.Array:
+		return &ArraySpec{
+			Value:  b.build(reflectType.Elem(), imports),
+			Length: strconv.Itoa(reflectType.Len()),
+		}
+	case reflect.Map:
+		return &MapSpec{
+			Key:   b.build(reflectType.Key(), imports),
+			Value: b.build(reflectType.Elem(), imports),
+		}
+	case reflect.Func:
+		return b.buildFunc(reflectType, imports)
+	case reflect.Struct:
+		return b.buildStruct(reflectType, imports)
+	case reflect.Interface:
+		return b.buildInterface(reflectType, imports)
+	case reflect.Chan, reflect.UnsafePointer:
+		panic(errors.Errorf("Variable 'reflectType' has not allowed kind: %s", reflectType.Kind()))
+	default:
+		// Underlying type of basic kind is predeclared type with the same name
+		return &SimpleSpec{
+			TypeName: reflectType.Kind().String(),
+		}
+	}
+}
+
+// Pointer to named type is built as *SimpleSpec, pointer to other types as *PointerSpec.
+func (b *ReflectSpecBuilder) buildPtr(reflectType reflect.Type, imports *[]*Import) interface{} {
+	result := b.build(reflectType.Elem(), imports)
+
+	if spec, ok := result.(*SimpleSpec); ok && !spec.IsPointer {
+		spec.IsPointer = true
+
+		return spec
+	}
+
+	return &PointerSpec{Value: result}
+}
+
+func (b *ReflectSpecBuilder) buildFunc(reflectType reflect.Type, imports *[]*Import) *FuncSpec {
+	result := &FuncSpec{
+		Params:     make([]*Field, reflectType.NumIn()),
+		Results:    make([]*Field, reflectType.NumOut()),
+		IsVariadic: reflectType.IsVariadic(),
+	}
+
+	for i := range result.Params {
+		result.Params[i] = &Field{
+			Spec: b.build(reflectType.In(i), imports),
+		}
+	}
+
+	for i := range result.Results {
+		result.Results[i] = &Field{
+			Spec: b.build(reflectType.Out(i), imports),
+		}
+	}
+
+	return result
+}
+
+func (b *ReflectSpecBuilder) buildStruct(reflectType reflect.Type, imports *[]*Import) *StructSpec {
+	result := &StructSpec{
+		Fields: make([]*Field, reflectType.NumField()),
+	}
+
+	for i := range result.Fields {
+		structField := reflectType.Field(i)
+		result.Fields[i] = &Field{
+			Tag:  string(structField.Tag),
+			Spec: b.build(structField.Type, imports),
+		}
+
+		if structField.Anonymous {
+			result.Fields[i].IsEmbedded = true
+		} else {
+			result.Fields[i].Name = structField.Name
+		}
+	}
+
+	return result
+}
+
+// Embedded interfaces are flattened to the list of methods.
+func (b *ReflectSpecBuilder) buildInterface(reflectType reflect.Type, imports *[]*Import) *InterfaceSpec {
+	result := &InterfaceSpec{
+		Fields: make([]*Field, reflectType.NumMethod()),
+	}
+
+	for i := range result.Fields {
+		method := reflectType.Method(i)
+		result.Fields[i] = &Field{
+			Name: method.Name,
+			Spec: b.buildFunc(method.Type, imports),
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,221 @@
+package annotation
+
+import (
+	htmlTemplate "html/template"
+	"io"
+	"reflect"
+	"testing"
+	textTemplate "text/template"
+	"time"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+type reflectSpecBuilderModel struct {
+	*TestAnnotation
+	ID       int `json:"id"`
+	Duration time.Duration
+	Items    map[string][]*time.Time
+	Reader   io.Reader
+	Format   func(format string, args ...interface{}) (string, error)
+}
+
+func TestNewReflectSpecBuilder(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	actual := NewReflectSpecBuilder()
+
+	ctrl.AssertNotNil(actual)
+}
+
+func TestReflectSpecBuilder_Build(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	expectedSpec := &ArraySpec{
+		Value: &SimpleSpec{
+			PackageName: "annotation",
+			TypeName:    "reflectSpecBuilderModel",
+			IsPointer:   true,
+		},
+		Length: "5",
+	}
+	expectedImports := []*Import{
+		{
+			Namespace: "github.com/index0h/go-annotation/annotation",
+		},
+	}
+
+	actualSpec, actualImports := NewReflectSpecBuilder().Build(reflect.TypeOf([5]*reflectSpecBuilderModel{}))
+
+	ctrl.AssertEqual(expectedSpec, actualSpec)
+	ctrl.AssertEqual(expectedImports, actualImports)
+}
+
+func TestReflectSpecBuilder_Build_WithPredeclared(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	expectedSpec := &MapSpec{
+		Key:   &SimpleSpec{TypeName: "string"},
+		Value: &SimpleSpec{TypeName: "error"},
+	}
+
+	actualSpec, actualImports := NewReflectSpecBuilder().Build(reflect.TypeOf(map[string]error{}))
+
+	ctrl.AssertEqual(expectedSpec, actualSpec)
+	ctrl.AssertEqual([]*Import{}, actualImports)
+}
+
+func TestReflectSpecBuilder_BuildUnderlying(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	expectedSpec := &StructSpec{
+		Fields: []*Field{
+			{
+				Spec:       &SimpleSpec{PackageName: "annotation", TypeName: "TestAnnotation", IsPointer: true},
+				IsEmbedded: true,
+			},
+			{
+				Name: "ID",
+				Tag:  `json:"id"`,
+				Spec: &SimpleSpec{TypeName: "int"},
+			},
+			{
+				Name: "Duration",
+				Spec: &SimpleSpec{PackageName: "time", TypeName: "Duration"},
+			},
+			{
+				Name: "Items",
+				Spec: &MapSpec{
+					Key: &SimpleSpec{TypeName: "string"},
+					Value: &ArraySpec{
+						Value: &SimpleSpec{PackageName: "time", TypeName: "Time", IsPointer: true},
+					},
+				},
+			},
+			{
+				Name: "Reader",
+				Spec: &SimpleSpec{PackageName: "io", TypeName: "Reader"},
+			},
+			{
+				Name: "Format",
+				Spec: &FuncSpec{
+					Params: []*Field{
+						{Spec: &SimpleSpec{TypeName: "string"}},
+						{Spec: &ArraySpec{Value: &InterfaceSpec{Fields: []*Field{}}}},
+					},
+					Results: []*Field{
+						{Spec: &SimpleSpec{TypeName: "string"}},
+						{Spec: &SimpleSpec{TypeName: "error"}},
+					},
+					IsVariadic: true,
+				},
+			},
+		},
+	}
+	expectedImports := []*Import{
+		{Namespace: "github.com/index0h/go-annotation/annotation"},
+		{Namespace: "time"},
+		{Namespace: "io"},
+	}
+
+	actualSpec, actualImports := NewReflectSpecBuilder().BuildUnderlying(reflect.TypeOf(reflectSpecBuilderModel{}))
+
+	ctrl.AssertEqual(expectedSpec, actualSpec)
+	ctrl.AssertEqual(expectedImports, actualImports)
+	ctrl.AssertNil(NewEntityValidator().Validate(actualSpec))
+}
+
+func TestReflectSpecBuilder_BuildUnderlying_WithInterface(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	expectedSpec := &InterfaceSpec{
+		Fields: []*Field{
+			{
+				Name: "Read",
+				Spec: &FuncSpec{
+					Params: []*Field{
+						{Spec: &ArraySpec{Value: &SimpleSpec{TypeName: "uint8"}}},
+					},
+					Results: []*Field{
+						{Spec: &SimpleSpec{TypeName: "int"}},
+						{Spec: &SimpleSpec{TypeName: "error"}},
+					},
+				},
+			},
+		},
+	}
+
+	actualSpec, _ := NewReflectSpecBuilder().BuildUnderlying(reflect.TypeOf((*io.Reader)(nil)).Elem())
+
+	ctrl.AssertEqual(expectedSpec, actualSpec)
+}
+
+func TestReflectSpecBuilder_Build_WithNil(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewReflectSpecBuilder().Build, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'reflectType' must be not nil"))
+}
+
+func TestReflectSpecBuilder_Build_WithChan(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewReflectSpecBuilder().Build, reflect.TypeOf(make(chan int))).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'reflectType' has not allowed kind: chan"))
+}
+
+func TestReflectSpecBuilder_Build_WithPointerToUnnamed(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	expectedSpec := &PointerSpec{
+		Value: &PointerSpec{
+			Value: &ArraySpec{
+				Value: &SimpleSpec{TypeName: "int"},
+			},
+		},
+	}
+
+	actualSpec, actualImports := NewReflectSpecBuilder().Build(reflect.TypeOf((**[]int)(nil)))
+
+	ctrl.AssertEqual(expectedSpec, actualSpec)
+	ctrl.AssertEqual([]*Import{}, actualImports)
+	ctrl.AssertSame("**[]int", NewEntityRenderer().Render(actualSpec))
+}
+
+func TestReflectSpecBuilder_Build_WithSamePackageNames(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	expectedSpec := &StructSpec{
+		Fields: []*Field{
+			{Name: "Text", Spec: &SimpleSpec{PackageName: "template", TypeName: "Template", IsPointer: true}},
+			{Name: "HTML", Spec: &SimpleSpec{PackageName: "template_2", TypeName: "Template", IsPointer: true}},
+			{Name: "Other", Spec: &SimpleSpec{PackageName: "template", TypeName: "FuncMap"}},
+		},
+	}
+	expectedImports := []*Import{
+		{Namespace: "text/template"},
+		{Namespace: "html/template", Alias: "template_2"},
+	}
+
+	actualSpec, actualImports := NewReflectSpecBuilder().Build(
+		reflect.TypeOf(struct {
+			Text  *textTemplate.Template
+			HTML  *htmlTemplate.Template
+			Other textTemplate.FuncMap
+		}{}),
+	)
+
+	ctrl.AssertEqual(expectedSpec, actualSpec)
+	ctrl.AssertEqual(expectedImports, actualImports)
+}
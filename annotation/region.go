@@ -0,0 +1,188 @@
+package annotation
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Markers of region inside hand-written file, which content is generated, e.g.:
+//
+//	// go-annotation:begin stringer:Color
+//	func (c Color) String() string { ... }
+//	// go-annotation:end stringer:Color
+//
+// Region name starts with name of generator, which fills it, optional suffix is separated by colon.
+const (
+	RegionBeginMarker = "// go-annotation:begin "
+	RegionEndMarker   = "// go-annotation:end "
+)
+
+// Region contains generated content of marked region.
+type Region struct {
+	Name    string
+	Content string
+}
+
+// Returns name of generator, which owns region.
+func (m *Region) Owner() string {
+	return regionOwner(m.Name)
+}
+
+// Regions contains regions of hand-written files, which are filled by generators during generation.
+type Regions struct {
+	mutex   sync.RWMutex
+	regions map[*File][]*Region
+}
+
+func NewRegions() *Regions {
+	return &Regions{regions: map[*File][]*Region{}}
+}
+
+// Sets region of file, previous region with the same name is replaced.
+func (r *Regions) Set(file *File, region *Region) {
+	if file == nil {
+		panic(errors.New("Variable 'file' must be not nil"))
+	}
+
+	if region == nil {
+		panic(errors.New("Variable 'region' must be not nil"))
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for i, element := range r.regions[file] {
+		if element.Name == region.Name {
+			r.regions[file][i] = region
+
+			return
+		}
+	}
+
+	r.regions[file] = append(r.regions[file], region)
+}
+
+// Returns region of file by name or nil.
+func (r *Regions) Find(file *File, name string) *Region {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, element := range r.regions[file] {
+		if element.Name == name {
+			return element
+		}
+	}
+
+	return nil
+}
+
+// Returns regions of file in order of filling.
+func (r *Regions) FindByFile(file *File) []*Region {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return append([]*Region{}, r.regions[file]...)
+}
+
+func (r *Regions) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.regions = map[*File][]*Region{}
+}
+
+// Bounds of region content: begin is offset after begin marker line, end is offset of end marker line.
+type regionBounds struct {
+	name  string
+	begin int
+	end   int
+}
+
+func regionOwner(name string) string {
+	return strings.SplitN(name, ":", 2)[0]
+}
+
+// Returns bounds of all regions of content.
+func findRegions(content string) ([]*regionBounds, error) {
+	result := []*regionBounds{}
+	names := map[string]bool{}
+
+	var current *regionBounds
+
+	for offset := 0; offset < len(content); {
+		lineEnd := strings.IndexByte(content[offset:], '\n')
+
+		if lineEnd < 0 {
+			lineEnd = len(content)
+		} else {
+			lineEnd += offset + 1
+		}
+
+		line := strings.TrimSpace(content[offset:lineEnd])
+
+		switch {
+		case strings.HasPrefix(line, RegionBeginMarker):
+			name := strings.TrimSpace(strings.TrimPrefix(line, RegionBeginMarker))
+
+			if current != nil {
+				return nil, errors.Errorf("Region '%s' is not closed", current.name)
+			}
+
+			if names[name] {
+				return nil, errors.Errorf("Region '%s' is duplicated", name)
+			}
+
+			names[name] = true
+			current = &regionBounds{name: name, begin: lineEnd}
+		case strings.HasPrefix(line, RegionEndMarker):
+			name := strings.TrimSpace(strings.TrimPrefix(line, RegionEndMarker))
+
+			if current == nil || current.name != name {
+				return nil, errors.Errorf("Region '%s' is not opened", name)
+			}
+
+			current.end = offset
+			result = append(result, current)
+			current = nil
+		}
+
+		offset = lineEnd
+	}
+
+	if current != nil {
+		return nil, errors.Errorf("Region '%s' is not closed", current.name)
+	}
+
+	return result, nil
+}
+
+// Replaces content of regions, which are selected by callback. Callback returns new content and true to replace it.
+func replaceRegions(content string, callback func(name string) (string, bool)) (string, error) {
+	bounds, err := findRegions(content)
+
+	if err != nil {
+		return "", err
+	}
+
+	result := ""
+	offset := 0
+
+	for _, element := range bounds {
+		regionContent, ok := callback(element.name)
+
+		if !ok {
+			continue
+		}
+
+		if regionContent != "" && !strings.HasSuffix(regionContent, "\n") {
+			regionContent += "\n"
+		}
+
+		result += content[offset:element.begin] + regionContent
+		offset = element.end
+	}
+
+	return result + content[offset:], nil
+}
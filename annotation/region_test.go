@@ -0,0 +1,134 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestRegion_Owner(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.AssertSame("stringer", (&Region{Name: "stringer"}).Owner())
+	ctrl.AssertSame("stringer", (&Region{Name: "stringer:Color:Dark"}).Owner())
+}
+
+func TestRegions(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	file := &File{Name: "file.go"}
+	first := &Region{Name: "first", Content: "// first"}
+	second := &Region{Name: "second", Content: "// second"}
+	replaced := &Region{Name: "first", Content: "// replaced"}
+
+	regions := NewRegions()
+	regions.Set(file, first)
+	regions.Set(file, second)
+
+	ctrl.AssertSame(first, regions.Find(file, "first"))
+	ctrl.AssertNil(regions.Find(file, "unknown"))
+	ctrl.AssertNil(regions.Find(&File{}, "first"))
+
+	regions.Set(file, replaced)
+
+	ctrl.AssertEqual([]*Region{replaced, second}, regions.FindByFile(file))
+
+	regions.Reset()
+
+	ctrl.AssertSame(0, len(regions.FindByFile(file)))
+
+	ctrl.Subtest("File").
+		Call(regions.Set, nil, first).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'file' must be not nil"))
+	ctrl.Subtest("Region").
+		Call(regions.Set, file, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'region' must be not nil"))
+}
+
+func TestReplaceRegions(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := "package model\n\n" +
+		"// go-annotation:begin first\n" +
+		"// old first\n" +
+		"// go-annotation:end first\n\n" +
+		"func init() {\n" +
+		"\t// go-annotation:begin second\n" +
+		"\t// go-annotation:end second\n" +
+		"}\n\n" +
+		"// go-annotation:begin kept\n" +
+		"// old kept\n" +
+		"// go-annotation:end kept\n"
+
+	expected := "package model\n\n" +
+		"// go-annotation:begin first\n" +
+		"// go-annotation:end first\n\n" +
+		"func init() {\n" +
+		"\t// go-annotation:begin second\n" +
+		"\tRegister()\n" +
+		"\t// go-annotation:end second\n" +
+		"}\n\n" +
+		"// go-annotation:begin kept\n" +
+		"// old kept\n" +
+		"// go-annotation:end kept\n"
+
+	actual, err := replaceRegions(content, func(name string) (string, bool) {
+		switch name {
+		case "first":
+			return "", true
+		case "second":
+			return "\tRegister()", true
+		default:
+			return "", false
+		}
+	})
+
+	ctrl.AssertNil(err)
+	ctrl.AssertSame(expected, actual)
+}
+
+func TestReplaceRegions_WithInvalidMarkers(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	type testCase struct {
+		content  string
+		expected string
+	}
+
+	testCases := []testCase{
+		{
+			content:  "// go-annotation:begin first\n",
+			expected: "Region 'first' is not closed",
+		},
+		{
+			content:  "// go-annotation:begin first\n// go-annotation:begin second\n",
+			expected: "Region 'first' is not closed",
+		},
+		{
+			content:  "// go-annotation:end first\n",
+			expected: "Region 'first' is not opened",
+		},
+		{
+			content:  "// go-annotation:begin first\n// go-annotation:end second\n",
+			expected: "Region 'second' is not opened",
+		},
+		{
+			content: "// go-annotation:begin first\n// go-annotation:end first\n" +
+				"// go-annotation:begin first\n// go-annotation:end first\n",
+			expected: "Region 'first' is duplicated",
+		},
+	}
+
+	for _, testCase := range testCases {
+		_, err := replaceRegions(testCase.content, func(name string) (string, bool) {
+			return "", true
+		})
+
+		ctrl.AssertNotNil(err)
+		ctrl.AssertSame(testCase.expected, err.Error())
+	}
+}
@@ -0,0 +1,12 @@
+package annotation
+
+// SelectedEntity is result of EntitySelector.
+type SelectedEntity struct {
+	Namespace *Namespace
+	// File is nil for namespace level result.
+	File *File
+	// Allowed types: *File, *Import, *Const, *Var, *Type, *Func, *Field.
+	Entity interface{}
+	// Entities selected by previous steps of selector, e.g. *Type for "Type.Field" selector.
+	Parents []interface{}
+}
@@ -0,0 +1,176 @@
+package annotation
+
+import (
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Bounds of declaration with its doc comment.
+type sourceSpan struct {
+	begin int
+	end   int
+}
+
+type SourceFileEditor struct {
+	sourceParser SourceParser
+	renderer     Renderer
+}
+
+func NewSourceFileEditor(sourceParser SourceParser, renderer Renderer) *SourceFileEditor {
+	if sourceParser == nil {
+		panic(errors.New("Variable 'sourceParser' must be not nil"))
+	}
+
+	if renderer == nil {
+		panic(errors.New("Variable 'renderer' must be not nil"))
+	}
+
+	return &SourceFileEditor{sourceParser: sourceParser, renderer: renderer}
+}
+
+// Compares rendered declarations of hand-written file with rendered declarations parsed from its content, so changes of
+// comments are detected too. Changed declarations are rendered again with original source of their unchanged specs
+// and fields, line comments and blank lines between them. New declarations are added after the last declaration of
+// the same kind, removed ones are deleted. Other content, including comments and formatting, is kept. Original
+// declarations are taken from File.Layout, if it's kept by SourceParser, otherwise content is parsed again.
+// Returns new content and names of changed declarations.
+func (e *SourceFileEditor) Edit(file *File) (string, []string) {
+	if file == nil {
+		panic(errors.New("Variable 'file' must be not nil"))
+	}
+
+	if file.Content == "" {
+		panic(errors.New("Variable 'file.Content' must be not empty"))
+	}
+
+	fileSet := token.NewFileSet()
+	astFile, err := parser.ParseFile(fileSet, file.Name, file.Content, parser.ParseComments)
+
+	if err != nil {
+		panic(err)
+	}
+
+	spans := e.spans(astFile, fileSet)
+	currentDeclarations := fileDeclarations(file)
+
+	var originalDeclarations map[string][]interface{}
+
+	// Layout keeps declarations parsed from content, so content is parsed again only for file without layout
+	if file.Layout != nil {
+		originalDeclarations = layoutDeclarations(file.Layout)
+	} else {
+		originalDeclarations = fileDeclarations(e.sourceParser.Parse(file.Name, file.Content))
+	}
+
+	edits := []*sourceEdit{}
+	changes := []string{}
+	insertOffset := fileSet.Position(astFile.Name.End()).Offset
+
+	for _, kind := range declarationKinds {
+		original := originalDeclarations[kind]
+		current := currentDeclarations[kind]
+
+		pairs := pairDeclarations(kind, original, current)
+		isPaired := map[int]bool{}
+
+		// New declarations are inserted after the last kept declaration of the same or previous kind
+		for _, j := range pairs {
+			if spans[kind][j].end > insertOffset {
+				insertOffset = spans[kind][j].end
+			}
+		}
+
+		for i, element := range current {
+			j, ok := pairs[i]
+
+			switch {
+			case !ok:
+				edits = append(
+					edits,
+					&sourceEdit{begin: insertOffset, end: insertOffset, content: "\n\n" + e.render(element)},
+				)
+			case e.render(original[j]) != e.render(element):
+				isPaired[j] = true
+				source := file.Content[spans[kind][j].begin:spans[kind][j].end]
+				edits = append(
+					edits,
+					&sourceEdit{
+						begin:   spans[kind][j].begin,
+						end:     spans[kind][j].end,
+						content: keepDeclarationLayout(source, e.render(element)),
+					},
+				)
+			default:
+				isPaired[j] = true
+
+				continue
+			}
+
+			changes = append(changes, describeDeclaration(element)...)
+		}
+
+		for j := range original {
+			if isPaired[j] {
+				continue
+			}
+
+			end := spans[kind][j].end
+
+			begin := spans[kind][j].begin
+
+			// Blank line after removed declaration is removed too, or before it at the end of file
+			for k := 0; k < 2 && end < len(file.Content) && file.Content[end] == '\n'; k++ {
+				end++
+			}
+
+			for end == len(file.Content) && begin > 1 && file.Content[begin-2:begin] == "\n\n" {
+				begin--
+			}
+
+			edits = append(edits, &sourceEdit{begin: begin, end: end})
+			changes = append(changes, describeDeclaration(original[j])...)
+		}
+	}
+
+	return applySourceEdits(file.Content, edits), changes
+}
+
+func (e *SourceFileEditor) spans(astFile *ast.File, fileSet *token.FileSet) map[string][]*sourceSpan {
+	result := map[string][]*sourceSpan{}
+
+	for _, node := range astFile.Decls {
+		switch decl := node.(type) {
+		case *ast.GenDecl:
+			result[decl.Tok.String()] = append(result[decl.Tok.String()], e.span(fileSet, decl.Doc, decl))
+		case *ast.FuncDecl:
+			result["func"] = append(result["func"], e.span(fileSet, decl.Doc, decl))
+		}
+	}
+
+	return result
+}
+
+func (e *SourceFileEditor) span(fileSet *token.FileSet, doc *ast.CommentGroup, node ast.Node) *sourceSpan {
+	begin := node.Pos()
+
+	if doc != nil {
+		begin = doc.Pos()
+	}
+
+	return &sourceSpan{begin: fileSet.Position(begin).Offset, end: fileSet.Position(node.End()).Offset}
+}
+
+func (e *SourceFileEditor) render(entity interface{}) string {
+	content, err := format.Source([]byte(e.renderer.Render(entity)))
+
+	if err != nil {
+		panic(errors.Wrapf(err, "Can't format %s", strings.Join(describeDeclaration(entity), ", ")))
+	}
+
+	return strings.TrimRight(string(content), "\n")
+}
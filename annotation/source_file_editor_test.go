@@ -0,0 +1,269 @@
+package annotation
+
+import (
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestNewSourceFileEditor(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	sourceParser := NewSourceParserMock(ctrl)
+	renderer := NewRendererMock(ctrl)
+
+	actual := NewSourceFileEditor(sourceParser, renderer)
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(sourceParser, actual.sourceParser)
+	ctrl.AssertSame(renderer, actual.renderer)
+}
+
+func TestNewSourceFileEditor_WithNilDependencies(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	sourceParser := NewSourceParserMock(ctrl)
+	renderer := NewRendererMock(ctrl)
+
+	ctrl.Subtest("SourceParser").
+		Call(NewSourceFileEditor, nil, renderer).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'sourceParser' must be not nil"))
+	ctrl.Subtest("Renderer").
+		Call(NewSourceFileEditor, sourceParser, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'renderer' must be not nil"))
+}
+
+func TestSourceFileEditor_Edit(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := "package model\n\n" +
+		"import \"fmt\"\n\n" +
+		"const (\n" +
+		"\t// First is kept.\n" +
+		"\tFirst = 1\n" +
+		")\n\n" +
+		"// Model is kept.\n" +
+		"type Model struct {\n" +
+		"\t// Name of model.\n" +
+		"\tName string\n" +
+		"}\n\n" +
+		"// Unchanged keeps its comments.\n" +
+		"func (m *Model) Unchanged() string {\n" +
+		"\t// Inner comment.\n" +
+		"\treturn fmt.Sprint(m.Name)   // Trailing comment.\n" +
+		"}\n\n" +
+		"func Removed() {}\n"
+
+	expected := "package model\n\n" +
+		"import \"fmt\"\n\n" +
+		"const (\n" +
+		"\t// First is kept.\n" +
+		"\tFirst  int = 1\n" +
+		"\tSecond     = 2\n" +
+		")\n\n" +
+		"// Model is kept.\n" +
+		"type Model struct {\n" +
+		"\t// Name of model.\n" +
+		"\tName string \"json:\\\"name\\\"\"\n" +
+		"}\n\n" +
+		"// Unchanged keeps its comments.\n" +
+		"func (m *Model) Unchanged() string {\n" +
+		"\t// Inner comment.\n" +
+		"\treturn fmt.Sprint(m.Name)   // Trailing comment.\n" +
+		"}\n\n" +
+		"func NewModel() *Model {\n" +
+		"\treturn &Model{}\n" +
+		"}\n"
+
+	sourceParser := NewGoSourceParser(NewJSONAnnotationParser())
+	editor := NewSourceFileEditor(sourceParser, NewEntityRenderer())
+	file := sourceParser.Parse("model.go", content)
+
+	file.ConstGroups[0].Consts = append(file.ConstGroups[0].Consts, &Const{Name: "Second", Value: "2"})
+	file.TypeGroups[0].Types[0].Spec.(*StructSpec).Fields[0].Tag = `json:"name"`
+	file.Funcs = []*Func{
+		file.Funcs[0],
+		{
+			Name:    "NewModel",
+			Spec:    &FuncSpec{Results: []*Field{{Spec: &SimpleSpec{TypeName: "Model", IsPointer: true}}}},
+			Content: "return &Model{}",
+		},
+	}
+
+	actual, changes := editor.Edit(file)
+
+	ctrl.AssertSame(expected, actual)
+	ctrl.AssertEqual([]string{"const First", "const Second", "type Model", "func NewModel", "func Removed"}, changes)
+
+	unchanged := sourceParser.Parse("model.go", content)
+
+	actual, changes = editor.Edit(unchanged)
+
+	ctrl.AssertSame(content, actual)
+	ctrl.AssertEqual([]string{}, changes)
+}
+
+func TestSourceFileEditor_Edit_WithNewDeclarations(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := "// Package model.\npackage model\n"
+
+	expected := "// Package model.\npackage model\n\n" +
+		"import \"fmt\"\n\n" +
+		"func Print() {\n" +
+		"\tfmt.Println()\n" +
+		"}\n"
+
+	sourceParser := NewGoSourceParser(NewJSONAnnotationParser())
+	editor := NewSourceFileEditor(sourceParser, NewEntityRenderer())
+	file := sourceParser.Parse("model.go", content)
+
+	file.ImportGroups = []*ImportGroup{{Imports: []*Import{{Namespace: "fmt"}}}}
+	file.Funcs = []*Func{{Name: "Print", Spec: &FuncSpec{}, Content: "fmt.Println()"}}
+
+	actual, changes := editor.Edit(file)
+
+	ctrl.AssertSame(expected, actual)
+	ctrl.AssertEqual([]string{"import fmt", "func Print"}, changes)
+}
+
+func TestSourceFileEditor_Edit_WithChangedComment(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := "package model\n\n" +
+		"// Model is changed.\n" +
+		"type Model struct {\n" +
+		"\t// Name of model.\n" +
+		"\tName string // Name is required.\n" +
+		"}\n"
+
+	expected := "package model\n\n" +
+		"// Model is changed.\n" +
+		"type Model struct {\n" +
+		"\t// Full name of model.\n" +
+		"\tName string // Name is required.\n" +
+		"}\n"
+
+	sourceParser := NewGoSourceParser(NewJSONAnnotationParser())
+	editor := NewSourceFileEditor(sourceParser, NewEntityRenderer())
+	file := sourceParser.Parse("model.go", content)
+
+	file.TypeGroups[0].Types[0].Spec.(*StructSpec).Fields[0].Comment = "Full name of model."
+
+	actual, changes := editor.Edit(file)
+
+	ctrl.AssertSame(expected, actual)
+	ctrl.AssertEqual([]string{"type Model"}, changes)
+}
+
+func TestSourceFileEditor_Edit_WithComments(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := "package model\n\n" +
+		"// Model has comments.\n" +
+		"type Model struct {\n" +
+		"\t// Name of model.\n" +
+		"\tName string // Name is required.\n" +
+		"\n" +
+		"\t// Options of model.\n" +
+		"\tOptions struct {\n" +
+		"\t\tIsDebug bool // Debug mode.\n" +
+		"\t}\n" +
+		"\tAge int // Age in years.\n" +
+		"}\n\n" +
+		"// Validate has comments in body.\n" +
+		"func (m *Model) Validate() bool {\n" +
+		"\t// Name is checked.\n" +
+		"\tif m.Name == \"\" { // Empty name.\n" +
+		"\t\treturn false\n" +
+		"\t}\n" +
+		"\n" +
+		"\treturn true // Valid.\n" +
+		"}\n"
+
+	expected := "package model\n\n" +
+		"// Model has comments.\n" +
+		"type Model struct {\n" +
+		"\t// Name of model.\n" +
+		"\tName string // Name is required.\n" +
+		"\n" +
+		"\t// Options of model.\n" +
+		"\tOptions struct {\n" +
+		"\t\tIsDebug bool // Debug mode.\n" +
+		"\t\tLevel   int\n" +
+		"\t}\n" +
+		"\tAge uint // Age in years.\n" +
+		"}\n\n" +
+		"// Validate has comments in body.\n" +
+		"func (m *Model) Validate() (result bool) {\n" +
+		"\t// Name is checked.\n" +
+		"\tif m.Name == \"\" { // Empty name.\n" +
+		"\t\treturn false\n" +
+		"\t}\n" +
+		"\n" +
+		"\treturn true // Valid.\n" +
+		"}\n"
+
+	sourceParser := NewGoSourceParser(NewJSONAnnotationParser())
+	editor := NewSourceFileEditor(sourceParser, NewEntityRenderer())
+	file := sourceParser.Parse("model.go", content)
+	fields := file.TypeGroups[0].Types[0].Spec.(*StructSpec).Fields
+
+	options := fields[1].Spec.(*StructSpec)
+	options.Fields = append(options.Fields, &Field{Name: "Level", Spec: &SimpleSpec{TypeName: "int"}})
+	fields[2].Spec = &SimpleSpec{TypeName: "uint"}
+	file.Funcs[0].Spec.Results[0].Name = "result"
+
+	actual, changes := editor.Edit(file)
+
+	ctrl.AssertSame(expected, actual)
+	ctrl.AssertEqual([]string{"type Model", "func Model.Validate"}, changes)
+}
+
+func TestSourceFileEditor_Edit_WithFileLayout(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	content := "package model\n\n" +
+		"type A struct{}\n\n" +
+		"// z\n" +
+		"type B struct{}\n"
+
+	expected := "package model\n\n" +
+		"// z\n" +
+		"type B struct{}\n"
+
+	sourceParser := NewGoSourceParser(NewJSONAnnotationParser())
+	sourceParser.SetLayoutKept(true)
+
+	// Content isn't parsed again, declarations are taken from layout
+	editor := NewSourceFileEditor(NewSourceParserMock(ctrl), NewEntityRenderer())
+	file := sourceParser.Parse("model.go", content)
+
+	file.TypeGroups = file.TypeGroups[1:]
+
+	actual, changes := editor.Edit(file)
+
+	ctrl.AssertSame(expected, actual)
+	ctrl.AssertEqual([]string{"type A"}, changes)
+}
+
+func TestSourceFileEditor_Edit_WithInvalidFile(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	editor := NewSourceFileEditor(NewSourceParserMock(ctrl), NewRendererMock(ctrl))
+
+	ctrl.Subtest("Nil").
+		Call(editor.Edit, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'file' must be not nil"))
+	ctrl.Subtest("Generated").
+		Call(editor.Edit, &File{Name: "model.go"}).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'file.Content' must be not empty"))
+}
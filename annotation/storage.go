@@ -1,11 +1,55 @@
 package annotation
 
 import (
+	"reflect"
+	"sync"
+
 	"github.com/pkg/errors"
 )
 
+var predeclaredTypes = map[string]bool{
+	"any":        true,
+	"bool":       true,
+	"byte":       true,
+	"comparable": true,
+	"complex64":  true,
+	"complex128": true,
+	"error":      true,
+	"float32":    true,
+	"float64":    true,
+	"int":        true,
+	"int8":       true,
+	"int16":      true,
+	"int32":      true,
+	"int64":      true,
+	"rune":       true,
+	"string":     true,
+	"uint":       true,
+	"uint8":      true,
+	"uint16":     true,
+	"uint32":     true,
+	"uint64":     true,
+	"uintptr":    true,
+}
+
 type Storage struct {
 	Namespaces []*Namespace
+	// Lookup indexes, they are built on first lookup and maintained by Add* and Remove* methods.
+	// Indexes are rebuilt, if namespaces or files were added or removed directly. Other direct modifications, e.g. of
+	// declarations of files, must be wrapped by Update or followed by Reindex, otherwise lookups return stale results.
+	index *storageIndex
+	// Methods of storage are safe for concurrent use, direct access to Namespaces must be wrapped by Read or Update.
+	mutex sync.RWMutex
+}
+
+type storageIndex struct {
+	namespacesByName map[string]*Namespace
+	namespacesByPath map[string]*Namespace
+	namespacesByFile map[*File]*Namespace
+	declarations     map[string]*Declaration
+	// Counts of indexed namespaces and their files, index is stale if they differ from actual ones.
+	namespacesCount int
+	filesCounts     map[*Namespace]int
 }
 
 // Returns namespace be its name.
@@ -14,11 +58,572 @@ func (m *Storage) FindNamespaceByName(name string) *Namespace {
 		panic(errors.New("Variable 'name' must be not empty"))
 	}
 
-	for _, element := range m.Namespaces {
-		if name == element.Name {
-			return element
+	var result *Namespace
+
+	m.readIndex(func(index *storageIndex) {
+		result = index.namespacesByName[name]
+	})
+
+	return result
+}
+
+// Returns namespace be its path.
+func (m *Storage) FindNamespaceByPath(path string) *Namespace {
+	if path == "" {
+		panic(errors.New("Variable 'path' must be not empty"))
+	}
+
+	var result *Namespace
+
+	m.readIndex(func(index *storageIndex) {
+		result = index.namespacesByPath[path]
+	})
+
+	return result
+}
+
+// Returns namespace, which contains file.
+func (m *Storage) FindNamespaceByFile(file *File) *Namespace {
+	if file == nil {
+		panic(errors.New("Variable 'file' must be not nil"))
+	}
+
+	var result *Namespace
+
+	m.readIndex(func(index *storageIndex) {
+		result = index.namespacesByFile[file]
+	})
+
+	return result
+}
+
+// Returns declaration by qualified name: "namespace.Name" for type, func, const and var or "namespace.Type.Method"
+// for method. Returns nil if declaration was not found.
+func (m *Storage) FindDeclaration(qualifiedName string) *Declaration {
+	if qualifiedName == "" {
+		panic(errors.New("Variable 'qualifiedName' must be not empty"))
+	}
+
+	var result *Declaration
+
+	m.readIndex(func(index *storageIndex) {
+		result = index.declarations[qualifiedName]
+	})
+
+	return result
+}
+
+// Adds namespace to storage and indexes it. Name and path of namespace must be unique.
+func (m *Storage) AddNamespace(namespace *Namespace) {
+	if namespace == nil {
+		panic(errors.New("Variable 'namespace' must be not nil"))
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	index := m.getIndex()
+
+	if _, ok := index.namespacesByName[namespace.Name]; ok {
+		panic(errors.Errorf("Storage already has namespace with name: '%s'", namespace.Name))
+	}
+
+	if _, ok := index.namespacesByPath[namespace.Path]; ok {
+		panic(errors.Errorf("Storage already has namespace with path: '%s'", namespace.Path))
+	}
+
+	m.Namespaces = append(m.Namespaces, namespace)
+	index.namespacesCount++
+	m.indexNamespace(index, namespace)
+}
+
+// Removes namespace with all its files from storage and indexes, does nothing if namespace is absent.
+func (m *Storage) RemoveNamespace(namespace *Namespace) {
+	if namespace == nil {
+		panic(errors.New("Variable 'namespace' must be not nil"))
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	index := m.getIndex()
+
+	for i, element := range m.Namespaces {
+		if element != namespace {
+			continue
+		}
+
+		m.Namespaces = append(m.Namespaces[:i:i], m.Namespaces[i+1:]...)
+		index.namespacesCount--
+
+		delete(index.namespacesByName, namespace.Name)
+		delete(index.namespacesByPath, namespace.Path)
+
+		for _, file := range namespace.Files {
+			m.unindexFile(index, namespace, file)
+		}
+
+		delete(index.filesCounts, namespace)
+
+		return
+	}
+}
+
+// Adds file to namespace of storage and indexes its declarations. File name must be unique in namespace.
+func (m *Storage) AddFile(namespace *Namespace, file *File) {
+	if namespace == nil {
+		panic(errors.New("Variable 'namespace' must be not nil"))
+	}
+
+	if file == nil {
+		panic(errors.New("Variable 'file' must be not nil"))
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	index := m.getIndex()
+
+	if index.namespacesByName[namespace.Name] != namespace {
+		panic(errors.Errorf("Storage has no namespace with name: '%s'", namespace.Name))
+	}
+
+	if namespace.FindFileByName(file.Name) != nil {
+		panic(errors.Errorf("Namespace '%s' already has file with name: '%s'", namespace.Name, file.Name))
+	}
+
+	namespace.Files = append(namespace.Files, file)
+	m.indexFile(index, namespace, file)
+}
+
+// Removes file from its namespace and indexes, does nothing if file is absent.
+func (m *Storage) RemoveFile(file *File) {
+	if file == nil {
+		panic(errors.New("Variable 'file' must be not nil"))
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	index := m.getIndex()
+	namespace := index.namespacesByFile[file]
+
+	if namespace == nil {
+		return
+	}
+
+	for i, element := range namespace.Files {
+		if element == file {
+			namespace.Files = append(namespace.Files[:i:i], namespace.Files[i+1:]...)
+
+			break
+		}
+	}
+
+	m.unindexFile(index, namespace, file)
+}
+
+// Drops indexes, they will be rebuilt on next lookup.
+// It must be called after direct modification of declarations of files or after replacement of files.
+func (m *Storage) Reindex() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.index = nil
+}
+
+// Calls callback with shared lock, so it could read Namespaces concurrently with other readers.
+// Callback must not call other methods of storage.
+func (m *Storage) Read(callback func()) {
+	if callback == nil {
+		panic(errors.New("Variable 'callback' must be not nil"))
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	callback()
+}
+
+// Calls callback with exclusive lock, so it could modify Namespaces, Files or declarations of files directly.
+// Indexes are rebuilt after modification. Callback must not call other methods of storage.
+func (m *Storage) Update(callback func()) {
+	if callback == nil {
+		panic(errors.New("Variable 'callback' must be not nil"))
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	callback()
+
+	m.index = nil
+}
+
+// Calls callback with actual index under shared lock, index is rebuilt under exclusive lock if it's required.
+func (m *Storage) readIndex(callback func(index *storageIndex)) {
+	m.mutex.RLock()
+
+	if m.index != nil && !m.isStale(m.index) {
+		defer m.mutex.RUnlock()
+
+		callback(m.index)
+
+		return
+	}
+
+	m.mutex.RUnlock()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	callback(m.getIndex())
+}
+
+// Returns index, it's built if it was dropped or is stale. Must be called under exclusive lock.
+func (m *Storage) getIndex() *storageIndex {
+	if m.index != nil && !m.isStale(m.index) {
+		return m.index
+	}
+
+	m.index = &storageIndex{
+		namespacesByName: map[string]*Namespace{},
+		namespacesByPath: map[string]*Namespace{},
+		namespacesByFile: map[*File]*Namespace{},
+		declarations:     map[string]*Declaration{},
+		namespacesCount:  len(m.Namespaces),
+		filesCounts:      map[*Namespace]int{},
+	}
+
+	for _, namespace := range m.Namespaces {
+		m.indexNamespace(m.index, namespace)
+	}
+
+	return m.index
+}
+
+// Index is stale if namespaces or files were added or removed directly.
+func (m *Storage) isStale(index *storageIndex) bool {
+	if len(m.Namespaces) != index.namespacesCount {
+		return true
+	}
+
+	for _, namespace := range m.Namespaces {
+		if count, ok := index.filesCounts[namespace]; !ok || count != len(namespace.Files) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// First namespace and declaration with the same key are indexed, same as linear search.
+func (m *Storage) indexNamespace(index *storageIndex, namespace *Namespace) {
+	if _, ok := index.namespacesByName[namespace.Name]; !ok {
+		index.namespacesByName[namespace.Name] = namespace
+	}
+
+	if _, ok := index.namespacesByPath[namespace.Path]; !ok {
+		index.namespacesByPath[namespace.Path] = namespace
+	}
+
+	index.filesCounts[namespace] = 0
+
+	for _, file := range namespace.Files {
+		m.indexFile(index, namespace, file)
+	}
+}
+
+func (m *Storage) indexFile(index *storageIndex, namespace *Namespace, file *File) {
+	index.namespacesByFile[file] = namespace
+	index.filesCounts[namespace]++
+
+	for name, entity := range m.fileDeclarations(file) {
+		if _, ok := index.declarations[namespace.Name+"."+name]; !ok {
+			index.declarations[namespace.Name+"."+name] = &Declaration{Namespace: namespace, File: file, Entity: entity}
+		}
+	}
+}
+
+func (m *Storage) unindexFile(index *storageIndex, namespace *Namespace, file *File) {
+	delete(index.namespacesByFile, file)
+	index.filesCounts[namespace]--
+
+	for name := range m.fileDeclarations(file) {
+		if declaration := index.declarations[namespace.Name+"."+name]; declaration != nil && declaration.File == file {
+			delete(index.declarations, namespace.Name+"."+name)
+		}
+	}
+}
+
+// Returns declarations of file by name relative to namespace.
+func (m *Storage) fileDeclarations(file *File) map[string]interface{} {
+	result := map[string]interface{}{}
+	add := func(name string, entity interface{}) {
+		if _, ok := result[name]; !ok && name != "" && name != "_" {
+			result[name] = entity
+		}
+	}
+
+	for _, typeGroup := range file.TypeGroups {
+		for _, element := range typeGroup.Types {
+			add(element.Name, element)
+		}
+	}
+
+	for _, element := range file.Funcs {
+		if element.Related == nil {
+			add(element.Name, element)
+
+			continue
+		}
+
+		if spec, ok := element.Related.Spec.(*SimpleSpec); ok {
+			add(spec.TypeName+"."+element.Name, element)
+		}
+	}
+
+	for _, constGroup := range file.ConstGroups {
+		for _, element := range constGroup.Consts {
+			add(element.Name, element)
+		}
+	}
+
+	for _, varGroup := range file.VarGroups {
+		for _, element := range varGroup.Vars {
+			add(element.Name, element)
+		}
+	}
+
+	return result
+}
+
+// Returns declaration of type, which is referenced by spec in context of file.
+// Spec without package name is searched in namespace of file, in namespaces imported with "." alias
+// and in predeclared types. Spec with package name is searched in namespace of import with the same alias.
+// Returns nil if declaration was not found.
+func (m *Storage) ResolveType(file *File, spec *SimpleSpec) *TypeReference {
+	if file == nil {
+		panic(errors.New("Variable 'file' must be not nil"))
+	}
+
+	if spec == nil {
+		panic(errors.New("Variable 'spec' must be not nil"))
+	}
+
+	if spec.PackageName != "" {
+		for _, importGroup := range file.ImportGroups {
+			for _, element := range importGroup.Imports {
+				if element.RealAlias() != spec.PackageName {
+					continue
+				}
+
+				if result := m.resolveTypeInNamespace(element.Namespace, spec.TypeName); result != nil {
+					return result
+				}
+			}
+		}
+
+		// Alias of import without explicit name could differ from package name
+		for _, importGroup := range file.ImportGroups {
+			for _, element := range importGroup.Imports {
+				namespace := m.FindNamespaceByName(element.Namespace)
+
+				if element.Alias != "" || namespace == nil || namespace.PackageName() != spec.PackageName {
+					continue
+				}
+
+				if result := m.resolveTypeInNamespace(element.Namespace, spec.TypeName); result != nil {
+					return result
+				}
+			}
+		}
+
+		return nil
+	}
+
+	if namespace := m.FindNamespaceByFile(file); namespace != nil {
+		if result := m.resolveTypeInNamespace(namespace.Name, spec.TypeName); result != nil {
+			return result
 		}
 	}
 
+	for _, importGroup := range file.ImportGroups {
+		for _, element := range importGroup.Imports {
+			if element.Alias != "." {
+				continue
+			}
+
+			if result := m.resolveTypeInNamespace(element.Namespace, spec.TypeName); result != nil {
+				return result
+			}
+		}
+	}
+
+	if predeclaredTypes[spec.TypeName] {
+		return &TypeReference{Type: &Type{Name: spec.TypeName}, IsPredeclared: true}
+	}
+
+	return nil
+}
+
+func (m *Storage) resolveTypeInNamespace(namespaceName string, typeName string) *TypeReference {
+	declaration := m.FindDeclaration(namespaceName + "." + typeName)
+
+	if declaration == nil {
+		return nil
+	}
+
+	if typeEntity, ok := declaration.Entity.(*Type); ok {
+		return &TypeReference{Namespace: declaration.Namespace, File: declaration.File, Type: typeEntity}
+	}
+
 	return nil
 }
+
+// Returns types with annotation of the same type as annotationType, annotations of group are inherited.
+func (m *Storage) FindTypesByAnnotation(annotationType interface{}) []*AnnotatedEntity {
+	return m.findByAnnotation(annotationType, func(file *File, add func(entity interface{}, annotations []interface{})) {
+		for _, typeGroup := range file.TypeGroups {
+			for _, element := range typeGroup.Types {
+				add(element, append(append([]interface{}{}, typeGroup.Annotations...), element.Annotations...))
+			}
+		}
+	})
+}
+
+// Returns funcs and methods with annotation of the same type as annotationType.
+func (m *Storage) FindFuncsByAnnotation(annotationType interface{}) []*AnnotatedEntity {
+	return m.findByAnnotation(annotationType, func(file *File, add func(entity interface{}, annotations []interface{})) {
+		for _, element := range file.Funcs {
+			add(element, element.Annotations)
+		}
+	})
+}
+
+// Returns consts with annotation of the same type as annotationType, annotations of group are inherited.
+func (m *Storage) FindConstsByAnnotation(annotationType interface{}) []*AnnotatedEntity {
+	return m.findByAnnotation(annotationType, func(file *File, add func(entity interface{}, annotations []interface{})) {
+		for _, constGroup := range file.ConstGroups {
+			for _, element := range constGroup.Consts {
+				add(element, append(append([]interface{}{}, constGroup.Annotations...), element.Annotations...))
+			}
+		}
+	})
+}
+
+// Returns vars with annotation of the same type as annotationType, annotations of group are inherited.
+func (m *Storage) FindVarsByAnnotation(annotationType interface{}) []*AnnotatedEntity {
+	return m.findByAnnotation(annotationType, func(file *File, add func(entity interface{}, annotations []interface{})) {
+		for _, varGroup := range file.VarGroups {
+			for _, element := range varGroup.Vars {
+				add(element, append(append([]interface{}{}, varGroup.Annotations...), element.Annotations...))
+			}
+		}
+	})
+}
+
+// Returns fields and methods of declared structs and interfaces (including nested ones, e.g. in slices, maps,
+// pointers and funcs) with annotation of the same type as annotationType. Result contains type declaration, which
+// contains field.
+func (m *Storage) FindFieldsByAnnotation(annotationType interface{}) []*AnnotatedEntity {
+	if annotationType == nil {
+		panic(errors.New("Variable 'annotationType' must be not nil"))
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	result := []*AnnotatedEntity{}
+
+	for _, namespace := range m.Namespaces {
+		for _, file := range namespace.Files {
+			for _, typeGroup := range file.TypeGroups {
+				for _, element := range typeGroup.Types {
+					for _, field := range m.findFields(element.Spec) {
+						annotations := m.filterAnnotations(field.Annotations, annotationType)
+
+						if len(annotations) == 0 {
+							continue
+						}
+
+						result = append(
+							result,
+							&AnnotatedEntity{
+								Namespace:   namespace,
+								File:        file,
+								Entity:      field,
+								Type:        element,
+								Annotations: annotations,
+							},
+						)
+					}
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// Returns fields of spec and its nested specs in depth-first order, e.g. fields of []struct{...} or *struct{...}.
+func (m *Storage) findFields(spec interface{}) []*Field {
+	result := []*Field{}
+
+	if spec == nil {
+		return result
+	}
+
+	NewEntityWalker().Inspect(spec, func(entity interface{}, parents []interface{}) bool {
+		if field, ok := entity.(*Field); ok {
+			result = append(result, field)
+		}
+
+		return true
+	})
+
+	return result
+}
+
+func (m *Storage) findByAnnotation(
+	annotationType interface{},
+	iterate func(file *File, add func(entity interface{}, annotations []interface{})),
+) []*AnnotatedEntity {
+	if annotationType == nil {
+		panic(errors.New("Variable 'annotationType' must be not nil"))
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	result := []*AnnotatedEntity{}
+
+	for _, namespace := range m.Namespaces {
+		for _, file := range namespace.Files {
+			iterate(file, func(entity interface{}, annotations []interface{}) {
+				if annotations = m.filterAnnotations(annotations, annotationType); len(annotations) == 0 {
+					return
+				}
+
+				result = append(
+					result,
+					&AnnotatedEntity{Namespace: namespace, File: file, Entity: entity, Annotations: annotations},
+				)
+			})
+		}
+	}
+
+	return result
+}
+
+func (m *Storage) filterAnnotations(annotations []interface{}, annotationType interface{}) []interface{} {
+	result := []interface{}{}
+	expectedType := reflect.TypeOf(annotationType)
+
+	for _, annotation := range annotations {
+		if reflect.TypeOf(annotation) == expectedType {
+			result = append(result, annotation)
+		}
+	}
+
+	return result
+}
@@ -1,6 +1,8 @@
 package annotation
 
 import (
+	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/index0h/go-unit/unit"
@@ -66,3 +68,572 @@ func TestStorage_FindNamespaceByName_WithEmptyName(t *testing.T) {
 		Call(model.FindNamespaceByName, name).
 		ExpectPanic(NewErrorMessageConstraint("Variable 'name' must be not empty"))
 }
+
+func TestStorage_FindNamespaceByFile(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	file := &File{
+		Name:        "fileName",
+		PackageName: "packageName",
+	}
+	expected := &Namespace{
+		Name:  "namespace/packageName",
+		Path:  "/namespace/path",
+		Files: []*File{file},
+	}
+
+	model := &Storage{
+		Namespaces: []*Namespace{
+			{
+				Name: "another",
+				Path: "/another",
+				Files: []*File{
+					{
+						Name:        "fileName",
+						PackageName: "packageName",
+					},
+				},
+			},
+			expected,
+		},
+	}
+
+	ctrl.AssertSame(expected, model.FindNamespaceByFile(file))
+	ctrl.AssertNil(model.FindNamespaceByFile(&File{}))
+}
+
+func TestStorage_FindNamespaceByFile_WithNilFile(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call((&Storage{}).FindNamespaceByFile, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'file' must be not nil"))
+}
+
+func TestStorage_ResolveType(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	localType := &Type{Name: "Local", Spec: &SimpleSpec{TypeName: "int"}}
+	localFile := &File{
+		Name:        "local.go",
+		PackageName: "model",
+		TypeGroups:  []*TypeGroup{{Types: []*Type{localType}}},
+	}
+	file := &File{
+		Name:        "file.go",
+		PackageName: "model",
+		ImportGroups: []*ImportGroup{
+			{
+				Imports: []*Import{
+					{Namespace: "example.com/aliased", Alias: "alias"},
+					{Namespace: "example.com/dot", Alias: "."},
+					{Namespace: "example.com/named-v2"},
+				},
+			},
+		},
+	}
+	modelNamespace := &Namespace{Name: "example.com/model", Path: "/model", Files: []*File{file, localFile}}
+
+	aliasedType := &Type{Name: "Aliased", Spec: &SimpleSpec{TypeName: "int"}}
+	aliasedFile := &File{Name: "a.go", PackageName: "aliased", TypeGroups: []*TypeGroup{{Types: []*Type{aliasedType}}}}
+	aliasedNamespace := &Namespace{Name: "example.com/aliased", Path: "/aliased", Files: []*File{aliasedFile}}
+
+	dotType := &Type{Name: "Dot", Spec: &SimpleSpec{TypeName: "int"}}
+	dotFile := &File{Name: "d.go", PackageName: "dot", TypeGroups: []*TypeGroup{{Types: []*Type{dotType}}}}
+	dotNamespace := &Namespace{Name: "example.com/dot", Path: "/dot", IsIgnored: true, Files: []*File{dotFile}}
+
+	namedType := &Type{Name: "Named", Spec: &SimpleSpec{TypeName: "int"}}
+	namedFile := &File{Name: "n.go", PackageName: "named", TypeGroups: []*TypeGroup{{Types: []*Type{namedType}}}}
+	namedNamespace := &Namespace{Name: "example.com/named-v2", Path: "/named", Files: []*File{namedFile}}
+
+	model := &Storage{
+		Namespaces: []*Namespace{modelNamespace, aliasedNamespace, dotNamespace, namedNamespace},
+	}
+
+	ctrl.AssertEqual(
+		&TypeReference{Namespace: modelNamespace, File: localFile, Type: localType},
+		model.ResolveType(file, &SimpleSpec{TypeName: "Local", IsPointer: true}),
+	)
+	ctrl.AssertEqual(
+		&TypeReference{Namespace: aliasedNamespace, File: aliasedFile, Type: aliasedType},
+		model.ResolveType(file, &SimpleSpec{PackageName: "alias", TypeName: "Aliased"}),
+	)
+	ctrl.AssertEqual(
+		&TypeReference{Namespace: dotNamespace, File: dotFile, Type: dotType},
+		model.ResolveType(file, &SimpleSpec{TypeName: "Dot"}),
+	)
+	ctrl.AssertEqual(
+		&TypeReference{Namespace: namedNamespace, File: namedFile, Type: namedType},
+		model.ResolveType(file, &SimpleSpec{PackageName: "named", TypeName: "Named"}),
+	)
+	ctrl.AssertEqual(
+		&TypeReference{Type: &Type{Name: "string"}, IsPredeclared: true},
+		model.ResolveType(file, &SimpleSpec{TypeName: "string"}),
+	)
+	ctrl.AssertNil(model.ResolveType(file, &SimpleSpec{TypeName: "Unknown"}))
+	ctrl.AssertNil(model.ResolveType(file, &SimpleSpec{PackageName: "alias", TypeName: "Unknown"}))
+	ctrl.AssertNil(model.ResolveType(file, &SimpleSpec{PackageName: "unknown", TypeName: "Aliased"}))
+}
+
+func TestStorage_ResolveType_WithNilFile(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call((&Storage{}).ResolveType, nil, &SimpleSpec{TypeName: "int"}).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'file' must be not nil"))
+}
+
+func TestStorage_ResolveType_WithNilSpec(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call((&Storage{}).ResolveType, &File{}, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'spec' must be not nil"))
+}
+
+func TestStorage_FindByAnnotation(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	annotation := TestAnnotation{Name: "expected"}
+	another := FileIsGeneratedAnnotation(true)
+	nestedField := &Field{Name: "Nested", Annotations: []interface{}{annotation}, Spec: &SimpleSpec{TypeName: "int"}}
+	field := &Field{
+		Name:        "Field",
+		Annotations: []interface{}{another, annotation},
+		Spec:        &StructSpec{Fields: []*Field{nestedField}},
+	}
+	typeEntity := &Type{
+		Name:        "Model",
+		Annotations: []interface{}{annotation},
+		Spec:        &StructSpec{Fields: []*Field{field, {Name: "ID", Spec: &SimpleSpec{TypeName: "int"}}}},
+	}
+	funcEntity := &Func{Name: "function", Annotations: []interface{}{annotation, annotation}}
+	constEntity := &Const{Name: "A", Annotations: []interface{}{annotation}, Value: "1"}
+	varEntity := &Var{Name: "b", Annotations: []interface{}{annotation}, Value: "1"}
+	groupVarEntity := &Var{Name: "c", Annotations: []interface{}{another}, Value: "1"}
+	file := &File{
+		Name:        "file.go",
+		PackageName: "model",
+		ConstGroups: []*ConstGroup{{Consts: []*Const{constEntity, {Name: "B", Value: "2"}}}},
+		VarGroups: []*VarGroup{
+			{Vars: []*Var{{Name: "a", Value: "1"}, varEntity}},
+			{Annotations: []interface{}{annotation}, Vars: []*Var{groupVarEntity}},
+		},
+		TypeGroups: []*TypeGroup{
+			{Types: []*Type{{Name: "Another", Annotations: []interface{}{another}, Spec: &SimpleSpec{TypeName: "int"}}}},
+			{Types: []*Type{typeEntity}},
+		},
+		Funcs: []*Func{funcEntity},
+	}
+	namespace := &Namespace{Name: "model", Path: "/model", Files: []*File{file}}
+	model := &Storage{Namespaces: []*Namespace{{Name: "empty", Path: "/empty"}, namespace}}
+
+	ctrl.AssertEqual(
+		[]*AnnotatedEntity{
+			{Namespace: namespace, File: file, Entity: typeEntity, Annotations: []interface{}{annotation}},
+		},
+		model.FindTypesByAnnotation(TestAnnotation{}),
+	)
+	ctrl.AssertEqual(
+		[]*AnnotatedEntity{
+			{Namespace: namespace, File: file, Entity: funcEntity, Annotations: []interface{}{annotation, annotation}},
+		},
+		model.FindFuncsByAnnotation(TestAnnotation{}),
+	)
+	ctrl.AssertEqual(
+		[]*AnnotatedEntity{
+			{Namespace: namespace, File: file, Entity: constEntity, Annotations: []interface{}{annotation}},
+		},
+		model.FindConstsByAnnotation(TestAnnotation{}),
+	)
+	ctrl.AssertEqual(
+		[]*AnnotatedEntity{
+			{Namespace: namespace, File: file, Entity: varEntity, Annotations: []interface{}{annotation}},
+			{Namespace: namespace, File: file, Entity: groupVarEntity, Annotations: []interface{}{annotation}},
+		},
+		model.FindVarsByAnnotation(TestAnnotation{}),
+	)
+	ctrl.AssertEqual(
+		[]*AnnotatedEntity{
+			{Namespace: namespace, File: file, Entity: field, Type: typeEntity, Annotations: []interface{}{annotation}},
+			{
+				Namespace:   namespace,
+				File:        file,
+				Entity:      nestedField,
+				Type:        typeEntity,
+				Annotations: []interface{}{annotation},
+			},
+		},
+		model.FindFieldsByAnnotation(TestAnnotation{}),
+	)
+	ctrl.AssertEqual([]*AnnotatedEntity{}, model.FindFuncsByAnnotation(another))
+}
+
+func TestStorage_FindFieldsByAnnotation_WithNestedSpecs(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	annotation := TestAnnotation{Name: "expected"}
+	arrayField := &Field{Name: "Array", Annotations: []interface{}{annotation}, Spec: &SimpleSpec{TypeName: "int"}}
+	mapField := &Field{Name: "Map", Annotations: []interface{}{annotation}, Spec: &SimpleSpec{TypeName: "int"}}
+	pointerField := &Field{Name: "Pointer", Annotations: []interface{}{annotation}, Spec: &SimpleSpec{TypeName: "int"}}
+	typeEntity := &Type{
+		Name: "Model",
+		Spec: &StructSpec{
+			Fields: []*Field{
+				{Name: "Items", Spec: &ArraySpec{Value: &StructSpec{Fields: []*Field{arrayField}}}},
+				{
+					Name: "Index",
+					Spec: &MapSpec{
+						Key:   &SimpleSpec{TypeName: "string"},
+						Value: &StructSpec{Fields: []*Field{mapField}},
+					},
+				},
+				{Name: "Parent", Spec: &PointerSpec{Value: &StructSpec{Fields: []*Field{pointerField}}}},
+			},
+		},
+	}
+	file := &File{Name: "file.go", PackageName: "model", TypeGroups: []*TypeGroup{{Types: []*Type{typeEntity}}}}
+	namespace := &Namespace{Name: "model", Path: "/model", Files: []*File{file}}
+	model := &Storage{Namespaces: []*Namespace{namespace}}
+
+	expected := []*AnnotatedEntity{}
+
+	for _, field := range []*Field{arrayField, mapField, pointerField} {
+		expected = append(
+			expected,
+			&AnnotatedEntity{
+				Namespace:   namespace,
+				File:        file,
+				Entity:      field,
+				Type:        typeEntity,
+				Annotations: []interface{}{annotation},
+			},
+		)
+	}
+
+	ctrl.AssertEqual(expected, model.FindFieldsByAnnotation(TestAnnotation{}))
+}
+
+func TestStorage_FindByAnnotation_WithNilAnnotationType(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	model := &Storage{}
+
+	ctrl.Subtest("FindTypesByAnnotation").
+		Call(model.FindTypesByAnnotation, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'annotationType' must be not nil"))
+	ctrl.Subtest("FindFuncsByAnnotation").
+		Call(model.FindFuncsByAnnotation, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'annotationType' must be not nil"))
+	ctrl.Subtest("FindConstsByAnnotation").
+		Call(model.FindConstsByAnnotation, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'annotationType' must be not nil"))
+	ctrl.Subtest("FindVarsByAnnotation").
+		Call(model.FindVarsByAnnotation, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'annotationType' must be not nil"))
+	ctrl.Subtest("FindFieldsByAnnotation").
+		Call(model.FindFieldsByAnnotation, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'annotationType' must be not nil"))
+}
+
+func TestStorage_FindNamespaceByPath(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	expected := &Namespace{Name: "namespace/packageName", Path: "/namespace/path"}
+
+	model := &Storage{Namespaces: []*Namespace{{Name: "another", Path: "/another"}, expected}}
+
+	ctrl.AssertSame(expected, model.FindNamespaceByPath("/namespace/path"))
+	ctrl.AssertNil(model.FindNamespaceByPath("/unknown"))
+}
+
+func TestStorage_FindNamespaceByPath_WithEmptyPath(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call((&Storage{}).FindNamespaceByPath, "").
+		ExpectPanic(NewErrorMessageConstraint("Variable 'path' must be not empty"))
+}
+
+func TestStorage_FindDeclaration(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	typeEntity := &Type{Name: "Model", Spec: &StructSpec{}}
+	funcEntity := &Func{Name: "NewModel", Spec: &FuncSpec{}}
+	methodEntity := &Func{
+		Name:    "Save",
+		Related: &Field{Name: "m", Spec: &SimpleSpec{TypeName: "Model", IsPointer: true}},
+		Spec:    &FuncSpec{},
+	}
+	constEntity := &Const{Name: "A", Value: "1"}
+	varEntity := &Var{Name: "b", Value: "1"}
+	file := &File{
+		Name:        "file.go",
+		PackageName: "model",
+		ConstGroups: []*ConstGroup{{Consts: []*Const{constEntity}}},
+		VarGroups:   []*VarGroup{{Vars: []*Var{varEntity, {Name: "_", Value: "2"}}}},
+		TypeGroups:  []*TypeGroup{{Types: []*Type{typeEntity}}},
+		Funcs:       []*Func{funcEntity, methodEntity},
+	}
+	namespace := &Namespace{Name: "example.com/model", Path: "/model", Files: []*File{file}}
+	model := &Storage{Namespaces: []*Namespace{namespace}}
+
+	ctrl.AssertEqual(
+		&Declaration{Namespace: namespace, File: file, Entity: typeEntity},
+		model.FindDeclaration("example.com/model.Model"),
+	)
+	ctrl.AssertSame(funcEntity, model.FindDeclaration("example.com/model.NewModel").Entity)
+	ctrl.AssertSame(methodEntity, model.FindDeclaration("example.com/model.Model.Save").Entity)
+	ctrl.AssertSame(constEntity, model.FindDeclaration("example.com/model.A").Entity)
+	ctrl.AssertSame(varEntity, model.FindDeclaration("example.com/model.b").Entity)
+	ctrl.AssertNil(model.FindDeclaration("example.com/model._"))
+	ctrl.AssertNil(model.FindDeclaration("example.com/model.Save"))
+	ctrl.AssertNil(model.FindDeclaration("example.com/another.Model"))
+}
+
+func TestStorage_FindDeclaration_WithEmptyQualifiedName(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call((&Storage{}).FindDeclaration, "").
+		ExpectPanic(NewErrorMessageConstraint("Variable 'qualifiedName' must be not empty"))
+}
+
+func TestStorage_AddNamespace(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	typeEntity := &Type{Name: "Model", Spec: &SimpleSpec{TypeName: "int"}}
+	file := &File{Name: "file.go", PackageName: "model", TypeGroups: []*TypeGroup{{Types: []*Type{typeEntity}}}}
+	namespace := &Namespace{Name: "example.com/model", Path: "/model", Files: []*File{file}}
+	model := &Storage{}
+
+	ctrl.AssertNil(model.FindNamespaceByName("example.com/model"))
+
+	model.AddNamespace(namespace)
+
+	ctrl.AssertEqual([]*Namespace{namespace}, model.Namespaces)
+	ctrl.AssertSame(namespace, model.FindNamespaceByName("example.com/model"))
+	ctrl.AssertSame(namespace, model.FindNamespaceByPath("/model"))
+	ctrl.AssertSame(namespace, model.FindNamespaceByFile(file))
+	ctrl.AssertSame(typeEntity, model.FindDeclaration("example.com/model.Model").Entity)
+}
+
+func TestStorage_AddNamespace_WithDuplicate(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	model := &Storage{Namespaces: []*Namespace{{Name: "model", Path: "/model"}}}
+
+	ctrl.Subtest("Name").
+		Call(model.AddNamespace, &Namespace{Name: "model", Path: "/another"}).
+		ExpectPanic(NewErrorMessageConstraint("Storage already has namespace with name: 'model'"))
+	ctrl.Subtest("Path").
+		Call(model.AddNamespace, &Namespace{Name: "another", Path: "/model"}).
+		ExpectPanic(NewErrorMessageConstraint("Storage already has namespace with path: '/model'"))
+	ctrl.Subtest("Nil").
+		Call(model.AddNamespace, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'namespace' must be not nil"))
+}
+
+func TestStorage_RemoveNamespace(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	file := &File{Name: "file.go", PackageName: "model", Funcs: []*Func{{Name: "Run", Spec: &FuncSpec{}}}}
+	namespace := &Namespace{Name: "example.com/model", Path: "/model", Files: []*File{file}}
+	another := &Namespace{Name: "example.com/another", Path: "/another"}
+	model := &Storage{Namespaces: []*Namespace{namespace, another}}
+
+	ctrl.AssertNotNil(model.FindDeclaration("example.com/model.Run"))
+
+	model.RemoveNamespace(namespace)
+	model.RemoveNamespace(&Namespace{Name: "example.com/unknown"})
+
+	ctrl.AssertEqual([]*Namespace{another}, model.Namespaces)
+	ctrl.AssertNil(model.FindNamespaceByName("example.com/model"))
+	ctrl.AssertNil(model.FindNamespaceByPath("/model"))
+	ctrl.AssertNil(model.FindNamespaceByFile(file))
+	ctrl.AssertNil(model.FindDeclaration("example.com/model.Run"))
+	ctrl.AssertSame(another, model.FindNamespaceByName("example.com/another"))
+
+	ctrl.Subtest("").
+		Call(model.RemoveNamespace, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'namespace' must be not nil"))
+}
+
+func TestStorage_AddFile(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	exists := &File{Name: "exists.go", PackageName: "model"}
+	namespace := &Namespace{Name: "example.com/model", Path: "/model", Files: []*File{exists}}
+	typeEntity := &Type{Name: "Model", Spec: &SimpleSpec{TypeName: "int"}}
+	file := &File{Name: "file.go", PackageName: "model", TypeGroups: []*TypeGroup{{Types: []*Type{typeEntity}}}}
+	model := &Storage{Namespaces: []*Namespace{namespace}}
+
+	ctrl.AssertNil(model.ResolveType(exists, &SimpleSpec{TypeName: "Model"}))
+
+	model.AddFile(namespace, file)
+
+	ctrl.AssertEqual([]*File{exists, file}, namespace.Files)
+	ctrl.AssertSame(namespace, model.FindNamespaceByFile(file))
+	ctrl.AssertEqual(
+		&TypeReference{Namespace: namespace, File: file, Type: typeEntity},
+		model.ResolveType(exists, &SimpleSpec{TypeName: "Model"}),
+	)
+
+	ctrl.Subtest("Duplicate").
+		Call(model.AddFile, namespace, &File{Name: "file.go"}).
+		ExpectPanic(NewErrorMessageConstraint("Namespace 'example.com/model' already has file with name: 'file.go'"))
+	ctrl.Subtest("Unknown").
+		Call(model.AddFile, &Namespace{Name: "example.com/model", Path: "/model"}, &File{Name: "new.go"}).
+		ExpectPanic(NewErrorMessageConstraint("Storage has no namespace with name: 'example.com/model'"))
+	ctrl.Subtest("NilNamespace").
+		Call(model.AddFile, nil, &File{Name: "new.go"}).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'namespace' must be not nil"))
+	ctrl.Subtest("NilFile").
+		Call(model.AddFile, namespace, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'file' must be not nil"))
+}
+
+func TestStorage_RemoveFile(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	first := &File{
+		Name:        "first.go",
+		PackageName: "model",
+		TypeGroups:  []*TypeGroup{{Types: []*Type{{Name: "Model", Spec: &SimpleSpec{TypeName: "int"}}}}},
+	}
+	secondType := &Type{Name: "Model", Spec: &SimpleSpec{TypeName: "string"}}
+	second := &File{Name: "second.go", PackageName: "model", TypeGroups: []*TypeGroup{{Types: []*Type{secondType}}}}
+	namespace := &Namespace{Name: "example.com/model", Path: "/model", Files: []*File{first, second}}
+	model := &Storage{Namespaces: []*Namespace{namespace}}
+
+	model.RemoveFile(second)
+	model.RemoveFile(&File{Name: "unknown.go"})
+
+	ctrl.AssertEqual([]*File{first}, namespace.Files)
+	ctrl.AssertNil(model.FindNamespaceByFile(second))
+	ctrl.AssertSame(first, model.FindDeclaration("example.com/model.Model").File)
+
+	model.RemoveFile(first)
+
+	ctrl.AssertEqual([]*File{}, namespace.Files)
+	ctrl.AssertNil(model.FindDeclaration("example.com/model.Model"))
+
+	ctrl.Subtest("").
+		Call(model.RemoveFile, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'file' must be not nil"))
+}
+
+func TestStorage_Reindex(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	namespace := &Namespace{Name: "example.com/model", Path: "/model"}
+	model := &Storage{Namespaces: []*Namespace{namespace}}
+
+	ctrl.AssertNil(model.FindDeclaration("example.com/model.Run"))
+
+	// Directly added files and namespaces are indexed on next lookup
+	file := &File{Name: "file.go", Funcs: []*Func{{Name: "Run", Spec: &FuncSpec{}}}}
+	namespace.Files = append(namespace.Files, file)
+
+	ctrl.AssertNotNil(model.FindDeclaration("example.com/model.Run"))
+
+	added := &Namespace{Name: "example.com/added", Path: "/added"}
+	model.Namespaces = append(model.Namespaces, added)
+
+	ctrl.AssertSame(added, model.FindNamespaceByName("example.com/added"))
+
+	replaced := &Namespace{Name: "example.com/replaced", Path: "/replaced"}
+	model.Namespaces[1] = replaced
+
+	ctrl.AssertNil(model.FindNamespaceByName("example.com/added"))
+	ctrl.AssertSame(replaced, model.FindNamespaceByName("example.com/replaced"))
+
+	// Declarations of indexed files are indexed again only after Reindex
+	file.Funcs = append(file.Funcs, &Func{Name: "Stop", Spec: &FuncSpec{}})
+
+	ctrl.AssertNil(model.FindDeclaration("example.com/model.Stop"))
+
+	model.Reindex()
+
+	ctrl.AssertNotNil(model.FindDeclaration("example.com/model.Stop"))
+}
+
+func TestStorage_ReadUpdate(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	namespace := &Namespace{Name: "example.com/model", Path: "/model"}
+	model := &Storage{Namespaces: []*Namespace{namespace}}
+	count := 0
+
+	ctrl.AssertNil(model.FindDeclaration("example.com/model.Run"))
+
+	model.Update(func() {
+		namespace.Files = append(namespace.Files, &File{Name: "file.go", Funcs: []*Func{{Name: "Run", Spec: &FuncSpec{}}}})
+	})
+	model.Read(func() {
+		count = len(namespace.Files)
+	})
+
+	ctrl.AssertSame(1, count)
+	ctrl.AssertNotNil(model.FindDeclaration("example.com/model.Run"))
+
+	ctrl.Subtest("Read").
+		Call(model.Read, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'callback' must be not nil"))
+	ctrl.Subtest("Update").
+		Call(model.Update, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'callback' must be not nil"))
+}
+
+func TestStorage_WithConcurrentAccess(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	namespace := &Namespace{Name: "example.com/model", Path: "/model"}
+	model := &Storage{Namespaces: []*Namespace{namespace}}
+	group := sync.WaitGroup{}
+
+	for i := 0; i < 20; i++ {
+		group.Add(2)
+
+		go func(name string) {
+			defer group.Done()
+
+			model.AddFile(namespace, &File{Name: name + ".go", Funcs: []*Func{{Name: name, Spec: &FuncSpec{}}}})
+		}("F" + strconv.Itoa(i))
+
+		go func(name string) {
+			defer group.Done()
+
+			model.FindDeclaration("example.com/model." + name)
+			model.FindFuncsByAnnotation(TestAnnotation{})
+		}("F" + strconv.Itoa(i))
+	}
+
+	group.Wait()
+
+	ctrl.AssertSame(20, len(namespace.Files))
+
+	for i := 0; i < 20; i++ {
+		ctrl.AssertNotNil(model.FindDeclaration("example.com/model.F" + strconv.Itoa(i)))
+	}
+}
@@ -0,0 +1,151 @@
+package annotation
+
+type TestConcurrentGenerator struct {
+	IsConcurrentValue bool
+	Callback          func(application *Application)
+}
+
+func (g *TestConcurrentGenerator) Annotations() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+func (g *TestConcurrentGenerator) Generate(application *Application) {
+	g.Callback(application)
+}
+
+func (g *TestConcurrentGenerator) IsConcurrent() bool {
+	return g.IsConcurrentValue
+}
+
+type TestDependentGenerator struct {
+	NameValue         string
+	DependenciesValue []string
+	AnnotationsValue  map[string]interface{}
+	Callback          func(application *Application)
+}
+
+func (g *TestDependentGenerator) Annotations() map[string]interface{} {
+	return g.AnnotationsValue
+}
+
+func (g *TestDependentGenerator) Generate(application *Application) {
+	if g.Callback != nil {
+		g.Callback(application)
+	}
+}
+
+func (g *TestDependentGenerator) Name() string {
+	return g.NameValue
+}
+
+func (g *TestDependentGenerator) Dependencies() []string {
+	return g.DependenciesValue
+}
+
+type TestHookGenerator struct {
+	Calls []string
+}
+
+func (g *TestHookGenerator) Annotations() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+func (g *TestHookGenerator) Generate(application *Application) {
+	g.Calls = append(g.Calls, "Generate")
+}
+
+func (g *TestHookGenerator) AfterScan(application *Application) {
+	g.Calls = append(g.Calls, "AfterScan")
+}
+
+func (g *TestHookGenerator) BeforeGenerate(application *Application) {
+	g.Calls = append(g.Calls, "BeforeGenerate")
+}
+
+func (g *TestHookGenerator) BeforeWrite(
+	application *Application,
+	namespace *Namespace,
+	file *File,
+	content string,
+) string {
+	g.Calls = append(g.Calls, "BeforeWrite "+file.Name)
+
+	return content + "// " + namespace.Name + "\n"
+}
+
+func (g *TestHookGenerator) AfterWrite(application *Application) {
+	g.Calls = append(g.Calls, "AfterWrite")
+}
+
+type TestContextGenerator struct {
+	NameValue string
+	Callback  func(context *GeneratorContext)
+}
+
+func (g *TestContextGenerator) Annotations() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+func (g *TestContextGenerator) Generate(application *Application) {
+	panic("Generate must not be called")
+}
+
+func (g *TestContextGenerator) GenerateWithContext(context *GeneratorContext) {
+	g.Callback(context)
+}
+
+func (g *TestContextGenerator) Name() string {
+	return g.NameValue
+}
+
+type TestConcurrentContextGenerator struct {
+	TestContextGenerator
+}
+
+func (g *TestConcurrentContextGenerator) IsConcurrent() bool {
+	return true
+}
+
+type TestConfigurableGenerator struct {
+	NameValue string
+	Options   struct {
+		Suffix string `json:"suffix"`
+	}
+}
+
+func (g *TestConfigurableGenerator) Annotations() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+func (g *TestConfigurableGenerator) Generate(application *Application) {
+}
+
+func (g *TestConfigurableGenerator) Name() string {
+	return g.NameValue
+}
+
+func (g *TestConfigurableGenerator) ConfigOptions() interface{} {
+	return &g.Options
+}
+
+type TestVersionedGenerator struct {
+	NameValue    string
+	VersionValue string
+	Callback     func(application *Application)
+}
+
+func (g *TestVersionedGenerator) Annotations() map[string]interface{} {
+	return map[string]interface{}{}
+}
+
+func (g *TestVersionedGenerator) Generate(application *Application) {
+	g.Callback(application)
+}
+
+func (g *TestVersionedGenerator) Name() string {
+	return g.NameValue
+}
+
+func (g *TestVersionedGenerator) Version() string {
+	return g.VersionValue
+}
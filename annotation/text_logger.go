@@ -0,0 +1,125 @@
+package annotation
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Levels of log messages.
+const (
+	LogLevelDebug   = "debug"
+	LogLevelInfo    = "info"
+	LogLevelWarning = "warning"
+	LogLevelError   = "error"
+)
+
+// Severity of log levels, messages with level lower than level of logger are skipped.
+var logLevelSeverities = map[string]int{
+	LogLevelDebug:   0,
+	LogLevelInfo:    1,
+	LogLevelWarning: 2,
+	LogLevelError:   3,
+}
+
+// Writes log messages as lines of key=value pairs, e.g.: level=info message="File is created" name=model.go
+type TextLogger struct {
+	writer io.Writer
+	fields []interface{}
+	level  string
+	mutex  *sync.Mutex
+}
+
+// Creates new instance of TextLogger, which writes messages with level LogLevelInfo and higher.
+func NewTextLogger(writer io.Writer) *TextLogger {
+	if writer == nil {
+		panic(errors.New("Variable 'writer' must be not nil"))
+	}
+
+	return &TextLogger{
+		writer: writer,
+		fields: []interface{}{},
+		level:  LogLevelInfo,
+		mutex:  &sync.Mutex{},
+	}
+}
+
+// Sets minimal level of written messages.
+func (l *TextLogger) SetLevel(level string) {
+	if _, ok := logLevelSeverities[level]; !ok {
+		panic(errors.Errorf("Variable 'level' has unknown value: '%s'", level))
+	}
+
+	l.level = level
+}
+
+func (l *TextLogger) Debug(message string, fields ...interface{}) {
+	l.log(LogLevelDebug, message, fields)
+}
+
+func (l *TextLogger) Info(message string, fields ...interface{}) {
+	l.log(LogLevelInfo, message, fields)
+}
+
+func (l *TextLogger) Warning(message string, fields ...interface{}) {
+	l.log(LogLevelWarning, message, fields)
+}
+
+func (l *TextLogger) Error(message string, fields ...interface{}) {
+	l.log(LogLevelError, message, fields)
+}
+
+// Returns logger, which adds fields (pairs of key and value) to each message, writer is shared and level is copied.
+func (l *TextLogger) With(fields ...interface{}) Logger {
+	l.validateFields(fields)
+
+	return &TextLogger{
+		writer: l.writer,
+		fields: append(l.fields[:len(l.fields):len(l.fields)], fields...),
+		level:  l.level,
+		mutex:  l.mutex,
+	}
+}
+
+func (l *TextLogger) log(level string, message string, fields []interface{}) {
+	l.validateFields(fields)
+
+	if logLevelSeverities[level] < logLevelSeverities[l.level] {
+		return
+	}
+
+	parts := []string{"level=" + level, "message=" + l.formatValue(message)}
+	allFields := append(l.fields[:len(l.fields):len(l.fields)], fields...)
+
+	for i := 0; i < len(allFields); i += 2 {
+		parts = append(parts, fmt.Sprint(allFields[i])+"="+l.formatValue(allFields[i+1]))
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if _, err := io.WriteString(l.writer, strings.Join(parts, " ")+"\n"); err != nil {
+		panic(err)
+	}
+}
+
+func (l *TextLogger) validateFields(fields []interface{}) {
+	if len(fields)%2 != 0 {
+		panic(errors.Errorf("Variable 'fields' must have even length, actual value: '%d'", len(fields)))
+	}
+}
+
+// Values with spaces, quotes or equal signs are quoted.
+func (l *TextLogger) formatValue(value interface{}) string {
+	result := fmt.Sprint(value)
+
+	if result == "" || strings.ContainsAny(result, " \t\n\"=") {
+		return strconv.Quote(result)
+	}
+
+	return result
+}
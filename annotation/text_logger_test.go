@@ -0,0 +1,97 @@
+package annotation
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestNewTextLogger(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	writer := &bytes.Buffer{}
+
+	actual := NewTextLogger(writer)
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertSame(writer, actual.writer)
+	ctrl.AssertEqual([]interface{}{}, actual.fields)
+	ctrl.AssertSame(LogLevelInfo, actual.level)
+}
+
+func TestNewTextLogger_WithNilWriter(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewTextLogger, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'writer' must be not nil"))
+}
+
+func TestTextLogger(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	writer := &bytes.Buffer{}
+	logger := NewTextLogger(writer)
+	logger.SetLevel(LogLevelDebug)
+	generatorLogger := logger.With("generator", "mock")
+
+	logger.Debug("debug")
+	logger.Info("File is created", "file", "model.go", "count", 2)
+	generatorLogger.Warning("warning", "value", "a=b")
+	generatorLogger.Error("error", "value", "")
+
+	ctrl.AssertSame(
+		"level=debug message=debug\n"+
+			"level=info message=\"File is created\" file=model.go count=2\n"+
+			"level=warning message=warning generator=mock value=\"a=b\"\n"+
+			"level=error message=error generator=mock value=\"\"\n",
+		writer.String(),
+	)
+}
+
+func TestTextLogger_SetLevel(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	writer := &bytes.Buffer{}
+	logger := NewTextLogger(writer)
+
+	logger.Debug("debug")
+	logger.Info("info")
+
+	logger.SetLevel(LogLevelWarning)
+	generatorLogger := logger.With("generator", "mock")
+
+	generatorLogger.Info("info")
+	generatorLogger.Warning("warning")
+	logger.Error("error")
+
+	ctrl.AssertSame(
+		"level=info message=info\n"+
+			"level=warning message=warning generator=mock\n"+
+			"level=error message=error\n",
+		writer.String(),
+	)
+
+	ctrl.Subtest("").
+		Call(logger.SetLevel, "unknown").
+		ExpectPanic(NewErrorMessageConstraint("Variable 'level' has unknown value: 'unknown'"))
+}
+
+func TestTextLogger_WithOddFields(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	logger := NewTextLogger(&bytes.Buffer{})
+
+	ctrl.Subtest("Info").
+		Call(logger.Info, "message", "key").
+		ExpectPanic(NewErrorMessageConstraint("Variable 'fields' must have even length, actual value: '1'"))
+	ctrl.Subtest("With").
+		Call(logger.With, "key", "value", "key").
+		ExpectPanic(NewErrorMessageConstraint("Variable 'fields' must have even length, actual value: '3'"))
+}
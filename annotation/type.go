@@ -5,6 +5,6 @@ type Type struct {
 	Name        string
 	Comment     string
 	Annotations []interface{}
-	// Allowed types: *SimpleSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec.
+	// Allowed types: *SimpleSpec, *PointerSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec.
 	Spec interface{}
 }
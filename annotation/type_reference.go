@@ -0,0 +1,10 @@
+package annotation
+
+// TypeReference represents declaration of type, which is referenced by *SimpleSpec.
+type TypeReference struct {
+	// Namespace and File are nil for predeclared type, Type of predeclared type has only name.
+	Namespace     *Namespace
+	File          *File
+	Type          *Type
+	IsPredeclared bool
+}
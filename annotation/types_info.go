@@ -0,0 +1,84 @@
+package annotation
+
+import (
+	"go/types"
+
+	"github.com/pkg/errors"
+)
+
+// EntityTypeInfo contains type information resolved by go/types for entity.
+type EntityTypeInfo struct {
+	// Identity of declaration: *types.TypeName, *types.Func, *types.Const or *types.Var.
+	Object types.Object
+	Type   types.Type
+	// Allowed types: *SimpleSpec, *PointerSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec or nil.
+	UnderlyingSpec interface{}
+	// Exact constant value for *Const, otherwise empty.
+	ConstValue string
+	// Method sets for value and pointer of *Type, each method has *FuncSpec.
+	Methods        []*Field
+	PointerMethods []*Field
+}
+
+// TypesInfo stores type information of entities, model of entities is not changed.
+type TypesInfo struct {
+	// Type checked packages by namespace name.
+	Packages map[string]*types.Package
+	// Errors of type checking, which was not fatal.
+	Errors  []error
+	entries map[interface{}]*EntityTypeInfo
+}
+
+// Creates new instance of TypesInfo.
+func NewTypesInfo() *TypesInfo {
+	return &TypesInfo{
+		Packages: map[string]*types.Package{},
+		Errors:   []error{},
+		entries:  map[interface{}]*EntityTypeInfo{},
+	}
+}
+
+// Returns type information of entity (*Type, *Func, *Const, *Var or *Field of struct), or nil if it's unknown.
+func (i *TypesInfo) Find(entity interface{}) *EntityTypeInfo {
+	if entity == nil {
+		panic(errors.New("Variable 'entity' must be not nil"))
+	}
+
+	return i.entries[entity]
+}
+
+// Stores type information of entity.
+func (i *TypesInfo) Set(entity interface{}, info *EntityTypeInfo) {
+	if entity == nil {
+		panic(errors.New("Variable 'entity' must be not nil"))
+	}
+
+	if info == nil {
+		panic(errors.New("Variable 'info' must be not nil"))
+	}
+
+	i.entries[entity] = info
+}
+
+// Checks, that type of entity implements interface. Pointer of *Type declaration is checked too.
+func (i *TypesInfo) Implements(entity interface{}, iface *types.Interface) bool {
+	if iface == nil {
+		panic(errors.New("Variable 'iface' must be not nil"))
+	}
+
+	info := i.Find(entity)
+
+	if info == nil || info.Type == nil {
+		return false
+	}
+
+	if types.Implements(info.Type, iface) {
+		return true
+	}
+
+	if _, ok := info.Object.(*types.TypeName); ok {
+		return types.Implements(types.NewPointer(info.Type), iface)
+	}
+
+	return false
+}
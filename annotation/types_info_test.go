@@ -0,0 +1,66 @@
+package annotation
+
+import (
+	"go/types"
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestNewTypesInfo(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	actual := NewTypesInfo()
+
+	ctrl.AssertNotNil(actual)
+	ctrl.AssertEqual(map[string]*types.Package{}, actual.Packages)
+	ctrl.AssertEqual([]error{}, actual.Errors)
+	ctrl.AssertEqual(map[interface{}]*EntityTypeInfo{}, actual.entries)
+}
+
+func TestTypesInfo_Find(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &Type{Name: "Model", Spec: &SimpleSpec{TypeName: "int"}}
+	expected := &EntityTypeInfo{Type: types.Typ[types.Int]}
+	typesInfo := NewTypesInfo()
+
+	typesInfo.Set(entity, expected)
+
+	ctrl.AssertSame(expected, typesInfo.Find(entity))
+	ctrl.AssertNil(typesInfo.Find(&Type{Name: "Model", Spec: &SimpleSpec{TypeName: "int"}}))
+}
+
+func TestTypesInfo_Find_WithNilEntity(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewTypesInfo().Find, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'entity' must be not nil"))
+}
+
+func TestTypesInfo_Set_WithNilInfo(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewTypesInfo().Set, &Type{}, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'info' must be not nil"))
+}
+
+func TestTypesInfo_Implements(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	entity := &Var{Name: "err", Spec: &SimpleSpec{TypeName: "error"}}
+	errorType := types.Universe.Lookup("error").Type()
+	typesInfo := NewTypesInfo()
+
+	typesInfo.Set(entity, &EntityTypeInfo{Type: errorType})
+
+	ctrl.AssertTrue(typesInfo.Implements(entity, errorType.Underlying().(*types.Interface)))
+	ctrl.AssertFalse(typesInfo.Implements(&Var{}, errorType.Underlying().(*types.Interface)))
+}
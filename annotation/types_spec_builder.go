@@ -0,0 +1,272 @@
+package annotation
+
+import (
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// Builds spec models by go/types types.
+type TypesSpecBuilder struct {
+}
+
+// Creates new instance of TypesSpecBuilder.
+func NewTypesSpecBuilder() *TypesSpecBuilder {
+	return &TypesSpecBuilder{}
+}
+
+// Builds spec, which references typ from code of pkg package. Pkg could be nil, than all named types are qualified.
+// Imports of packages of named types are returned in order of first usage, packages with the same names get aliases
+// with numeric suffix, e.g. "template_2".
+func (b *TypesSpecBuilder) Build(pkg *types.Package, typ types.Type) (spec interface{}, imports []*Import) {
+	if typ == nil {
+		panic(errors.New("Variable 'typ' must be not nil"))
+	}
+
+	imports = []*Import{}
+
+	if spec = b.build(pkg, typ, &imports); spec == nil {
+		panic(errors.Errorf("Variable 'typ' has not allowed type: %s", typ))
+	}
+
+	return spec, imports
+}
+
+// Builds spec of underlying type of typ, e.g. *StructSpec for named struct.
+func (b *TypesSpecBuilder) BuildUnderlying(pkg *types.Package, typ types.Type) (spec interface{}, imports []*Import) {
+	if typ == nil {
+		panic(errors.New("Variable 'typ' must be not nil"))
+	}
+
+	return b.Build(pkg, typ.Underlying())
+}
+
+// Returns nil for types, which could not be represented by specs, e.g. channels or generics.
+func (b *TypesSpecBuilder) build(pkg *types.Package, typ types.Type, imports *[]*Import) interface{} {
+	switch typ := typ.(type) {
+	case *types.Basic:
+		return b.buildBasic(typ, imports)
+	case *types.Named:
+		// Specs have no type arguments
+		if typeListLen(typ, "TypeArgs") > 0 {
+			return nil
+		}
+
+		return b.buildNamed(pkg, typ, imports)
+	case *types.Pointer:
+		value := b.build(pkg, typ.Elem(), imports)
+
+		if spec, ok := value.(*SimpleSpec); ok && !spec.IsPointer {
+			spec.IsPointer = true
+
+			return spec
+		}
+
+		if value != nil {
+			return &PointerSpec{Value: value}
+		}
+	case *types.Slice:
+		if value := b.build(pkg, typ.Elem(), imports); value != nil {
+			return &ArraySpec{Value: value}
+		}
+	case *types.Array:
+		if value := b.build(pkg, typ.Elem(), imports); value != nil {
+			return &ArraySpec{Value: value, Length: strconv.FormatInt(typ.Len(), 10)}
+		}
+	case *types.Map:
+		key := b.build(pkg, typ.Key(), imports)
+		value := b.build(pkg, typ.Elem(), imports)
+
+		if key != nil && value != nil {
+			return &MapSpec{Key: key, Value: value}
+		}
+	case *types.Signature:
+		if typeListLen(typ, "TypeParams") > 0 {
+			return nil
+		}
+
+		if spec := b.buildSignature(pkg, typ, imports); spec != nil {
+			return spec
+		}
+	case *types.Struct:
+		if spec := b.buildStruct(pkg, typ, imports); spec != nil {
+			return spec
+		}
+	case *types.Interface:
+		if spec := b.buildInterface(pkg, typ, imports); spec != nil {
+			return spec
+		}
+	case *types.Chan, *types.Tuple:
+		return nil
+	default:
+		// Type parameter is not a type, which could be referenced outside of its declaration
+		if _, ok := typ.(interface{ Constraint() types.Type }); ok {
+			return nil
+		}
+
+		// Aliases and other wrappers are represented by their underlying type
+		if underlying := typ.Underlying(); underlying != nil && underlying != typ {
+			return b.build(pkg, underlying, imports)
+		}
+	}
+
+	return nil
+}
+
+func (b *TypesSpecBuilder) buildBasic(typ *types.Basic, imports *[]*Import) interface{} {
+	if typ.Kind() == types.UnsafePointer {
+		return &SimpleSpec{PackageName: b.addImport(imports, types.Unsafe), TypeName: typ.Name()}
+	}
+
+	if typ.Info()&types.IsUntyped != 0 {
+		typ = types.Default(typ).(*types.Basic)
+	}
+
+	if typ.Kind() == types.UntypedNil || typ.Kind() == types.Invalid {
+		return nil
+	}
+
+	return &SimpleSpec{TypeName: typ.Name()}
+}
+
+func (b *TypesSpecBuilder) buildNamed(pkg *types.Package, typ *types.Named, imports *[]*Import) *SimpleSpec {
+	object := typ.Obj()
+	result := &SimpleSpec{
+		TypeName: object.Name(),
+	}
+
+	if object.Pkg() == nil || object.Pkg() == pkg || (pkg != nil && object.Pkg().Path() == pkg.Path()) {
+		return result
+	}
+
+	result.PackageName = b.addImport(imports, object.Pkg())
+
+	return result
+}
+
+// Returns alias of pkg in imports, pkg is added to imports if it's absent.
+func (b *TypesSpecBuilder) addImport(imports *[]*Import, pkg *types.Package) string {
+	for _, element := range *imports {
+		if element.Namespace == pkg.Path() {
+			return element.RealAlias()
+		}
+	}
+
+	element := &Import{
+		Namespace: pkg.Path(),
+	}
+
+	if alias := uniqueAlias(*imports, pkg.Name()); filepath.Base(element.Namespace) != alias {
+		element.Alias = alias
+	}
+
+	*imports = append(*imports, element)
+
+	return element.RealAlias()
+}
+
+// Returns length of list, which is returned by method of typ, e.g. "TypeArgs" of *types.Named.
+// Method is called by reflection, because go/types has no generics before go 1.18.
+func typeListLen(typ types.Type, method string) int {
+	value := reflect.ValueOf(typ).MethodByName(method)
+
+	if !value.IsValid() {
+		return 0
+	}
+
+	return int(value.Call(nil)[0].MethodByName("Len").Call(nil)[0].Int())
+}
+
+func (b *TypesSpecBuilder) buildSignature(pkg *types.Package, typ *types.Signature, imports *[]*Import) *FuncSpec {
+	params := b.buildTuple(pkg, typ.Params(), imports)
+	results := b.buildTuple(pkg, typ.Results(), imports)
+
+	if params == nil || results == nil {
+		return nil
+	}
+
+	return &FuncSpec{
+		Params:     params,
+		Results:    results,
+		IsVariadic: typ.Variadic(),
+	}
+}
+
+func (b *TypesSpecBuilder) buildTuple(pkg *types.Package, tuple *types.Tuple, imports *[]*Import) []*Field {
+	result := make([]*Field, tuple.Len())
+
+	for i := range result {
+		spec := b.build(pkg, tuple.At(i).Type(), imports)
+
+		if spec == nil {
+			return nil
+		}
+
+		result[i] = &Field{
+			Name: tuple.At(i).Name(),
+			Spec: spec,
+		}
+	}
+
+	return result
+}
+
+func (b *TypesSpecBuilder) buildStruct(pkg *types.Package, typ *types.Struct, imports *[]*Import) *StructSpec {
+	result := &StructSpec{
+		Fields: make([]*Field, typ.NumFields()),
+	}
+
+	for i := range result.Fields {
+		field := typ.Field(i)
+		spec := b.build(pkg, field.Type(), imports)
+
+		if spec == nil {
+			return nil
+		}
+
+		result.Fields[i] = &Field{
+			Tag:  typ.Tag(i),
+			Spec: spec,
+		}
+
+		if field.Anonymous() {
+			result.Fields[i].IsEmbedded = true
+		} else {
+			result.Fields[i].Name = field.Name()
+		}
+	}
+
+	return result
+}
+
+func (b *TypesSpecBuilder) buildInterface(pkg *types.Package, typ *types.Interface, imports *[]*Import) *InterfaceSpec {
+	result := &InterfaceSpec{
+		Fields: make([]*Field, 0, typ.NumEmbeddeds()+typ.NumExplicitMethods()),
+	}
+
+	for i := 0; i < typ.NumEmbeddeds(); i++ {
+		spec, ok := b.build(pkg, typ.EmbeddedType(i), imports).(*SimpleSpec)
+
+		if !ok {
+			return nil
+		}
+
+		result.Fields = append(result.Fields, &Field{Spec: spec, IsEmbedded: true})
+	}
+
+	for i := 0; i < typ.NumExplicitMethods(); i++ {
+		method := typ.ExplicitMethod(i)
+		spec := b.buildSignature(pkg, method.Type().(*types.Signature), imports)
+
+		if spec == nil {
+			return nil
+		}
+
+		result.Fields = append(result.Fields, &Field{Name: method.Name(), Spec: spec})
+	}
+
+	return result
+}
@@ -0,0 +1,212 @@
+package annotation
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/index0h/go-unit/unit"
+)
+
+func TestNewTypesSpecBuilder(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	actual := NewTypesSpecBuilder()
+
+	ctrl.AssertNotNil(actual)
+}
+
+func TestTypesSpecBuilder_Build(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	pkg := checkTypesPackage(
+		t,
+		"package model\n\nimport \"io\"\n\ntype Model struct{}\n\nvar X map[string][]*Model\n\n"+
+			"var Y func(io.Reader, ...int) (n int, err error)\n",
+	)
+
+	actualSpec, actualImports := NewTypesSpecBuilder().Build(pkg, pkg.Scope().Lookup("X").Type())
+
+	ctrl.AssertEqual(
+		&MapSpec{
+			Key:   &SimpleSpec{TypeName: "string"},
+			Value: &ArraySpec{Value: &SimpleSpec{TypeName: "Model", IsPointer: true}},
+		},
+		actualSpec,
+	)
+	ctrl.AssertEqual([]*Import{}, actualImports)
+
+	actualSpec, actualImports = NewTypesSpecBuilder().Build(pkg, pkg.Scope().Lookup("Y").Type())
+
+	ctrl.AssertEqual(
+		&FuncSpec{
+			Params: []*Field{
+				{Spec: &SimpleSpec{PackageName: "io", TypeName: "Reader"}},
+				{Spec: &ArraySpec{Value: &SimpleSpec{TypeName: "int"}}},
+			},
+			Results: []*Field{
+				{Name: "n", Spec: &SimpleSpec{TypeName: "int"}},
+				{Name: "err", Spec: &SimpleSpec{TypeName: "error"}},
+			},
+			IsVariadic: true,
+		},
+		actualSpec,
+	)
+	ctrl.AssertEqual([]*Import{{Namespace: "io"}}, actualImports)
+}
+
+func TestTypesSpecBuilder_Build_WithNilPackage(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	pkg := checkTypesPackage(t, "package model\n\ntype Model struct{}\n\nvar X Model\n")
+
+	actualSpec, actualImports := NewTypesSpecBuilder().Build(nil, pkg.Scope().Lookup("X").Type())
+
+	ctrl.AssertEqual(&SimpleSpec{PackageName: "model", TypeName: "Model"}, actualSpec)
+	ctrl.AssertEqual([]*Import{{Namespace: "example.com/model"}}, actualImports)
+}
+
+func TestTypesSpecBuilder_BuildUnderlying(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	pkg := checkTypesPackage(
+		t,
+		"package model\n\nimport \"io\"\n\ntype Model struct {\n\tio.Reader\n\tID int `json:\"id\"`\n}\n\n"+
+			"type Service interface {\n\tio.Closer\n\tFind(id int) *Model\n}\n",
+	)
+
+	actualSpec, actualImports := NewTypesSpecBuilder().BuildUnderlying(pkg, pkg.Scope().Lookup("Model").Type())
+
+	ctrl.AssertEqual(
+		&StructSpec{
+			Fields: []*Field{
+				{Spec: &SimpleSpec{PackageName: "io", TypeName: "Reader"}, IsEmbedded: true},
+				{Name: "ID", Tag: `json:"id"`, Spec: &SimpleSpec{TypeName: "int"}},
+			},
+		},
+		actualSpec,
+	)
+	ctrl.AssertEqual([]*Import{{Namespace: "io"}}, actualImports)
+
+	actualSpec, _ = NewTypesSpecBuilder().BuildUnderlying(pkg, pkg.Scope().Lookup("Service").Type())
+
+	ctrl.AssertEqual(
+		&InterfaceSpec{
+			Fields: []*Field{
+				{Spec: &SimpleSpec{PackageName: "io", TypeName: "Closer"}, IsEmbedded: true},
+				{
+					Name: "Find",
+					Spec: &FuncSpec{
+						Params:  []*Field{{Name: "id", Spec: &SimpleSpec{TypeName: "int"}}},
+						Results: []*Field{{Spec: &SimpleSpec{TypeName: "Model", IsPointer: true}}},
+					},
+				},
+			},
+		},
+		actualSpec,
+	)
+}
+
+func TestTypesSpecBuilder_Build_WithPointerToUnnamed(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	typ := types.NewPointer(types.NewSlice(types.Typ[types.Int]))
+
+	actualSpec, actualImports := NewTypesSpecBuilder().Build(nil, typ)
+
+	ctrl.AssertEqual(&PointerSpec{Value: &ArraySpec{Value: &SimpleSpec{TypeName: "int"}}}, actualSpec)
+	ctrl.AssertEqual([]*Import{}, actualImports)
+}
+
+func TestTypesSpecBuilder_Build_WithChan(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	typ := types.NewChan(types.SendRecv, types.Typ[types.Int])
+
+	ctrl.Subtest("").
+		Call(NewTypesSpecBuilder().Build, nil, typ).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'typ' has not allowed type: chan int"))
+}
+
+func TestTypesSpecBuilder_Build_WithSamePackageNames(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	pkg := checkTypesPackage(
+		t,
+		"package model\n\nimport (\n\thtmltemplate \"html/template\"\n\t\"text/template\"\n)\n\n"+
+			"var X map[*template.Template]*htmltemplate.Template\n",
+	)
+
+	actualSpec, actualImports := NewTypesSpecBuilder().Build(pkg, pkg.Scope().Lookup("X").Type())
+
+	ctrl.AssertEqual(
+		&MapSpec{
+			Key:   &SimpleSpec{PackageName: "template", TypeName: "Template", IsPointer: true},
+			Value: &SimpleSpec{PackageName: "template_2", TypeName: "Template", IsPointer: true},
+		},
+		actualSpec,
+	)
+	ctrl.AssertEqual(
+		[]*Import{{Namespace: "text/template"}, {Namespace: "html/template", Alias: "template_2"}},
+		actualImports,
+	)
+}
+
+func TestTypesSpecBuilder_Build_WithTypeParams(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	pkg := checkTypesPackage(
+		t,
+		"package model\n\ntype Box[T any] struct{ v T }\n\nvar X Box[int]\n\n"+
+			"func F[T any](v T) T { return v }\n",
+	)
+
+	ctrl.Subtest("Instance").
+		Call(NewTypesSpecBuilder().Build, pkg, pkg.Scope().Lookup("X").Type()).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'typ' has not allowed type: example.com/model.Box[int]"))
+	ctrl.Subtest("Func").
+		Call(NewTypesSpecBuilder().Build, pkg, pkg.Scope().Lookup("F").Type()).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'typ' has not allowed type: func[T any](v T) T"))
+	ctrl.Subtest("TypeParam").
+		Call(NewTypesSpecBuilder().BuildUnderlying, pkg, pkg.Scope().Lookup("Box").Type()).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'typ' has not allowed type: struct{v T}"))
+}
+
+func TestTypesSpecBuilder_Build_WithNil(t *testing.T) {
+	ctrl := unit.NewController(t)
+	defer ctrl.Finish()
+
+	ctrl.Subtest("").
+		Call(NewTypesSpecBuilder().Build, nil, nil).
+		ExpectPanic(NewErrorMessageConstraint("Variable 'typ' must be not nil"))
+}
+
+// Type checks source of package with path "example.com/model".
+func checkTypesPackage(t *testing.T, content string) *types.Package {
+	fileSet := token.NewFileSet()
+	astFile, err := parser.ParseFile(fileSet, "model.go", content, 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &types.Config{Importer: importer.ForCompiler(fileSet, "source", nil)}
+	pkg, err := config.Check("example.com/model", fileSet, []*ast.File{astFile}, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pkg
+}
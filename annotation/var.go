@@ -6,6 +6,6 @@ type Var struct {
 	Value       string
 	Comment     string
 	Annotations []interface{}
-	// Allowed types: *SimpleSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec.
+	// Allowed types: *SimpleSpec, *PointerSpec, *ArraySpec, *MapSpec, *StructSpec, *InterfaceSpec, *FuncSpec.
 	Spec interface{}
 }
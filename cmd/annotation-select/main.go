@@ -0,0 +1,79 @@
+// Command annotation-select prints entities of scanned sources, which are matched by selector.
+//
+// Usage:
+//
+//	annotation-select -namespace github.com/my/project -path . 'github.com/my/project/...:Type[@Entity].Field'
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/index0h/go-annotation/annotation"
+)
+
+func main() {
+	namespace := flag.String("namespace", "", "Namespace of root path, e.g. module name")
+	rootPath := flag.String("path", ".", "Root path of sources")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] selector\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if *namespace == "" || flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	application := annotation.NewApplication()
+	storage := application.Storage()
+
+	application.Scan(*namespace, *rootPath)
+
+	result, err := application.Selector().Select(storage, flag.Arg(0))
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	for _, element := range result {
+		fileName := ""
+
+		if element.File != nil {
+			fileName = element.File.Name
+		}
+
+		fmt.Printf("%s\t%s\n", filepath.Join(element.Namespace.Path, fileName), describe(element))
+	}
+}
+
+func describe(element *annotation.SelectedEntity) string {
+	switch entity := element.Entity.(type) {
+	case *annotation.File:
+		return "File " + entity.Name
+	case *annotation.Import:
+		return "Import " + entity.Namespace
+	case *annotation.Const:
+		return "Const " + entity.Name
+	case *annotation.Var:
+		return "Var " + entity.Name
+	case *annotation.Type:
+		return "Type " + entity.Name
+	case *annotation.Func:
+		if entity.Related != nil {
+			return "Method " + entity.Name
+		}
+
+		return "Func " + entity.Name
+	case *annotation.Field:
+		return "Field " + entity.Name
+	default:
+		return fmt.Sprintf("%T", entity)
+	}
+}